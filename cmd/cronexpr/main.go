@@ -0,0 +1,123 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/blend/go-sdk/cron"
+	"github.com/blend/go-sdk/ex"
+)
+
+// linker metadata block
+// this block must be present
+// it is used by goreleaser
+var (
+	version = "dev"
+	commit  = "none"
+	date    = "unknown"
+)
+
+var (
+	flagCount      *int
+	flagAfter      *string
+	flagLocation   *string
+	flagTimeFormat *string
+)
+
+func command() *cobra.Command {
+	root := &cobra.Command{
+		Use:   "cronexpr [cron string]",
+		Short: "Validate cron strings and show when they run.",
+		Long:  "Validate a cron string, describe it, and print the next times it runs.",
+		Example: `
+# Show the next 10 runs of a schedule
+cronexpr "0 0 9 * * MON-FRI"
+
+# Show the next 3 runs after a given time, in a given location
+cronexpr -n 3 --after=2021-01-01T00:00:00Z --location=America/New_York "0 0 12 ? * 5L"
+`,
+		Args: cobra.MinimumNArgs(1),
+	}
+	flagCount = root.Flags().IntP("count", "n", 10, "The number of runs to show")
+	flagAfter = root.Flags().String("after", "", "Show runs after a given time (RFC3339), defaults to now")
+	flagLocation = root.Flags().String("location", "", "The location to show runs in (e.g. America/New_York), defaults to the local time zone")
+	flagTimeFormat = root.Flags().String("time-format", "Mon 2006-01-02 15:04:05 MST", "The run time format")
+	return root
+}
+
+func main() {
+	cmd := command()
+	cmd.Run = func(parent *cobra.Command, args []string) {
+		opts, err := parseOptions()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "%+v\n", err)
+			os.Exit(1)
+		}
+		// cron strings are usually quoted, but don't have to be.
+		if err := describe(strings.Join(args, " "), opts, os.Stdout); err != nil {
+			fmt.Fprintf(os.Stderr, "%v\n", err)
+			os.Exit(1)
+		}
+	}
+	if err := cmd.Execute(); err != nil {
+		os.Exit(1)
+	}
+}
+
+// options are the options for describing a cron string.
+//
+// Runs are computed after the given time in UTC, as the job scheduler does, and the location
+// is only used to show them.
+type options struct {
+	Count      int
+	After      time.Time
+	Location   *time.Location
+	TimeFormat string
+}
+
+func parseOptions() (opts options, err error) {
+	opts.Count = *flagCount
+	opts.TimeFormat = *flagTimeFormat
+	opts.Location = time.Local
+	if *flagLocation != "" {
+		if opts.Location, err = time.LoadLocation(*flagLocation); err != nil {
+			return opts, ex.New(err, ex.OptMessage("invalid --location"))
+		}
+	}
+	opts.After = time.Now().UTC()
+	if *flagAfter != "" {
+		if opts.After, err = time.Parse(time.RFC3339Nano, *flagAfter); err != nil {
+			return opts, ex.New(err, ex.OptMessage("invalid --after"))
+		}
+		opts.After = opts.After.UTC()
+	}
+	return opts, nil
+}
+
+// describe validates a cron string, and writes its description and next runs to an output.
+func describe(cronString string, opts options, output io.Writer) error {
+	schedule, err := cron.ParseString(cronString)
+	if err != nil {
+		return err
+	}
+	description, err := cron.DescribeString(cronString)
+	if err != nil {
+		return err
+	}
+	fmt.Fprintln(output, description)
+	runs := cron.NextRuns(schedule, opts.After.UTC(), opts.Count)
+	if len(runs) == 0 {
+		fmt.Fprintln(output, "No upcoming runs")
+		return nil
+	}
+	fmt.Fprintln(output)
+	for _, run := range runs {
+		fmt.Fprintln(output, run.In(opts.Location).Format(opts.TimeFormat))
+	}
+	return nil
+}
@@ -0,0 +1,80 @@
+package main
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/blend/go-sdk/assert"
+	"github.com/blend/go-sdk/cron"
+	"github.com/blend/go-sdk/ex"
+)
+
+func TestDescribe(t *testing.T) {
+	assert := assert.New(t)
+
+	opts := options{
+		Count:      3,
+		After:      time.Date(2021, 01, 01, 0, 0, 0, 0, time.UTC),
+		Location:   time.UTC,
+		TimeFormat: time.RFC3339,
+	}
+
+	output := new(bytes.Buffer)
+	assert.Nil(describe("0 0 12 ? * 5L", opts, output))
+	assert.Equal(`At 12:00, on the last Friday of the month
+
+2021-01-29T12:00:00Z
+2021-02-26T12:00:00Z
+2021-03-26T12:00:00Z
+`, output.String())
+
+	output.Reset()
+	assert.Nil(describe("0 0 0 1 1 * 2020", opts, output))
+	assert.Equal("At 00:00, on day 1 of the month, in January, in 2020\nNo upcoming runs\n", output.String())
+
+	output.Reset()
+	err := describe("0 0 12 * * 1#6", opts, output)
+	assert.True(ex.Is(err, cron.ErrStringScheduleInvalid))
+	assert.Empty(output.String())
+}
+
+func TestDescribeLocation(t *testing.T) {
+	assert := assert.New(t)
+
+	location, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Skip("zoneinfo is not available")
+	}
+	opts := options{
+		Count:      2,
+		After:      time.Date(2021, 01, 01, 0, 0, 0, 0, location),
+		Location:   location,
+		TimeFormat: time.RFC3339,
+	}
+
+	// runs are computed in utc, and shown in the location.
+	output := new(bytes.Buffer)
+	assert.Nil(describe("0 0 12 * * *", opts, output))
+	assert.Equal(`At 12:00
+
+2021-01-01T07:00:00-05:00
+2021-01-02T07:00:00-05:00
+`, output.String())
+}
+
+func TestParseOptions(t *testing.T) {
+	assert := assert.New(t)
+
+	cmd := command()
+	assert.Nil(cmd.Flags().Set("location", "UTC"))
+	assert.Nil(cmd.Flags().Set("after", "2021-01-01T00:00:00-05:00"))
+	opts, err := parseOptions()
+	assert.Nil(err)
+	assert.Equal(time.UTC, opts.After.Location())
+	assert.Equal(time.Date(2021, 01, 01, 5, 0, 0, 0, time.UTC), opts.After)
+
+	assert.Nil(cmd.Flags().Set("after", "tomorrow"))
+	_, err = parseOptions()
+	assert.NotNil(err)
+}
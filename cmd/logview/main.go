@@ -0,0 +1,465 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/blend/go-sdk/ansi"
+	"github.com/blend/go-sdk/ex"
+	"github.com/blend/go-sdk/logger"
+	"github.com/blend/go-sdk/selector"
+	"github.com/blend/go-sdk/timeutil"
+)
+
+// linker metadata block
+// this block must be present
+// it is used by goreleaser
+var (
+	version = "dev"
+	commit  = "none"
+	date    = "unknown"
+)
+
+var (
+	flagFlags         *[]string
+	flagPath          *string
+	flagLabels        *[]string
+	flagSelector      *string
+	flagAfter         *string
+	flagBefore        *string
+	flagSince         *time.Duration
+	flagFollow        *bool
+	flagNoColor       *bool
+	flagHideTimestamp *bool
+	flagTimeFormat    *string
+	flagSkipInvalid   *bool
+)
+
+// followPollInterval is how often a followed file is checked for new lines.
+const followPollInterval = 250 * time.Millisecond
+
+func command() *cobra.Command {
+	root := &cobra.Command{
+		Use:   "logview [files...]",
+		Short: "Pretty print and filter json log streams.",
+		Long:  "Pretty print and filter newline delimited json logger output, read from files or from stdin.",
+		Example: `
+# Pretty print the logs of a running app
+./app | logview
+
+# Show error and fatal events for a given scope path
+logview --flag=error,fatal --path=api/users app.log
+
+# Show events with a label selector from the last hour
+logview --selector="env=prod,team in (payments,search)" --since=1h app.log
+
+# Follow a file, showing new events as they are written
+logview -f app.log
+`,
+	}
+	flagFlags = root.Flags().StringSlice("flag", nil, "Event flags to show (can be comma separated or repeated)")
+	flagPath = root.Flags().String("path", "", "A scope path prefix events must have, separated by '/' (e.g. api/users)")
+	flagLabels = root.Flags().StringSlice("label", nil, "A label value events must have as key=value (can be repeated)")
+	flagSelector = root.Flags().String("selector", "", "A label selector expression events must match")
+	flagAfter = root.Flags().String("after", "", "Show events after a given time (RFC3339)")
+	flagBefore = root.Flags().String("before", "", "Show events before a given time (RFC3339)")
+	flagSince = root.Flags().Duration("since", 0, "Show events in the last duration (e.g. 15m)")
+	flagFollow = root.Flags().BoolP("follow", "f", false, "Keep reading a file as new lines are written to it")
+	flagNoColor = root.Flags().Bool("no-color", false, "Disable colorized output")
+	flagHideTimestamp = root.Flags().Bool("hide-timestamp", false, "Hide event timestamps")
+	flagTimeFormat = root.Flags().String("time-format", logger.DefaultTextTimeFormat, "The timestamp format")
+	flagSkipInvalid = root.Flags().Bool("skip-invalid", false, "Skip lines that are not json events instead of printing them as is")
+	return root
+}
+
+func main() {
+	cmd := command()
+	cmd.Run = func(parent *cobra.Command, args []string) {
+		f, err := parseFilter()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "%+v\n", err)
+			os.Exit(1)
+		}
+		if *flagFollow && len(args) > 1 {
+			fmt.Fprintln(os.Stderr, "--follow can only be used with a single file")
+			os.Exit(1)
+		}
+
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		go func() {
+			signals := make(chan os.Signal, 1)
+			signal.Notify(signals, os.Interrupt)
+			<-signals
+			cancel()
+		}()
+
+		v := viewer{
+			Filter:      f,
+			SkipInvalid: *flagSkipInvalid,
+			Formatter:   formatter(),
+		}
+		if len(args) == 0 {
+			args = []string{"-"}
+		}
+		for _, path := range args {
+			if err := viewFile(ctx, v, path, *flagFollow, os.Stdout); err != nil {
+				fmt.Fprintf(os.Stderr, "%+v\n", err)
+				os.Exit(1)
+			}
+		}
+	}
+	if err := cmd.Execute(); err != nil {
+		os.Exit(1)
+	}
+}
+
+func formatter() *logger.TextOutputFormatter {
+	options := []logger.TextOutputFormatterOption{
+		logger.OptTextTimeFormat(*flagTimeFormat),
+	}
+	if *flagNoColor {
+		options = append(options, logger.OptTextNoColor())
+	}
+	if *flagHideTimestamp {
+		options = append(options, logger.OptTextHideTimestamp())
+	}
+	return logger.NewTextOutputFormatter(options...)
+}
+
+func parseFilter() (f filter, err error) {
+	for _, flag := range *flagFlags {
+		if flag = strings.ToLower(strings.TrimSpace(flag)); flag != "" {
+			f.Flags = append(f.Flags, flag)
+		}
+	}
+	for _, segment := range strings.Split(*flagPath, "/") {
+		if segment != "" {
+			f.Path = append(f.Path, segment)
+		}
+	}
+	for _, label := range *flagLabels {
+		parts := strings.SplitN(label, "=", 2)
+		if len(parts) != 2 || parts[0] == "" {
+			return f, ex.New("invalid label; should be in the form key=value", ex.OptMessage(label))
+		}
+		if f.Labels == nil {
+			f.Labels = make(logger.Labels)
+		}
+		f.Labels[parts[0]] = parts[1]
+	}
+	if *flagSelector != "" {
+		if f.Selector, err = selector.Parse(*flagSelector); err != nil {
+			return
+		}
+	}
+	if *flagAfter != "" {
+		if f.After, err = time.Parse(time.RFC3339Nano, *flagAfter); err != nil {
+			return f, ex.New(err, ex.OptMessage("invalid --after"))
+		}
+	}
+	if *flagBefore != "" {
+		if f.Before, err = time.Parse(time.RFC3339Nano, *flagBefore); err != nil {
+			return f, ex.New(err, ex.OptMessage("invalid --before"))
+		}
+	}
+	if *flagSince > 0 {
+		f.After = time.Now().UTC().Add(-*flagSince)
+	}
+	return f, nil
+}
+
+// viewFile views a file, or stdin if the path is "-".
+func viewFile(ctx context.Context, v viewer, path string, follow bool, output io.Writer) error {
+	if path == "-" {
+		return v.View(ctx, os.Stdin, output)
+	}
+	f, err := os.Open(path)
+	if err != nil {
+		return ex.New(err)
+	}
+	defer f.Close()
+	if follow {
+		return v.View(ctx, &followReader{ctx: ctx, Reader: f, Interval: followPollInterval}, output)
+	}
+	return v.View(ctx, f, output)
+}
+
+// viewer reads json events line by line and writes the events that match a filter.
+type viewer struct {
+	Filter      filter
+	SkipInvalid bool
+	Formatter   logger.WriteFormatter
+}
+
+// View reads lines from an input until it is exhausted or the context is cancelled.
+func (v viewer) View(ctx context.Context, input io.Reader, output io.Writer) error {
+	reader := bufio.NewReader(input)
+	for {
+		line, err := reader.ReadBytes('\n')
+		if len(line) > 0 {
+			if writeErr := v.WriteLine(output, line); writeErr != nil {
+				return writeErr
+			}
+		}
+		if err == io.EOF || ctx.Err() != nil {
+			return nil
+		}
+		if err != nil {
+			return ex.New(err)
+		}
+	}
+}
+
+// WriteLine writes a line if it is an event that matches the filter.
+// Lines that are not json events are written as is unless they are skipped.
+func (v viewer) WriteLine(output io.Writer, line []byte) error {
+	line = bytes.TrimSpace(line)
+	if len(line) == 0 {
+		return nil
+	}
+	e, err := parseEvent(line)
+	if err != nil {
+		if v.SkipInvalid {
+			return nil
+		}
+		_, err = fmt.Fprintf(output, "%s\n", line)
+		return err
+	}
+	if !v.Filter.Matches(e) {
+		return nil
+	}
+	return v.Formatter.WriteFormat(e.Context(), output, e)
+}
+
+// followReader is a reader that waits for more data at the end of its input
+// until its context is cancelled, like `tail -f`.
+type followReader struct {
+	io.Reader
+	ctx      context.Context
+	Interval time.Duration
+}
+
+// Read implements io.Reader.
+func (fr *followReader) Read(p []byte) (int, error) {
+	for {
+		n, err := fr.Reader.Read(p)
+		if n > 0 || (err != nil && err != io.EOF) {
+			return n, err
+		}
+		select {
+		case <-fr.ctx.Done():
+			return 0, io.EOF
+		case <-time.After(fr.Interval):
+		}
+	}
+}
+
+// filter matches parsed events.
+type filter struct {
+	Flags    []string
+	Path     []string
+	Labels   logger.Labels
+	Selector selector.Selector
+	After    time.Time
+	Before   time.Time
+}
+
+// Matches returns if an event matches the filter.
+func (f filter) Matches(e *jsonEvent) bool {
+	if len(f.Flags) > 0 {
+		var matched bool
+		for _, flag := range f.Flags {
+			if flag == e.Flag {
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			return false
+		}
+	}
+	if len(f.Path) > 0 {
+		if len(e.Path) < len(f.Path) {
+			return false
+		}
+		for index := range f.Path {
+			if e.Path[index] != f.Path[index] {
+				return false
+			}
+		}
+	}
+	for key, value := range f.Labels {
+		if actual, ok := e.Labels[key]; !ok || actual != value {
+			return false
+		}
+	}
+	if f.Selector != nil && !f.Selector.Matches(e.Labels) {
+		return false
+	}
+	if !f.After.IsZero() && e.Timestamp.Before(f.After) {
+		return false
+	}
+	if !f.Before.IsZero() && e.Timestamp.After(f.Before) {
+		return false
+	}
+	return true
+}
+
+var (
+	_ logger.Event             = (*jsonEvent)(nil)
+	_ logger.TimestampProvider = (*jsonEvent)(nil)
+	_ logger.TextWritable      = (*jsonEvent)(nil)
+)
+
+// jsonEvent is an event parsed from `logger.JSONOutputFormatter` output.
+type jsonEvent struct {
+	Flag        string
+	Timestamp   time.Time
+	Path        []string
+	Labels      logger.Labels
+	Annotations logger.Annotations
+	TraceID     string
+	SpanID      string
+	Fields      map[string]interface{}
+}
+
+// parseEvent parses an event from a json line; the line must be an object with a flag.
+func parseEvent(line []byte) (*jsonEvent, error) {
+	var raw struct {
+		Flag        string                 `json:"flag"`
+		Timestamp   time.Time              `json:"_timestamp"`
+		Path        []string               `json:"scope_path"`
+		Labels      map[string]string      `json:"labels"`
+		Annotations map[string]interface{} `json:"annotations"`
+		TraceID     string                 `json:"trace_id"`
+		SpanID      string                 `json:"span_id"`
+	}
+	if err := json.Unmarshal(line, &raw); err != nil {
+		return nil, ex.New(err)
+	}
+	if raw.Flag == "" {
+		return nil, ex.New("line is not a logger event; it is missing a flag")
+	}
+	fields := make(map[string]interface{})
+	if err := json.Unmarshal(line, &fields); err != nil {
+		return nil, ex.New(err)
+	}
+	for _, key := range []string{
+		logger.FieldFlag,
+		logger.FieldTimestamp,
+		logger.FieldScopePath,
+		logger.FieldLabels,
+		logger.FieldAnnotations,
+		logger.FieldTraceID,
+		logger.FieldSpanID,
+	} {
+		delete(fields, key)
+	}
+	return &jsonEvent{
+		Flag:        raw.Flag,
+		Timestamp:   raw.Timestamp,
+		Path:        raw.Path,
+		Labels:      raw.Labels,
+		Annotations: raw.Annotations,
+		TraceID:     raw.TraceID,
+		SpanID:      raw.SpanID,
+		Fields:      fields,
+	}, nil
+}
+
+// Context returns a context with the scope fields of the event.
+func (e *jsonEvent) Context() context.Context {
+	ctx := context.Background()
+	if len(e.Path) > 0 {
+		ctx = logger.WithPath(ctx, e.Path...)
+	}
+	if len(e.Labels) > 0 {
+		ctx = logger.WithLabels(ctx, e.Labels)
+	}
+	if len(e.Annotations) > 0 {
+		ctx = logger.WithAnnotations(ctx, e.Annotations)
+	}
+	if e.TraceID != "" || e.SpanID != "" {
+		ctx = logger.WithTrace(ctx, e.TraceID, e.SpanID)
+	}
+	return ctx
+}
+
+// GetFlag implements logger.Event.
+func (e *jsonEvent) GetFlag() string { return e.Flag }
+
+// GetTimestamp implements logger.TimestampProvider.
+func (e *jsonEvent) GetTimestamp() time.Time { return e.Timestamp }
+
+// WriteText implements logger.TextWritable.
+//
+// Message events are written as their text, error events as their error,
+// and any other fields as sorted key=value pairs.
+func (e *jsonEvent) WriteText(tf logger.TextFormatter, wr io.Writer) {
+	var parts []string
+	if text, ok := e.Fields[logger.FieldText].(string); ok {
+		parts = append(parts, text)
+	}
+	if err, ok := e.Fields["err"]; ok {
+		parts = append(parts, formatError(err))
+	}
+	if elapsed, ok := e.Fields[logger.FieldElapsed].(float64); ok && elapsed > 0 {
+		parts = append(parts, "("+timeutil.FromMilliseconds(elapsed).String()+")")
+	}
+
+	var keys []string
+	for key := range e.Fields {
+		if key == logger.FieldText || key == logger.FieldElapsed || key == "err" {
+			continue
+		}
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		parts = append(parts, tf.Colorize(key, ansi.ColorLightBlack)+"="+formatValue(e.Fields[key]))
+	}
+	io.WriteString(wr, strings.Join(parts, logger.Space))
+}
+
+// formatError formats an error field, which is either a string or a decomposed `ex.Ex`.
+func formatError(err interface{}) string {
+	if typed, ok := err.(string); ok {
+		return typed
+	}
+	contents, marshalErr := json.Marshal(err)
+	if marshalErr != nil {
+		return fmt.Sprint(err)
+	}
+	exception := new(ex.Ex)
+	if unmarshalErr := json.Unmarshal(contents, exception); unmarshalErr != nil || exception.Class == nil {
+		return string(contents)
+	}
+	return fmt.Sprintf("%+v", exception)
+}
+
+func formatValue(value interface{}) string {
+	switch typed := value.(type) {
+	case string:
+		return typed
+	case float64:
+		return fmt.Sprint(typed)
+	default:
+		contents, err := json.Marshal(typed)
+		if err != nil {
+			return fmt.Sprint(typed)
+		}
+		return string(contents)
+	}
+}
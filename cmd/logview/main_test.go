@@ -0,0 +1,126 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/blend/go-sdk/assert"
+	"github.com/blend/go-sdk/logger"
+	"github.com/blend/go-sdk/selector"
+)
+
+func jsonLines(events ...logger.EventWithContext) string {
+	buffer := new(bytes.Buffer)
+	formatter := logger.NewJSONOutputFormatter()
+	for _, ewc := range events {
+		_ = formatter.WriteFormat(ewc.Context, buffer, ewc.Event)
+	}
+	return buffer.String()
+}
+
+func TestParseEvent(t *testing.T) {
+	assert := assert.New(t)
+
+	ts := time.Date(2020, 01, 02, 03, 04, 05, 0, time.UTC)
+	ctx := logger.WithTimestamp(context.Background(), ts)
+	ctx = logger.WithPath(ctx, "api", "users")
+	ctx = logger.WithLabels(ctx, logger.Labels{"env": "prod"})
+	ctx = logger.WithTrace(ctx, "trace", "span")
+
+	e, err := parseEvent([]byte(jsonLines(logger.EventWithContext{Context: ctx, Event: logger.NewMessageEvent(logger.Info, "hello")})))
+	assert.Nil(err)
+	assert.Equal(logger.Info, e.Flag)
+	assert.Equal(ts, e.Timestamp.UTC())
+	assert.Equal([]string{"api", "users"}, e.Path)
+	assert.Equal("prod", e.Labels["env"])
+	assert.Equal("trace", e.TraceID)
+	assert.Equal(map[string]interface{}{logger.FieldText: "hello"}, e.Fields)
+
+	_, err = parseEvent([]byte("not json"))
+	assert.NotNil(err)
+	_, err = parseEvent([]byte(`{"text":"no flag"}`))
+	assert.NotNil(err)
+}
+
+func TestFilterMatches(t *testing.T) {
+	assert := assert.New(t)
+
+	ts := time.Date(2020, 01, 02, 03, 04, 05, 0, time.UTC)
+	e := &jsonEvent{Flag: logger.Info, Timestamp: ts, Path: []string{"api", "users"}, Labels: logger.Labels{"env": "prod", "team": "payments"}}
+
+	assert.True(filter{}.Matches(e))
+	assert.True(filter{Flags: []string{logger.Error, logger.Info}}.Matches(e))
+	assert.False(filter{Flags: []string{logger.Error}}.Matches(e))
+	assert.True(filter{Path: []string{"api"}}.Matches(e))
+	assert.False(filter{Path: []string{"api", "users", "list"}}.Matches(e))
+	assert.False(filter{Path: []string{"cron"}}.Matches(e))
+	assert.True(filter{Labels: logger.Labels{"env": "prod"}}.Matches(e))
+	assert.False(filter{Labels: logger.Labels{"env": "dev"}}.Matches(e))
+	assert.True(filter{After: ts.Add(-time.Second), Before: ts.Add(time.Second)}.Matches(e))
+	assert.False(filter{After: ts.Add(time.Second)}.Matches(e))
+	assert.False(filter{Before: ts.Add(-time.Second)}.Matches(e))
+
+	sel, err := selector.Parse("env=prod,team in (payments,search)")
+	assert.Nil(err)
+	assert.True(filter{Selector: sel}.Matches(e))
+	sel, err = selector.Parse("env!=prod")
+	assert.Nil(err)
+	assert.False(filter{Selector: sel}.Matches(e))
+}
+
+func TestViewerView(t *testing.T) {
+	assert := assert.New(t)
+
+	input := jsonLines(
+		logger.EventWithContext{Context: logger.WithPath(context.Background(), "api"), Event: logger.NewMessageEvent(logger.Info, "api info")},
+		logger.EventWithContext{Context: context.Background(), Event: logger.NewMessageEvent(logger.Debug, "debug message")},
+		logger.EventWithContext{Context: logger.WithLabels(context.Background(), logger.Labels{"env": "prod"}), Event: logger.NewErrorEvent(logger.Error, errTest("bad things"))},
+	) + "plain text line\n"
+
+	v := viewer{
+		Formatter: logger.NewTextOutputFormatter(logger.OptTextNoColor(), logger.OptTextHideTimestamp()),
+	}
+	output := new(bytes.Buffer)
+	assert.Nil(v.View(context.Background(), strings.NewReader(input), output))
+	assert.Equal("[api] [info] api info\n[debug] debug message\n[error] bad things\tenv=prod\nplain text line\n", output.String())
+
+	v.Filter = filter{Flags: []string{logger.Error}}
+	v.SkipInvalid = true
+	output.Reset()
+	assert.Nil(v.View(context.Background(), strings.NewReader(input), output))
+	assert.Equal("[error] bad things\tenv=prod\n", output.String())
+}
+
+func TestJSONEventWriteText(t *testing.T) {
+	assert := assert.New(t)
+
+	e := &jsonEvent{Flag: "http.request", Fields: map[string]interface{}{
+		"verb":       "GET",
+		"statusCode": float64(200),
+		"elapsed":    float64(1500),
+	}}
+	output := new(bytes.Buffer)
+	e.WriteText(logger.NewTextOutputFormatter(logger.OptTextNoColor()), output)
+	assert.Equal("(1.5s) statusCode=200 verb=GET", output.String())
+}
+
+func TestViewerViewMessageElapsed(t *testing.T) {
+	assert := assert.New(t)
+
+	input := jsonLines(
+		logger.EventWithContext{Context: context.Background(), Event: logger.NewMessageEvent(logger.Info, "done", logger.OptMessageElapsed(1500*time.Millisecond))},
+	)
+	v := viewer{
+		Formatter: logger.NewTextOutputFormatter(logger.OptTextNoColor(), logger.OptTextHideTimestamp()),
+	}
+	output := new(bytes.Buffer)
+	assert.Nil(v.View(context.Background(), strings.NewReader(input), output))
+	assert.Equal("[info] done (1.5s)\n", output.String())
+}
+
+type errTest string
+
+func (e errTest) Error() string { return string(e) }
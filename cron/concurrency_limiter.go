@@ -0,0 +1,47 @@
+package cron
+
+import "sync"
+
+// NewConcurrencyLimiter returns a new concurrency limiter for a maximum number of running invocations.
+func NewConcurrencyLimiter(max int) *ConcurrencyLimiter {
+	return &ConcurrencyLimiter{
+		Max: max,
+	}
+}
+
+// ConcurrencyLimiter limits the number of invocations that can run concurrently across the job
+// schedulers it is shared by (e.g. all the jobs of a job manager).
+type ConcurrencyLimiter struct {
+	// Max is the maximum number of running invocations; if it is unset there is no limit.
+	Max int
+
+	mu      sync.Mutex
+	running int
+}
+
+// TryAcquire acquires a place for an invocation, returning false if the limit has been reached.
+func (cl *ConcurrencyLimiter) TryAcquire() bool {
+	cl.mu.Lock()
+	defer cl.mu.Unlock()
+	if cl.Max > 0 && cl.running >= cl.Max {
+		return false
+	}
+	cl.running++
+	return true
+}
+
+// Release releases a place acquired with `TryAcquire`.
+func (cl *ConcurrencyLimiter) Release() {
+	cl.mu.Lock()
+	defer cl.mu.Unlock()
+	if cl.running > 0 {
+		cl.running--
+	}
+}
+
+// Running returns the number of running invocations.
+func (cl *ConcurrencyLimiter) Running() int {
+	cl.mu.Lock()
+	defer cl.mu.Unlock()
+	return cl.running
+}
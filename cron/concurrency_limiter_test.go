@@ -0,0 +1,314 @@
+package cron
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/blend/go-sdk/assert"
+	"github.com/blend/go-sdk/ex"
+	"github.com/blend/go-sdk/logger"
+	"github.com/blend/go-sdk/ref"
+)
+
+func TestConcurrencyLimiter(t *testing.T) {
+	assert := assert.New(t)
+
+	limiter := NewConcurrencyLimiter(2)
+	assert.True(limiter.TryAcquire())
+	assert.True(limiter.TryAcquire())
+	assert.False(limiter.TryAcquire())
+	assert.Equal(2, limiter.Running())
+	limiter.Release()
+	assert.True(limiter.TryAcquire())
+
+	unlimited := NewConcurrencyLimiter(0)
+	for x := 0; x < 10; x++ {
+		assert.True(unlimited.TryAcquire())
+	}
+}
+
+func newBlockingJob(name string, options ...JobBuilderOption) (*JobBuilder, chan struct{}) {
+	started := make(chan struct{}, 10)
+	return NewJob(append([]JobBuilderOption{
+		OptJobName(name),
+		OptJobAction(func(ctx context.Context) error {
+			started <- struct{}{}
+			<-ctx.Done()
+			// report the cancellation, so the invocation is cancelled whether or not the scheduler sees it first.
+			return ex.New(ErrJobCancelled)
+		}),
+	}, options...)...), started
+}
+
+func TestJobSchedulerConcurrencyPolicyForbid(t *testing.T) {
+	assert := assert.New(t)
+
+	job, started := newBlockingJob("forbid")
+	js := NewJobScheduler(job)
+	ji, done, err := js.RunAsync()
+	assert.Nil(err)
+	<-started
+
+	assert.False(js.CanBeScheduled())
+	_, _, err = js.RunAsync()
+	assert.True(IsJobAlreadyRunning(err))
+
+	ji.Cancel()
+	<-done
+	assert.True(js.CanBeScheduled())
+}
+
+type blockingJobLocker struct {
+	locking chan struct{}
+	proceed chan struct{}
+	err     error
+}
+
+func (bjl *blockingJobLocker) Lock(_ context.Context, _ string, _ time.Time, _ time.Duration) (JobLease, error) {
+	bjl.locking <- struct{}{}
+	<-bjl.proceed
+	return nil, bjl.err
+}
+
+func TestJobSchedulerConcurrencyPolicyForbidWhileLocking(t *testing.T) {
+	assert := assert.New(t)
+
+	locker := &blockingJobLocker{locking: make(chan struct{}, 2), proceed: make(chan struct{})}
+	job, started := newBlockingJob("forbid", OptJobConfig(JobConfig{DistributedLock: ref.Bool(true)}))
+	js := NewJobScheduler(job, OptJobSchedulerLocker(locker))
+
+	type result struct {
+		ji   *JobInvocation
+		done <-chan struct{}
+		err  error
+	}
+	first := make(chan result)
+	go func() {
+		ji, done, err := js.RunAsync()
+		first <- result{ji, done, err}
+	}()
+	<-locker.locking
+
+	// the first invocation has reserved its slot while it acquires its lease.
+	assert.False(js.CanBeScheduled())
+	_, _, err := js.RunAsync()
+	assert.True(IsJobAlreadyRunning(err))
+
+	close(locker.proceed)
+	running := <-first
+	assert.Nil(running.err)
+	<-started
+	running.ji.Cancel()
+	<-running.done
+	assert.True(js.CanBeScheduled())
+}
+
+func TestJobSchedulerLockErrorReleasesSlot(t *testing.T) {
+	assert := assert.New(t)
+
+	locker := &blockingJobLocker{locking: make(chan struct{}, 2), proceed: make(chan struct{}), err: fmt.Errorf("this is only a test")}
+	close(locker.proceed)
+	limiter := NewConcurrencyLimiter(1)
+	job, _ := newBlockingJob("forbid", OptJobConfig(JobConfig{DistributedLock: ref.Bool(true)}))
+	js := NewJobScheduler(job, OptJobSchedulerLocker(locker), OptJobSchedulerConcurrencyLimiter(limiter))
+
+	_, _, err := js.RunAsync()
+	assert.NotNil(err)
+	assert.True(js.CanBeScheduled())
+	assert.Zero(limiter.Running())
+}
+
+func TestJobSchedulerConcurrencyPolicyAllow(t *testing.T) {
+	assert := assert.New(t)
+
+	job, started := newBlockingJob("allow", OptJobConcurrencyPolicy(ConcurrencyPolicyAllow), OptJobMaxConcurrency(2))
+	js := NewJobScheduler(job)
+	first, firstDone, err := js.RunAsync()
+	assert.Nil(err)
+	<-started
+	second, secondDone, err := js.RunAsync()
+	assert.Nil(err)
+	<-started
+
+	running := js.Running()
+	assert.Len(running, 2)
+	assert.Equal(first.ID, running[0].ID)
+	assert.Equal(second.ID, running[1].ID)
+	assert.Equal(second.ID, js.Current().ID)
+
+	assert.False(js.CanBeScheduled())
+	_, _, err = js.RunAsync()
+	assert.True(IsJobAlreadyRunning(err))
+
+	first.Cancel()
+	<-firstDone
+	assert.Len(js.Running(), 1)
+	assert.Equal(first.ID, js.Last().ID)
+	assert.True(js.CanBeScheduled())
+
+	assert.Nil(js.Cancel())
+	<-secondDone
+	assert.True(js.IsIdle())
+	assert.Equal(JobInvocationStatusCancelled, js.Last().Status)
+}
+
+func TestJobSchedulerConcurrencyPolicyReplace(t *testing.T) {
+	assert := assert.New(t)
+
+	replaced := make(chan Event, 1)
+	log := logger.All(logger.OptOutput(ioutil.Discard))
+	defer log.Close()
+	log.Listen(FlagReplaced, "test", NewEventListener(func(_ context.Context, e Event) { replaced <- e }))
+
+	job, started := newBlockingJob("replace", OptJobConcurrencyPolicy(ConcurrencyPolicyReplace))
+	js := NewJobScheduler(job, OptJobSchedulerLog(log))
+	first, firstDone, err := js.RunAsync()
+	assert.Nil(err)
+	<-started
+
+	assert.True(js.CanBeScheduled())
+	second, secondDone, err := js.RunAsync()
+	assert.Nil(err)
+	<-started
+
+	// the first invocation is complete before the second starts.
+	select {
+	case <-firstDone:
+	default:
+		assert.FailNow("the replaced invocation should be complete")
+	}
+	assert.Equal(first.ID, js.Last().ID)
+	assert.Equal(JobInvocationStatusCancelled, js.Last().Status)
+	assert.Equal(second.ID, js.Current().ID)
+	assert.Equal(first.ID, (<-replaced).JobInvocation)
+
+	second.Cancel()
+	<-secondDone
+}
+
+func TestJobSchedulerConcurrencyPolicyReplaceConcurrent(t *testing.T) {
+	assert := assert.New(t)
+
+	const triggers = 8
+	for trial := 0; trial < 50; trial++ {
+		job, _ := newBlockingJob("replace", OptJobConcurrencyPolicy(ConcurrencyPolicyReplace))
+		js := NewJobScheduler(job)
+
+		var wg sync.WaitGroup
+		start := make(chan struct{})
+		dones := make(chan (<-chan struct{}), triggers)
+		for x := 0; x < triggers; x++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				<-start
+				_, done, err := js.RunAsync()
+				assert.Nil(err)
+				dones <- done
+			}()
+		}
+		close(start)
+		wg.Wait()
+		close(dones)
+
+		// every invocation but the last to start has been replaced and is complete.
+		var running int
+		for done := range dones {
+			select {
+			case <-done:
+			default:
+				running++
+			}
+		}
+		assert.Equal(1, running)
+		assert.Len(js.Running(), 1)
+
+		assert.Nil(js.Cancel())
+		for !js.IsIdle() {
+			time.Sleep(time.Millisecond)
+		}
+	}
+}
+
+func TestJobSchedulerConcurrencyPolicyReplaceCancelled(t *testing.T) {
+	assert := assert.New(t)
+
+	release := make(chan struct{})
+	job, started := newBlockingJob("replace",
+		OptJobConcurrencyPolicy(ConcurrencyPolicyReplace),
+		OptJobOnCancellation(func(_ context.Context) { <-release }), // the replaced invocation is slow to complete
+	)
+	js := NewJobScheduler(job)
+	first, firstDone, err := js.RunAsync()
+	assert.Nil(err)
+	<-started
+
+	// the context ends before the first invocation completes, so the second doesn't start.
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	_, _, err = js.RunAsyncContext(ctx)
+	assert.True(IsJobCancelled(err))
+	running := js.Running()
+	assert.Len(running, 1)
+	assert.Equal(first.ID, running[0].ID)
+
+	close(release)
+	<-firstDone
+	assert.True(js.IsIdle())
+}
+
+func TestJobManagerMaxConcurrency(t *testing.T) {
+	assert := assert.New(t)
+
+	first, firstStarted := newBlockingJob("first")
+	second, secondStarted := newBlockingJob("second")
+	jm := New(OptMaxConcurrency(1))
+	assert.Nil(jm.LoadJobs(first, second))
+
+	ji, done, err := jm.RunJob("first")
+	assert.Nil(err)
+	<-firstStarted
+
+	_, _, err = jm.RunJob("second")
+	assert.True(IsJobConcurrencyLimit(err))
+	assert.True(jm.IsJobRunning("first"))
+	assert.False(jm.IsJobRunning("second"))
+
+	ji.Cancel()
+	<-done
+
+	ji, done, err = jm.RunJob("second")
+	assert.Nil(err)
+	<-secondStarted
+	ji.Cancel()
+	<-done
+	assert.Zero(jm.ConcurrencyLimiter.Running())
+}
+
+func TestJobSchedulerSkipped(t *testing.T) {
+	assert := assert.New(t)
+
+	skipped := make(chan Event, 10)
+	log := logger.All(logger.OptOutput(ioutil.Discard))
+	defer log.Close()
+	log.Listen(FlagSkipped, "test", NewEventListener(func(_ context.Context, e Event) { skipped <- e }))
+
+	job, started := newBlockingJob("skipped", OptJobSchedule(Every(5*time.Millisecond)))
+	js := NewJobScheduler(job, OptJobSchedulerLog(log))
+	go js.Start()
+	<-js.NotifyStarted()
+	defer js.Stop()
+
+	<-started
+	select {
+	case e := <-skipped:
+		assert.Equal("skipped", e.JobName)
+		assert.True(IsJobAlreadyRunning(e.Err))
+	case <-time.After(5 * time.Second):
+		assert.FailNow("a tick should have been skipped")
+	}
+}
@@ -9,6 +9,10 @@ const (
 	DefaultTimeout               time.Duration = 0
 	DefaultHistoryRestoreTimeout               = 5 * time.Second
 	DefaultShutdownGracePeriod   time.Duration = 0
+	DefaultDistributedLockTTL                  = 30 * time.Second
+	DefaultHistoryMaxAge         time.Duration = 0
+	DefaultRetryBackoff                        = 5 * time.Second
+	DefaultRetryMaxBackoff                     = 5 * time.Minute
 )
 
 const (
@@ -18,6 +22,22 @@ const (
 	DefaultShouldSkipLoggerListeners = false
 	// DefaultShouldSkipLoggerOutput is a default.
 	DefaultShouldSkipLoggerOutput = false
+	// DefaultDistributedLock is a default.
+	DefaultDistributedLock = false
+	// DefaultHistoryMaxCount is a default.
+	DefaultHistoryMaxCount = 1000
+	// DefaultRetryMaxAttempts is a default.
+	DefaultRetryMaxAttempts = 1
+	// DefaultConcurrencyPolicy is a default.
+	DefaultConcurrencyPolicy = ConcurrencyPolicyForbid
+	// DefaultCatchUpPolicy is a default.
+	DefaultCatchUpPolicy = CatchUpPolicyNone
+	// DefaultCatchUpMaxRuns is a default.
+	DefaultCatchUpMaxRuns = 10
+	// DefaultShouldSkipOutputCapture is a default.
+	DefaultShouldSkipOutputCapture = false
+	// DefaultOutputMaxBytes is a default.
+	DefaultOutputMaxBytes = 64 << 10
 )
 
 const (
@@ -31,6 +51,12 @@ const (
 	FlagErrored = "cron.errored"
 	// FlagCancelled is an event flag.
 	FlagCancelled = "cron.cancelled"
+	// FlagRetry is an event flag.
+	FlagRetry = "cron.retry"
+	// FlagSkipped is an event flag.
+	FlagSkipped = "cron.skipped"
+	// FlagReplaced is an event flag.
+	FlagReplaced = "cron.replaced"
 	// FlagBroken is an event flag.
 	FlagBroken = "cron.broken"
 	// FlagFixed is an event flag.
@@ -61,6 +87,32 @@ const (
 	JobSchedulerStateStopped JobSchedulerState = "stopped"
 )
 
+// ConcurrencyPolicy determines what happens when a job is triggered while it is running.
+type ConcurrencyPolicy string
+
+// ConcurrencyPolicy values.
+const (
+	// ConcurrencyPolicyForbid doesn't start another invocation while the job is running.
+	ConcurrencyPolicyForbid ConcurrencyPolicy = "forbid"
+	// ConcurrencyPolicyAllow starts another invocation alongside the running invocations, up to `JobConfig.MaxConcurrency`.
+	ConcurrencyPolicyAllow ConcurrencyPolicy = "allow"
+	// ConcurrencyPolicyReplace cancels the running invocations and starts another.
+	ConcurrencyPolicyReplace ConcurrencyPolicy = "replace"
+)
+
+// CatchUpPolicy determines which of the runs a job missed while no job manager was running are run when it starts.
+type CatchUpPolicy string
+
+// CatchUpPolicy values.
+const (
+	// CatchUpPolicyNone doesn't run missed runs.
+	CatchUpPolicyNone CatchUpPolicy = "none"
+	// CatchUpPolicyOnce runs once if any runs were missed.
+	CatchUpPolicyOnce CatchUpPolicy = "once"
+	// CatchUpPolicyEach runs once for each missed run, oldest first, up to `JobConfig.CatchUpMaxRuns` of the most recent.
+	CatchUpPolicyEach CatchUpPolicy = "each"
+)
+
 // JobInvocationStatus is a job status.
 type JobInvocationStatus string
 
@@ -71,4 +123,5 @@ const (
 	JobInvocationStatusCancelled JobInvocationStatus = "cancelled"
 	JobInvocationStatusErrored   JobInvocationStatus = "errored"
 	JobInvocationStatusSuccess   JobInvocationStatus = "success"
+	JobInvocationStatusSkipped   JobInvocationStatus = "skipped"
 )
@@ -0,0 +1,342 @@
+package crondb
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/blend/go-sdk/cron"
+	"github.com/blend/go-sdk/db"
+	"github.com/blend/go-sdk/db/migration"
+	"github.com/blend/go-sdk/ex"
+	"github.com/blend/go-sdk/ref"
+)
+
+// DefaultJobHistoryTable is the default table job invocations are recorded in.
+const DefaultJobHistoryTable = "cron_job_invocation"
+
+var (
+	_ cron.JobHistoryStore = (*JobHistoryStore)(nil)
+)
+
+// NewJobHistoryStore returns a new job history store for a connection.
+func NewJobHistoryStore(conn *db.Connection, options ...JobHistoryStoreOption) *JobHistoryStore {
+	jhs := &JobHistoryStore{
+		Conn:  conn,
+		Table: DefaultJobHistoryTable,
+	}
+	for _, option := range options {
+		option(jhs)
+	}
+	return jhs
+}
+
+// JobHistoryStoreOption mutates a job history store.
+type JobHistoryStoreOption func(*JobHistoryStore)
+
+// OptJobHistoryStoreTable sets the table job invocations are recorded in.
+func OptJobHistoryStoreTable(table string) JobHistoryStoreOption {
+	return func(jhs *JobHistoryStore) { jhs.Table = table }
+}
+
+// JobHistoryStore is a `cron.JobHistoryStore` that records job invocations in a postgres table,
+// with a row for each invocation.
+//
+// Invocation (and attempt) errors are stored as json and restored as `*ex.Ex` errors with the same class and message.
+type JobHistoryStore struct {
+	Conn  *db.Connection
+	Table string
+}
+
+// Migrations returns the migrations that create the invocations table.
+func (jhs *JobHistoryStore) Migrations() *migration.Suite {
+	return migration.NewWithActions(
+		migration.NewStep(
+			migration.TableNotExists(jhs.Table),
+			migration.Statements(
+				fmt.Sprintf(`CREATE TABLE %s (
+					id varchar(64) NOT NULL PRIMARY KEY,
+					job_name varchar(255) NOT NULL,
+					status varchar(32) NOT NULL,
+					started_utc timestamptz NOT NULL,
+					complete_utc timestamptz,
+					err text,
+					parameters text,
+					output text
+				);`, jhs.Table),
+				fmt.Sprintf(`CREATE INDEX ix_%[1]s_job_name_started_utc ON %[1]s (job_name, started_utc);`, jhs.Table),
+				fmt.Sprintf(`CREATE INDEX ix_%[1]s_started_utc ON %[1]s (started_utc);`, jhs.Table),
+			),
+		),
+		migration.NewStep(
+			migration.ColumnNotExists(jhs.Table, "attempts"),
+			migration.Statements(
+				fmt.Sprintf(`ALTER TABLE %s ADD COLUMN attempts text;`, jhs.Table),
+			),
+		),
+		migration.NewStep(
+			migration.ColumnNotExists(jhs.Table, "children"),
+			migration.Statements(
+				fmt.Sprintf(`ALTER TABLE %s ADD COLUMN children text;`, jhs.Table),
+			),
+		),
+	)
+}
+
+// Save implements cron.JobHistoryStore.
+func (jhs *JobHistoryStore) Save(ctx context.Context, ji *cron.JobInvocation) error {
+	var complete *time.Time
+	if !ji.Complete.IsZero() {
+		utc := ji.Complete.UTC()
+		complete = &utc
+	}
+	var errJSON, parameters, attempts, children *string
+	var err error
+	if ji.Err != nil {
+		if errJSON, err = marshalJSON(ex.New(ji.Err)); err != nil {
+			return err
+		}
+	}
+	if len(ji.Parameters) > 0 {
+		if parameters, err = marshalJSON(ji.Parameters); err != nil {
+			return err
+		}
+	}
+	if len(ji.Attempts) > 0 {
+		if attempts, err = marshalJSON(newJobInvocationAttempts(ji.Attempts)); err != nil {
+			return err
+		}
+	}
+	if len(ji.Children) > 0 {
+		if children, err = marshalJSON(newJobInvocationChildren(ji.Children)); err != nil {
+			return err
+		}
+	}
+
+	statement := fmt.Sprintf(`INSERT INTO %s (id, job_name, status, started_utc, complete_utc, err, parameters, output, attempts, children)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
+		ON CONFLICT (id) DO UPDATE SET
+			status = excluded.status,
+			started_utc = excluded.started_utc,
+			complete_utc = excluded.complete_utc,
+			err = excluded.err,
+			parameters = excluded.parameters,
+			output = excluded.output,
+			attempts = excluded.attempts,
+			children = excluded.children`, jhs.Table)
+	return ex.New(db.IgnoreExecResult(jhs.Conn.Invoke(db.OptContext(ctx)).Exec(statement,
+		ji.ID, ji.JobName, string(ji.Status), ji.Started.UTC(), complete, errJSON, parameters, ji.Output, attempts, children,
+	)))
+}
+
+// Query implements cron.JobHistoryStore.
+func (jhs *JobHistoryStore) Query(ctx context.Context, query cron.JobHistoryQuery) ([]*cron.JobInvocation, error) {
+	var predicates []string
+	var args []interface{}
+	arg := func(value interface{}) string {
+		args = append(args, value)
+		return fmt.Sprintf("$%d", len(args))
+	}
+	if query.JobName != "" {
+		predicates = append(predicates, "job_name = "+arg(query.JobName))
+	}
+	if len(query.Statuses) > 0 {
+		statuses := make([]string, len(query.Statuses))
+		for index, status := range query.Statuses {
+			statuses[index] = arg(string(status))
+		}
+		predicates = append(predicates, "status IN ("+strings.Join(statuses, ", ")+")")
+	}
+	if !query.After.IsZero() {
+		predicates = append(predicates, "started_utc >= "+arg(query.After.UTC()))
+	}
+	if !query.Before.IsZero() {
+		predicates = append(predicates, "started_utc < "+arg(query.Before.UTC()))
+	}
+
+	statement := fmt.Sprintf(`SELECT id, job_name, status, started_utc, complete_utc, err, parameters, output, attempts, children FROM %s`, jhs.Table)
+	if len(predicates) > 0 {
+		statement += " WHERE " + strings.Join(predicates, " AND ")
+	}
+	statement += " ORDER BY started_utc DESC"
+	if query.Limit > 0 {
+		statement += " LIMIT " + arg(query.Limit)
+	}
+
+	var output []*cron.JobInvocation
+	err := jhs.Conn.Invoke(db.OptContext(ctx)).Query(statement, args...).Each(func(r db.Rows) error {
+		ji, err := jhs.scan(r)
+		if err != nil {
+			return err
+		}
+		output = append(output, ji)
+		return nil
+	})
+	if err != nil {
+		return nil, ex.New(err)
+	}
+	return output, nil
+}
+
+// Cull implements cron.JobHistoryStore.
+func (jhs *JobHistoryStore) Cull(ctx context.Context, jobName string, retention cron.JobHistoryRetention) error {
+	if retention.MaxCount > 0 {
+		statement := fmt.Sprintf(`DELETE FROM %[1]s WHERE job_name = $1 AND id NOT IN (
+			SELECT id FROM %[1]s WHERE job_name = $1 ORDER BY started_utc DESC LIMIT $2
+		)`, jhs.Table)
+		if err := db.IgnoreExecResult(jhs.Conn.Invoke(db.OptContext(ctx)).Exec(statement, jobName, retention.MaxCount)); err != nil {
+			return ex.New(err)
+		}
+	}
+	if cutoff := retention.Cutoff(cron.Now()); !cutoff.IsZero() {
+		statement := fmt.Sprintf(`DELETE FROM %s WHERE job_name = $1 AND started_utc < $2`, jhs.Table)
+		if err := db.IgnoreExecResult(jhs.Conn.Invoke(db.OptContext(ctx)).Exec(statement, jobName, cutoff.UTC())); err != nil {
+			return ex.New(err)
+		}
+	}
+	return nil
+}
+
+func (jhs *JobHistoryStore) scan(r db.Rows) (*cron.JobInvocation, error) {
+	var ji cron.JobInvocation
+	var status string
+	var complete *time.Time
+	var errJSON, parameters, output, attempts, children *string
+	if err := r.Scan(&ji.ID, &ji.JobName, &status, &ji.Started, &complete, &errJSON, &parameters, &output, &attempts, &children); err != nil {
+		return nil, err
+	}
+	ji.Status = cron.JobInvocationStatus(status)
+	ji.Started = ji.Started.UTC()
+	if complete != nil {
+		ji.Complete = complete.UTC()
+	}
+	if errJSON != nil {
+		var invocationErr ex.Ex
+		if err := json.Unmarshal([]byte(*errJSON), &invocationErr); err != nil {
+			return nil, err
+		}
+		ji.Err = &invocationErr
+	}
+	if parameters != nil {
+		if err := json.Unmarshal([]byte(*parameters), &ji.Parameters); err != nil {
+			return nil, err
+		}
+	}
+	if output != nil {
+		ji.Output = *output
+	}
+	if attempts != nil {
+		var saved []jobInvocationAttempt
+		if err := json.Unmarshal([]byte(*attempts), &saved); err != nil {
+			return nil, err
+		}
+		ji.Attempts = restoreJobInvocationAttempts(saved)
+	}
+	if children != nil {
+		var saved []jobInvocationChild
+		if err := json.Unmarshal([]byte(*children), &saved); err != nil {
+			return nil, err
+		}
+		ji.Children = restoreJobInvocationChildren(saved)
+	}
+	return &ji, nil
+}
+
+// jobInvocationAttempt is how attempts are stored, with errors that can be unmarshaled.
+type jobInvocationAttempt struct {
+	Started  time.Time `json:"started"`
+	Complete time.Time `json:"complete"`
+	Err      *ex.Ex    `json:"err,omitempty"`
+}
+
+func newJobInvocationAttempts(attempts []cron.JobInvocationAttempt) []jobInvocationAttempt {
+	output := make([]jobInvocationAttempt, len(attempts))
+	for index, attempt := range attempts {
+		output[index] = jobInvocationAttempt{
+			Started:  attempt.Started.UTC(),
+			Complete: attempt.Complete.UTC(),
+			Err:      ex.As(ex.New(attempt.Err)),
+		}
+	}
+	return output
+}
+
+func restoreJobInvocationAttempts(attempts []jobInvocationAttempt) []cron.JobInvocationAttempt {
+	var output []cron.JobInvocationAttempt
+	for _, attempt := range attempts {
+		restored := cron.JobInvocationAttempt{
+			Started:  attempt.Started,
+			Complete: attempt.Complete,
+		}
+		if attempt.Err != nil {
+			restored.Err = attempt.Err
+		}
+		output = append(output, restored)
+	}
+	return output
+}
+
+// jobInvocationChild is how child invocations (e.g. workflow steps) are stored, with errors that can be unmarshaled.
+type jobInvocationChild struct {
+	ID         string                   `json:"id"`
+	JobName    string                   `json:"jobName"`
+	Status     cron.JobInvocationStatus `json:"status"`
+	Started    time.Time                `json:"started"`
+	Complete   time.Time                `json:"complete"`
+	Err        *ex.Ex                   `json:"err,omitempty"`
+	Parameters cron.JobParameters       `json:"parameters,omitempty"`
+	Output     string                   `json:"output,omitempty"`
+	Attempts   []jobInvocationAttempt   `json:"attempts,omitempty"`
+	Children   []jobInvocationChild     `json:"children,omitempty"`
+}
+
+func newJobInvocationChildren(children []*cron.JobInvocation) []jobInvocationChild {
+	output := make([]jobInvocationChild, len(children))
+	for index, child := range children {
+		output[index] = jobInvocationChild{
+			ID:         child.ID,
+			JobName:    child.JobName,
+			Status:     child.Status,
+			Started:    child.Started.UTC(),
+			Complete:   child.Complete.UTC(),
+			Err:        ex.As(ex.New(child.Err)),
+			Parameters: child.Parameters,
+			Output:     child.Output,
+			Attempts:   newJobInvocationAttempts(child.Attempts),
+			Children:   newJobInvocationChildren(child.Children),
+		}
+	}
+	return output
+}
+
+func restoreJobInvocationChildren(children []jobInvocationChild) []*cron.JobInvocation {
+	var output []*cron.JobInvocation
+	for _, child := range children {
+		restored := &cron.JobInvocation{
+			ID:         child.ID,
+			JobName:    child.JobName,
+			Status:     child.Status,
+			Started:    child.Started,
+			Complete:   child.Complete,
+			Parameters: child.Parameters,
+			Output:     child.Output,
+			Attempts:   restoreJobInvocationAttempts(child.Attempts),
+			Children:   restoreJobInvocationChildren(child.Children),
+		}
+		if child.Err != nil {
+			restored.Err = child.Err
+		}
+		output = append(output, restored)
+	}
+	return output
+}
+
+func marshalJSON(value interface{}) (*string, error) {
+	contents, err := json.Marshal(value)
+	if err != nil {
+		return nil, ex.New(err)
+	}
+	return ref.String(string(contents)), nil
+}
@@ -0,0 +1,104 @@
+package crondb
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/blend/go-sdk/assert"
+	"github.com/blend/go-sdk/cron"
+	"github.com/blend/go-sdk/ex"
+)
+
+func TestJobHistoryStore(t *testing.T) {
+	assert := assert.New(t)
+
+	table := buildTestTableName()
+	defer dropTable(table)
+
+	ctx := context.Background()
+	store := NewJobHistoryStore(defaultDB(), OptJobHistoryStoreTable(table))
+	assert.Nil(store.Migrations().Apply(ctx, defaultDB()))
+
+	started := time.Date(2020, 01, 02, 03, 04, 05, 0, time.UTC)
+	for index := 0; index < 10; index++ {
+		ji := &cron.JobInvocation{
+			ID:         fmt.Sprintf("test-%d", index),
+			JobName:    "test",
+			Started:    started.Add(time.Duration(index) * time.Minute),
+			Status:     cron.JobInvocationStatusRunning,
+			Parameters: cron.JobParameters{"index": fmt.Sprint(index)},
+		}
+		assert.Nil(store.Save(ctx, ji))
+
+		ji.Complete = ji.Started.Add(time.Second)
+		ji.Output = "output"
+		ji.Attempts = []cron.JobInvocationAttempt{
+			{Started: ji.Started, Complete: ji.Started.Add(time.Millisecond), Err: ex.New("attempt failed")},
+			{Started: ji.Started.Add(time.Millisecond), Complete: ji.Complete},
+		}
+		ji.Children = []*cron.JobInvocation{
+			{ID: "first", JobName: "first", Status: cron.JobInvocationStatusSuccess, Started: ji.Started, Complete: ji.Complete, Parameters: cron.JobParameters{"output": "value"}},
+			{ID: "second", JobName: "second", Status: cron.JobInvocationStatusErrored, Started: ji.Started, Complete: ji.Complete, Err: ex.New("step failed")},
+			{ID: "third", JobName: "third", Status: cron.JobInvocationStatusSkipped},
+		}
+		if index%2 == 0 {
+			ji.Status = cron.JobInvocationStatusSuccess
+		} else {
+			ji.Status = cron.JobInvocationStatusErrored
+			ji.Err = ex.New(cron.ErrJobCancelled, ex.OptMessage("test"))
+		}
+		assert.Nil(store.Save(ctx, ji))
+	}
+	assert.Nil(store.Save(ctx, &cron.JobInvocation{ID: "other", JobName: "other", Started: started, Status: cron.JobInvocationStatusSuccess}))
+
+	history, err := store.Query(ctx, cron.JobHistoryQuery{JobName: "test"})
+	assert.Nil(err)
+	assert.Len(history, 10)
+	assert.Equal("test-9", history[0].ID)
+	assert.Equal(started.Add(9*time.Minute), history[0].Started)
+	assert.Equal(started.Add(9*time.Minute+time.Second), history[0].Complete)
+	assert.Equal("9", history[0].Parameters["index"])
+	assert.Equal("output", history[0].Output)
+	assert.True(ex.Is(history[0].Err, cron.ErrJobCancelled))
+	assert.Equal("test", ex.ErrMessage(history[0].Err))
+	assert.Nil(history[1].Err)
+	assert.Len(history[0].Attempts, 2)
+	assert.Equal("attempt failed", history[0].Attempts[0].Err.Error())
+	assert.Equal(started.Add(9*time.Minute+time.Millisecond), history[0].Attempts[0].Complete)
+	assert.Nil(history[0].Attempts[1].Err)
+	assert.Len(history[0].Children, 3)
+	assert.Equal("value", history[0].Children[0].Parameters["output"])
+	assert.Nil(history[0].Children[0].Err)
+	assert.Equal("step failed", history[0].Children[1].Err.Error())
+	assert.Equal(cron.JobInvocationStatusSkipped, history[0].Children[2].Status)
+
+	history, err = store.Query(ctx, cron.JobHistoryQuery{
+		JobName:  "test",
+		Statuses: []cron.JobInvocationStatus{cron.JobInvocationStatusErrored},
+		After:    started.Add(2 * time.Minute),
+		Before:   started.Add(7 * time.Minute),
+	})
+	assert.Nil(err)
+	assert.Len(history, 2)
+	assert.Equal("test-5", history[0].ID)
+	assert.Equal("test-3", history[1].ID)
+
+	history, err = store.Query(ctx, cron.JobHistoryQuery{Limit: 3})
+	assert.Nil(err)
+	assert.Len(history, 3)
+
+	assert.Nil(store.Cull(ctx, "test", cron.JobHistoryRetention{MaxCount: 6}))
+	assert.Nil(store.Cull(ctx, "test", cron.JobHistoryRetention{MaxAge: time.Since(started.Add(5*time.Minute + 30*time.Second))}))
+	history, err = store.Query(ctx, cron.JobHistoryQuery{JobName: "test"})
+	assert.Nil(err)
+	assert.Len(history, 4)
+	assert.Equal("test-6", history[3].ID)
+
+	history, err = store.Query(ctx, cron.JobHistoryQuery{JobName: "other"})
+	assert.Nil(err)
+	assert.Len(history, 1)
+	assert.Empty(history[0].Parameters)
+	assert.True(history[0].Complete.IsZero())
+}
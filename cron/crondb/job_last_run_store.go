@@ -0,0 +1,81 @@
+package crondb
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/blend/go-sdk/cron"
+	"github.com/blend/go-sdk/db"
+	"github.com/blend/go-sdk/db/migration"
+	"github.com/blend/go-sdk/ex"
+)
+
+// DefaultJobLastRunTable is the default table the last runs of jobs are kept in.
+const DefaultJobLastRunTable = "cron_job_last_run"
+
+var (
+	_ cron.JobLastRunStore = (*JobLastRunStore)(nil)
+)
+
+// NewJobLastRunStore returns a new last run store for a connection.
+func NewJobLastRunStore(conn *db.Connection, options ...JobLastRunStoreOption) *JobLastRunStore {
+	jlr := &JobLastRunStore{
+		Conn:  conn,
+		Table: DefaultJobLastRunTable,
+	}
+	for _, option := range options {
+		option(jlr)
+	}
+	return jlr
+}
+
+// JobLastRunStoreOption mutates a last run store.
+type JobLastRunStoreOption func(*JobLastRunStore)
+
+// OptJobLastRunStoreTable sets the table the last runs of jobs are kept in.
+func OptJobLastRunStoreTable(table string) JobLastRunStoreOption {
+	return func(jlr *JobLastRunStore) { jlr.Table = table }
+}
+
+// JobLastRunStore is a `cron.JobLastRunStore` that keeps the last runs of jobs in a postgres table, with a row for each job.
+type JobLastRunStore struct {
+	Conn  *db.Connection
+	Table string
+}
+
+// Migrations returns the migrations that create the last runs table.
+func (jlr *JobLastRunStore) Migrations() *migration.Suite {
+	return migration.NewWithActions(
+		migration.NewStep(
+			migration.TableNotExists(jlr.Table),
+			migration.Statements(
+				fmt.Sprintf(`CREATE TABLE %s (
+					job_name varchar(255) NOT NULL PRIMARY KEY,
+					last_run_utc timestamptz NOT NULL
+				);`, jlr.Table),
+			),
+		),
+	)
+}
+
+// LastRun implements cron.JobLastRunStore.
+func (jlr *JobLastRunStore) LastRun(ctx context.Context, jobName string) (time.Time, error) {
+	var lastRun time.Time
+	_, err := jlr.Conn.Invoke(db.OptContext(ctx)).Query(fmt.Sprintf(`SELECT last_run_utc FROM %s WHERE job_name = $1`, jlr.Table), jobName).Scan(&lastRun)
+	if err != nil {
+		return cron.Zero, ex.New(err)
+	}
+	if lastRun.IsZero() {
+		return cron.Zero, nil
+	}
+	return lastRun.UTC(), nil
+}
+
+// SetLastRun implements cron.JobLastRunStore.
+func (jlr *JobLastRunStore) SetLastRun(ctx context.Context, jobName string, runtime time.Time) error {
+	statement := fmt.Sprintf(`INSERT INTO %[1]s (job_name, last_run_utc) VALUES ($1, $2)
+		ON CONFLICT (job_name) DO UPDATE SET last_run_utc = excluded.last_run_utc
+		WHERE %[1]s.last_run_utc < excluded.last_run_utc`, jlr.Table)
+	return ex.New(db.IgnoreExecResult(jlr.Conn.Invoke(db.OptContext(ctx)).Exec(statement, jobName, runtime.UTC())))
+}
@@ -0,0 +1,42 @@
+package crondb
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/blend/go-sdk/assert"
+)
+
+func TestJobLastRunStore(t *testing.T) {
+	assert := assert.New(t)
+
+	table := buildTestTableName()
+	defer dropTable(table)
+
+	ctx := context.Background()
+	store := NewJobLastRunStore(defaultDB(), OptJobLastRunStoreTable(table))
+	assert.Nil(store.Migrations().Apply(ctx, defaultDB()))
+
+	lastRun, err := store.LastRun(ctx, "test")
+	assert.Nil(err)
+	assert.True(lastRun.IsZero())
+
+	runtime := time.Date(2020, 01, 02, 03, 04, 05, 0, time.UTC)
+	assert.Nil(store.SetLastRun(ctx, "test", runtime))
+	lastRun, err = store.LastRun(ctx, "test")
+	assert.Nil(err)
+	assert.Equal(runtime, lastRun)
+
+	// an earlier time doesn't replace a later one.
+	assert.Nil(store.SetLastRun(ctx, "test", runtime.Add(-time.Hour)))
+	assert.Nil(store.SetLastRun(ctx, "other", runtime.Add(-time.Hour)))
+	lastRun, err = store.LastRun(ctx, "test")
+	assert.Nil(err)
+	assert.Equal(runtime, lastRun)
+
+	assert.Nil(store.SetLastRun(ctx, "test", runtime.Add(time.Hour)))
+	lastRun, err = store.LastRun(ctx, "test")
+	assert.Nil(err)
+	assert.Equal(runtime.Add(time.Hour), lastRun)
+}
@@ -0,0 +1,138 @@
+package crondb
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/blend/go-sdk/cron"
+	"github.com/blend/go-sdk/db"
+	"github.com/blend/go-sdk/db/migration"
+	"github.com/blend/go-sdk/ex"
+	"github.com/blend/go-sdk/uuid"
+)
+
+// DefaultJobLockerTable is the default table job leases are held in.
+const DefaultJobLockerTable = "cron_job_lease"
+
+var (
+	_ cron.JobLocker = (*JobLocker)(nil)
+	_ cron.JobLease  = (*JobLease)(nil)
+)
+
+// NewJobLocker returns a new job locker for a connection.
+func NewJobLocker(conn *db.Connection, options ...JobLockerOption) *JobLocker {
+	jl := &JobLocker{
+		Conn:  conn,
+		Table: DefaultJobLockerTable,
+	}
+	jl.Owner, _ = os.Hostname()
+	for _, option := range options {
+		option(jl)
+	}
+	return jl
+}
+
+// JobLockerOption mutates a job locker.
+type JobLockerOption func(*JobLocker)
+
+// OptJobLockerTable sets the table job leases are held in.
+func OptJobLockerTable(table string) JobLockerOption {
+	return func(jl *JobLocker) { jl.Table = table }
+}
+
+// OptJobLockerOwner sets the owner recorded with leases; it defaults to the hostname.
+func OptJobLockerOwner(owner string) JobLockerOption {
+	return func(jl *JobLocker) { jl.Owner = owner }
+}
+
+/*
+JobLocker is a `cron.JobLocker` that holds leases in a postgres table, with a row for each job.
+
+A lease is acquired by taking over the row for a job if the current lease has expired and was acquired
+for an earlier scheduled time, and lease expiry is computed with the database clock so the clocks of
+job managers don't need to agree. Releasing a lease expires it, but keeps the scheduled time it was
+acquired for so that the same scheduled time isn't run by another job manager.
+*/
+type JobLocker struct {
+	Conn  *db.Connection
+	Table string
+	Owner string
+}
+
+// Migrations returns the migrations that create the leases table.
+func (jl *JobLocker) Migrations() *migration.Suite {
+	return migration.NewWithActions(
+		migration.NewStep(
+			migration.TableNotExists(jl.Table),
+			migration.Statements(
+				fmt.Sprintf(`CREATE TABLE %s (
+					job_name varchar(255) NOT NULL PRIMARY KEY,
+					lease_id varchar(64) NOT NULL,
+					owner varchar(255) NOT NULL,
+					runtime_utc timestamptz NOT NULL,
+					expires_utc timestamptz NOT NULL
+				);`, jl.Table),
+			),
+		),
+	)
+}
+
+// Lock implements cron.JobLocker.
+func (jl *JobLocker) Lock(ctx context.Context, jobName string, runtime time.Time, ttl time.Duration) (cron.JobLease, error) {
+	leaseID := uuid.V4().String()
+	statement := fmt.Sprintf(`INSERT INTO %[1]s (job_name, lease_id, owner, runtime_utc, expires_utc)
+		VALUES ($1, $2, $3, $4, now() + ($5 * interval '1 millisecond'))
+		ON CONFLICT (job_name) DO UPDATE SET
+			lease_id = excluded.lease_id,
+			owner = excluded.owner,
+			runtime_utc = excluded.runtime_utc,
+			expires_utc = excluded.expires_utc
+		WHERE %[1]s.expires_utc < now() AND %[1]s.runtime_utc < excluded.runtime_utc
+		RETURNING lease_id`, jl.Table)
+
+	var acquired string
+	found, err := jl.Conn.Invoke(db.OptContext(ctx)).Query(statement, jobName, leaseID, jl.Owner, runtime.UTC(), milliseconds(ttl)).Scan(&acquired)
+	if err != nil {
+		return nil, ex.New(err)
+	}
+	if !found || acquired != leaseID {
+		return nil, ex.New(cron.ErrJobLocked, ex.OptMessagef("job: %s", jobName))
+	}
+	return &JobLease{
+		Locker:  jl,
+		JobName: jobName,
+		LeaseID: leaseID,
+	}, nil
+}
+
+// JobLease is a lease acquired from a postgres job locker.
+type JobLease struct {
+	Locker  *JobLocker
+	JobName string
+	LeaseID string
+}
+
+// Renew implements cron.JobLease.
+func (jl *JobLease) Renew(ctx context.Context, ttl time.Duration) error {
+	statement := fmt.Sprintf(`UPDATE %s SET expires_utc = now() + ($3 * interval '1 millisecond') WHERE job_name = $1 AND lease_id = $2`, jl.Locker.Table)
+	updated, err := db.ExecRowsAffected(jl.Locker.Conn.Invoke(db.OptContext(ctx)).Exec(statement, jl.JobName, jl.LeaseID, milliseconds(ttl)))
+	if err != nil {
+		return ex.New(err)
+	}
+	if updated == 0 {
+		return ex.New(cron.ErrJobLeaseLost, ex.OptMessagef("job: %s", jl.JobName))
+	}
+	return nil
+}
+
+// Release implements cron.JobLease.
+func (jl *JobLease) Release(ctx context.Context) error {
+	statement := fmt.Sprintf(`UPDATE %s SET expires_utc = now() WHERE job_name = $1 AND lease_id = $2`, jl.Locker.Table)
+	return ex.New(db.IgnoreExecResult(jl.Locker.Conn.Invoke(db.OptContext(ctx)).Exec(statement, jl.JobName, jl.LeaseID)))
+}
+
+func milliseconds(d time.Duration) int64 {
+	return int64(d / time.Millisecond)
+}
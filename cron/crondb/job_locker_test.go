@@ -0,0 +1,68 @@
+package crondb
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/blend/go-sdk/assert"
+	"github.com/blend/go-sdk/cron"
+	"github.com/blend/go-sdk/ex"
+)
+
+func TestJobLocker(t *testing.T) {
+	assert := assert.New(t)
+
+	table := buildTestTableName()
+	defer dropTable(table)
+
+	ctx := context.Background()
+	locker := NewJobLocker(defaultDB(), OptJobLockerTable(table), OptJobLockerOwner("test"))
+	assert.Nil(locker.Migrations().Apply(ctx, defaultDB()))
+
+	runtime := time.Date(2020, 01, 02, 03, 04, 05, 0, time.UTC)
+	lease, err := locker.Lock(ctx, "test", runtime, time.Minute)
+	assert.Nil(err)
+	assert.NotNil(lease)
+
+	// held by another lease.
+	_, err = NewJobLocker(defaultDB(), OptJobLockerTable(table)).Lock(ctx, "test", runtime.Add(time.Second), time.Minute)
+	assert.True(cron.IsJobLocked(err))
+
+	other, err := locker.Lock(ctx, "other", runtime, time.Minute)
+	assert.Nil(err)
+	assert.Nil(other.Release(ctx))
+
+	assert.Nil(lease.Renew(ctx, time.Minute))
+	assert.Nil(lease.Release(ctx))
+
+	// the same scheduled time doesn't run again once released.
+	_, err = locker.Lock(ctx, "test", runtime, time.Minute)
+	assert.True(cron.IsJobLocked(err))
+
+	next, err := locker.Lock(ctx, "test", runtime.Add(time.Second), time.Minute)
+	assert.Nil(err)
+	assert.True(ex.Is(lease.Renew(ctx, time.Minute), cron.ErrJobLeaseLost))
+	assert.Nil(next.Release(ctx))
+}
+
+func TestJobLockerExpired(t *testing.T) {
+	assert := assert.New(t)
+
+	table := buildTestTableName()
+	defer dropTable(table)
+
+	ctx := context.Background()
+	locker := NewJobLocker(defaultDB(), OptJobLockerTable(table))
+	assert.Nil(locker.Migrations().Apply(ctx, defaultDB()))
+
+	runtime := time.Date(2020, 01, 02, 03, 04, 05, 0, time.UTC)
+	lease, err := locker.Lock(ctx, "test", runtime, time.Millisecond)
+	assert.Nil(err)
+	time.Sleep(10 * time.Millisecond)
+
+	// an expired lease can be taken over for a later runtime, and can no longer be renewed.
+	_, err = locker.Lock(ctx, "test", runtime.Add(time.Second), time.Minute)
+	assert.Nil(err)
+	assert.True(ex.Is(lease.Renew(ctx, time.Minute), cron.ErrJobLeaseLost))
+}
@@ -0,0 +1,43 @@
+package crondb
+
+import (
+	"fmt"
+	"os"
+	"testing"
+
+	_ "github.com/lib/pq"
+
+	"github.com/blend/go-sdk/db"
+	"github.com/blend/go-sdk/logger"
+	"github.com/blend/go-sdk/stringutil"
+)
+
+func TestMain(m *testing.M) {
+	conn, err := db.New(db.OptConfigFromEnv())
+	if err != nil {
+		logger.FatalExit(err)
+	}
+	err = conn.Open()
+	if err != nil {
+		logger.FatalExit(err)
+	}
+	defaultConnection = conn
+	defer conn.Close()
+	os.Exit(m.Run())
+}
+
+var (
+	defaultConnection *db.Connection
+)
+
+func defaultDB() *db.Connection {
+	return defaultConnection
+}
+
+func buildTestTableName() string {
+	return fmt.Sprintf("test_cron_%s", stringutil.Random(stringutil.LowerLetters, 10))
+}
+
+func dropTable(table string) {
+	_ = db.IgnoreExecResult(defaultDB().Exec(fmt.Sprintf("DROP TABLE IF EXISTS %s", table)))
+}
@@ -0,0 +1,20 @@
+/*
+Package crondb includes postgres backed implementations of `cron` interfaces, using `db.Connection`.
+
+The tables they use can be created with the `Migrations` of each type, e.g.:
+
+	locker := crondb.NewJobLocker(conn)
+	if err := locker.Migrations().Apply(ctx, conn); err != nil {
+		return err
+	}
+	history := crondb.NewJobHistoryStore(conn)
+	if err := history.Migrations().Apply(ctx, conn); err != nil {
+		return err
+	}
+	lastRuns := crondb.NewJobLastRunStore(conn)
+	if err := lastRuns.Migrations().Apply(ctx, conn); err != nil {
+		return err
+	}
+	jm := cron.New(cron.OptLocker(locker), cron.OptHistoryStore(history), cron.OptLastRunStore(lastRuns))
+*/
+package crondb
@@ -0,0 +1,17 @@
+package cronweb
+
+import "github.com/blend/go-sdk/ex"
+
+const (
+	// DefaultBasePath is the default path the controller's routes are registered under.
+	DefaultBasePath = "/cron"
+	// DefaultHistoryLimit is the default number of recent invocations returned for each job.
+	DefaultHistoryLimit = 10
+)
+
+const (
+	// ErrInvalidSelector is returned if the `selector` query value can't be parsed.
+	ErrInvalidSelector ex.Class = "cronweb; invalid selector"
+	// ErrInvalidParameters is returned if the parameters to run a job with can't be parsed.
+	ErrInvalidParameters ex.Class = "cronweb; invalid parameters"
+)
@@ -0,0 +1,385 @@
+package cronweb
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+
+	"github.com/blend/go-sdk/cron"
+	"github.com/blend/go-sdk/ex"
+	"github.com/blend/go-sdk/selector"
+	"github.com/blend/go-sdk/web"
+	"github.com/blend/go-sdk/webutil"
+)
+
+// Interface assertions.
+var (
+	_ web.Controller = (*Controller)(nil)
+)
+
+// NewController returns a new controller for a job manager.
+func NewController(jm *cron.JobManager, options ...ControllerOption) *Controller {
+	c := Controller{
+		JobManager: jm,
+	}
+	for _, option := range options {
+		option(&c)
+	}
+	return &c
+}
+
+// ControllerOption is an option for controllers.
+type ControllerOption func(*Controller)
+
+// OptBasePath sets the path the controller's routes are registered under.
+func OptBasePath(basePath string) ControllerOption {
+	return func(c *Controller) { c.BasePath = basePath }
+}
+
+// OptHistoryLimit sets the number of recent invocations returned for each job.
+func OptHistoryLimit(historyLimit int) ControllerOption {
+	return func(c *Controller) { c.HistoryLimit = historyLimit }
+}
+
+// OptMiddleware adds middleware that's applied to each of the controller's routes, e.g. to authenticate requests.
+func OptMiddleware(middleware ...web.Middleware) ControllerOption {
+	return func(c *Controller) { c.Middleware = append(c.Middleware, middleware...) }
+}
+
+// Controller is a web controller for managing the jobs of a job manager.
+//
+// The api routes are:
+//
+//	GET  {BasePath}/api/jobs              the status of each job, optionally filtered by a `selector` on their labels.
+//	GET  {BasePath}/api/job/:name         the status of a job.
+//	POST {BasePath}/api/job/:name/run     run a job with the parameters in a json object body, returning its invocation.
+//	POST {BasePath}/api/job/:name/cancel  cancel a job's running invocations.
+//	POST {BasePath}/api/job/:name/enable  enable a job.
+//	POST {BasePath}/api/job/:name/disable disable a job.
+//
+// The dashboard is served at `{BasePath}` and `{BasePath}/job/:name`.
+//
+// Invocation parameters and captured `Output` are served as they are, without redaction, so the routes
+// should be protected with `Middleware` if jobs handle anything sensitive.
+type Controller struct {
+	JobManager *cron.JobManager
+	// BasePath is the path the routes are registered under; it defaults to `DefaultBasePath`.
+	BasePath string
+	// HistoryLimit is the number of recent invocations returned for each job; it defaults to `DefaultHistoryLimit`.
+	// Recent invocations are read from the job manager's history store, or are just the last invocation if it doesn't have one.
+	HistoryLimit int
+	// Middleware is applied to each of the controller's routes.
+	Middleware []web.Middleware
+}
+
+// BasePathOrDefault returns the base path or a default.
+func (c Controller) BasePathOrDefault() string {
+	if c.BasePath != "" {
+		return strings.TrimSuffix(c.BasePath, "/")
+	}
+	return DefaultBasePath
+}
+
+// HistoryLimitOrDefault returns the history limit or a default.
+func (c Controller) HistoryLimitOrDefault() int {
+	if c.HistoryLimit > 0 {
+		return c.HistoryLimit
+	}
+	return DefaultHistoryLimit
+}
+
+// Register registers the controller's routes and views with an app.
+func (c Controller) Register(app *web.App) {
+	app.Views.AddLiterals(viewJobs, viewJob)
+
+	basePath := c.BasePathOrDefault()
+	app.GET(basePath, c.getJobsView, c.Middleware...)
+	app.GET(basePath+"/job/:name", c.getJobView, c.Middleware...)
+	app.POST(basePath+"/job/:name/run", c.viewAction(func(r *web.Ctx) error {
+		_, err := c.run(r)
+		return err
+	}), c.Middleware...)
+	app.POST(basePath+"/job/:name/cancel", c.viewAction(c.cancel), c.Middleware...)
+	app.POST(basePath+"/job/:name/enable", c.viewAction(c.enable), c.Middleware...)
+	app.POST(basePath+"/job/:name/disable", c.viewAction(c.disable), c.Middleware...)
+
+	app.GET(basePath+"/api/jobs", c.getJobs, c.Middleware...)
+	app.GET(basePath+"/api/job/:name", c.getJob, c.Middleware...)
+	app.POST(basePath+"/api/job/:name/run", c.postRun, c.Middleware...)
+	app.POST(basePath+"/api/job/:name/cancel", c.apiAction(c.cancel), c.Middleware...)
+	app.POST(basePath+"/api/job/:name/enable", c.apiAction(c.enable), c.Middleware...)
+	app.POST(basePath+"/api/job/:name/disable", c.apiAction(c.disable), c.Middleware...)
+}
+
+//
+// api
+//
+
+func (c Controller) getJobs(r *web.Ctx) web.Result {
+	jobs, err := c.jobs(r)
+	if err != nil {
+		return c.apiError(err)
+	}
+	return web.JSON.Result(jobs)
+}
+
+func (c Controller) getJob(r *web.Ctx) web.Result {
+	job, err := c.job(r)
+	if err != nil {
+		return c.apiError(err)
+	}
+	return web.JSON.Result(job)
+}
+
+func (c Controller) postRun(r *web.Ctx) web.Result {
+	ji, err := c.run(r)
+	if err != nil {
+		return c.apiError(err)
+	}
+	return web.JSON.Result(NewInvocation(ji))
+}
+
+func (c Controller) apiAction(action func(*web.Ctx) error) web.Action {
+	return func(r *web.Ctx) web.Result {
+		if err := action(r); err != nil {
+			return c.apiError(err)
+		}
+		return web.JSON.OK()
+	}
+}
+
+func (c Controller) apiError(err error) web.Result {
+	if statusCode := errorStatusCode(err); statusCode != http.StatusInternalServerError {
+		return web.JSON.Status(statusCode, err.Error())
+	}
+	return web.JSON.InternalError(err)
+}
+
+//
+// views
+//
+
+func (c Controller) getJobsView(r *web.Ctx) web.Result {
+	jobs, err := c.jobs(r)
+	if err != nil {
+		return c.viewError(r, err)
+	}
+	selectorQuery, _ := r.QueryValue("selector")
+	return r.Views.View(viewNameJobs, jobsViewModel{
+		BasePath: c.BasePathOrDefault(),
+		Selector: selectorQuery,
+		State:    c.JobManager.State(),
+		Jobs:     jobs,
+	})
+}
+
+func (c Controller) getJobView(r *web.Ctx) web.Result {
+	job, err := c.job(r)
+	if err != nil {
+		return c.viewError(r, err)
+	}
+	return r.Views.View(viewNameJob, jobViewModel{
+		BasePath: c.BasePathOrDefault(),
+		Job:      job,
+	})
+}
+
+// viewAction runs an action from a dashboard form and redirects back to the job.
+func (c Controller) viewAction(action func(*web.Ctx) error) web.Action {
+	return func(r *web.Ctx) web.Result {
+		if err := action(r); err != nil {
+			return c.viewError(r, err)
+		}
+		name, _ := r.RouteParam("name")
+		return web.RedirectWithMethod(http.MethodGet, c.BasePathOrDefault()+"/job/"+url.PathEscape(name))
+	}
+}
+
+func (c Controller) viewError(r *web.Ctx, err error) web.Result {
+	switch errorStatusCode(err) {
+	case http.StatusNotFound:
+		return r.Views.NotFound()
+	case http.StatusBadRequest, http.StatusConflict:
+		return r.Views.BadRequest(err)
+	default:
+		return r.Views.InternalError(err)
+	}
+}
+
+//
+// actions
+//
+
+func (c Controller) run(r *web.Ctx) (*cron.JobInvocation, error) {
+	name, err := r.RouteParam("name")
+	if err != nil {
+		return nil, err
+	}
+	parameters, err := c.parameters(r)
+	if err != nil {
+		return nil, err
+	}
+	// the invocation outlives the request, so it can't use the request context.
+	ctx := cron.WithJobParameterValues(context.Background(), parameters)
+	ji, _, err := c.JobManager.RunJobContext(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+	return c.invocation(name, ji.ID), nil
+}
+
+func (c Controller) cancel(r *web.Ctx) error {
+	name, err := r.RouteParam("name")
+	if err != nil {
+		return err
+	}
+	return c.JobManager.CancelJob(name)
+}
+
+func (c Controller) enable(r *web.Ctx) error {
+	name, err := r.RouteParam("name")
+	if err != nil {
+		return err
+	}
+	return c.JobManager.EnableJobs(name)
+}
+
+func (c Controller) disable(r *web.Ctx) error {
+	name, err := r.RouteParam("name")
+	if err != nil {
+		return err
+	}
+	return c.JobManager.DisableJobs(name)
+}
+
+//
+// helpers
+//
+
+// jobs returns the status of each job whose labels match the `selector` query value, if one is given.
+func (c Controller) jobs(r *web.Ctx) ([]JobStatus, error) {
+	var sel selector.Selector
+	if selectorQuery, _ := r.QueryValue("selector"); selectorQuery != "" {
+		var err error
+		if sel, err = selector.Parse(selectorQuery); err != nil {
+			return nil, ex.New(ErrInvalidSelector, ex.OptInner(err))
+		}
+	}
+
+	c.JobManager.Lock()
+	schedulers := make([]*cron.JobScheduler, 0, len(c.JobManager.Jobs))
+	for _, js := range c.JobManager.Jobs {
+		schedulers = append(schedulers, js)
+	}
+	c.JobManager.Unlock()
+	sort.Sort(cron.JobSchedulersByJobNameAsc(schedulers))
+
+	jobs := make([]JobStatus, 0, len(schedulers))
+	for _, js := range schedulers {
+		if sel != nil && !sel.Matches(js.Labels()) {
+			continue
+		}
+		history, err := c.history(r.Context(), js)
+		if err != nil {
+			return nil, err
+		}
+		jobs = append(jobs, NewJobStatus(js, history))
+	}
+	return jobs, nil
+}
+
+func (c Controller) job(r *web.Ctx) (JobStatus, error) {
+	name, err := r.RouteParam("name")
+	if err != nil {
+		return JobStatus{}, err
+	}
+	js, err := c.JobManager.Job(name)
+	if err != nil {
+		return JobStatus{}, err
+	}
+	history, err := c.history(r.Context(), js)
+	if err != nil {
+		return JobStatus{}, err
+	}
+	return NewJobStatus(js, history), nil
+}
+
+// history returns the recent invocations of a job, newest first.
+func (c Controller) history(ctx context.Context, js *cron.JobScheduler) ([]*cron.JobInvocation, error) {
+	if js.HistoryStore == nil {
+		if last := js.Last(); last != nil {
+			return []*cron.JobInvocation{last}, nil
+		}
+		return nil, nil
+	}
+	return js.History(ctx, cron.JobHistoryQuery{Limit: c.HistoryLimitOrDefault()})
+}
+
+// invocation returns a copy of a job's running or last invocation, as the invocation returned when
+// a job is run can be changed by the scheduler at any time.
+func (c Controller) invocation(name, id string) *cron.JobInvocation {
+	if js, err := c.JobManager.Job(name); err == nil {
+		for _, ji := range js.Running() {
+			if ji.ID == id {
+				return ji
+			}
+		}
+		if last := js.Last(); last != nil && last.ID == id {
+			return last
+		}
+	}
+	return &cron.JobInvocation{ID: id, JobName: name}
+}
+
+// parameters returns the parameters to run a job with from a json object body, or
+// from the `key=value` lines of a `parameters` form value.
+func (c Controller) parameters(r *web.Ctx) (cron.JobParameters, error) {
+	if strings.HasPrefix(r.Request.Header.Get(webutil.HeaderContentType), "application/json") {
+		body, err := r.PostBody()
+		if err != nil {
+			return nil, err
+		}
+		if len(strings.TrimSpace(string(body))) == 0 {
+			return nil, nil
+		}
+		var parameters cron.JobParameters
+		if err := json.Unmarshal(body, &parameters); err != nil {
+			return nil, ex.New(ErrInvalidParameters, ex.OptInner(err))
+		}
+		return parameters, nil
+	}
+
+	value, _ := r.FormValue("parameters")
+	if strings.TrimSpace(value) == "" {
+		return nil, nil
+	}
+	parameters := make(cron.JobParameters)
+	for _, line := range strings.Split(value, "\n") {
+		if line = strings.TrimSpace(line); line == "" {
+			continue
+		}
+		pair := strings.SplitN(line, "=", 2)
+		if len(pair) != 2 || strings.TrimSpace(pair[0]) == "" {
+			return nil, ex.New(ErrInvalidParameters, ex.OptMessagef("line: %q; parameters must be `key=value`", line))
+		}
+		parameters[strings.TrimSpace(pair[0])] = strings.TrimSpace(pair[1])
+	}
+	return parameters, nil
+}
+
+// errorStatusCode returns the status code for an error.
+func errorStatusCode(err error) int {
+	switch {
+	case cron.IsJobNotLoaded(err), cron.IsJobNotFound(err):
+		return http.StatusNotFound
+	case cron.IsJobAlreadyRunning(err), cron.IsJobConcurrencyLimit(err), cron.IsJobLocked(err):
+		return http.StatusConflict
+	case ex.Is(err, ErrInvalidSelector), ex.Is(err, ErrInvalidParameters), web.IsErrParameterMissing(err):
+		return http.StatusBadRequest
+	default:
+		return http.StatusInternalServerError
+	}
+}
@@ -0,0 +1,231 @@
+package cronweb
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/blend/go-sdk/assert"
+	"github.com/blend/go-sdk/cron"
+	"github.com/blend/go-sdk/r2"
+	"github.com/blend/go-sdk/web"
+)
+
+type testJobs struct {
+	JobManager *cron.JobManager
+	Parameters chan cron.JobParameters
+	Started    chan struct{}
+}
+
+func newTestJobs(t *testing.T) testJobs {
+	assert := assert.New(t)
+
+	jobs := testJobs{
+		JobManager: cron.New(),
+		Parameters: make(chan cron.JobParameters, 1),
+		Started:    make(chan struct{}, 1),
+	}
+	assert.Nil(jobs.JobManager.LoadJobs(
+		cron.NewJob(
+			cron.OptJobName("alpha"),
+			cron.OptJobSchedule(cron.Every(time.Hour)),
+			cron.OptJobLabels(map[string]string{"team": "a"}),
+			cron.OptJobAction(func(ctx context.Context) error {
+				jobs.Parameters <- cron.GetJobParameterValues(ctx)
+				return nil
+			}),
+		),
+		cron.NewJob(
+			cron.OptJobName("beta"),
+			cron.OptJobLabels(map[string]string{"team": "b"}),
+			cron.OptJobAction(func(ctx context.Context) error {
+				jobs.Started <- struct{}{}
+				<-ctx.Done()
+				return nil
+			}),
+		),
+	))
+	return jobs
+}
+
+func TestControllerGetJobs(t *testing.T) {
+	assert := assert.New(t)
+
+	jobs := newTestJobs(t)
+	assert.Nil(jobs.JobManager.StartAsync())
+	defer jobs.JobManager.Stop()
+
+	app := web.MustNew()
+	app.Register(NewController(jobs.JobManager))
+
+	var statuses []JobStatus
+	res, err := web.MockGet(app, "/cron/api/jobs").JSON(&statuses)
+	assert.Nil(err)
+	assert.Equal(http.StatusOK, res.StatusCode)
+	assert.Len(statuses, 2)
+	assert.Equal("alpha", statuses[0].Name)
+	assert.Equal(cron.Every(time.Hour).String(), statuses[0].Schedule)
+	assert.NotNil(statuses[0].NextRuntime)
+	assert.Equal("a", statuses[0].Labels["team"])
+	assert.Equal("beta", statuses[1].Name)
+	assert.Nil(statuses[1].NextRuntime)
+
+	res, err = web.MockGet(app, "/cron/api/jobs", r2.OptQueryValue("selector", "team=b")).JSON(&statuses)
+	assert.Nil(err)
+	assert.Equal(http.StatusOK, res.StatusCode)
+	assert.Len(statuses, 1)
+	assert.Equal("beta", statuses[0].Name)
+
+	res, err = web.MockGet(app, "/cron/api/jobs", r2.OptQueryValue("selector", "team in (")).Discard()
+	assert.Nil(err)
+	assert.Equal(http.StatusBadRequest, res.StatusCode)
+
+	res, err = web.MockGet(app, "/cron/api/job/gamma").Discard()
+	assert.Nil(err)
+	assert.Equal(http.StatusNotFound, res.StatusCode)
+}
+
+func TestControllerRun(t *testing.T) {
+	assert := assert.New(t)
+
+	jobs := newTestJobs(t)
+	app := web.MustNew()
+	app.Register(NewController(jobs.JobManager, OptBasePath("/admin/cron/")))
+
+	var invocation Invocation
+	res, err := web.MockPost(app, "/admin/cron/api/job/alpha/run", nil, r2.OptJSONBody(cron.JobParameters{"foo": "bar"})).JSON(&invocation)
+	assert.Nil(err)
+	assert.Equal(http.StatusOK, res.StatusCode)
+	assert.Equal("alpha", invocation.JobName)
+	assert.Equal("bar", (<-jobs.Parameters)["foo"])
+
+	res, err = web.MockPost(app, "/admin/cron/api/job/alpha/run", nil, r2.OptJSONBody([]string{"foo"})).Discard()
+	assert.Nil(err)
+	assert.Equal(http.StatusBadRequest, res.StatusCode)
+
+	// the job is running, so it can't be run again until it's cancelled.
+	res, err = web.MockPost(app, "/admin/cron/api/job/beta/run", nil).Discard()
+	assert.Nil(err)
+	assert.Equal(http.StatusOK, res.StatusCode)
+	<-jobs.Started
+	assert.True(jobs.JobManager.IsJobRunning("beta"))
+
+	var status JobStatus
+	res, err = web.MockGet(app, "/admin/cron/api/job/beta").JSON(&status)
+	assert.Nil(err)
+	assert.Equal(http.StatusOK, res.StatusCode)
+	assert.Len(status.Current, 1)
+	assert.Equal(cron.JobInvocationStatusRunning, status.Current[0].Status)
+
+	res, err = web.MockPost(app, "/admin/cron/api/job/beta/run", nil).Discard()
+	assert.Nil(err)
+	assert.Equal(http.StatusConflict, res.StatusCode)
+
+	res, err = web.MockPost(app, "/admin/cron/api/job/beta/cancel", nil).Discard()
+	assert.Nil(err)
+	assert.Equal(http.StatusOK, res.StatusCode)
+	js, err := jobs.JobManager.Job("beta")
+	assert.Nil(err)
+	for !js.IsIdle() {
+		time.Sleep(time.Millisecond)
+	}
+	res, err = web.MockGet(app, "/admin/cron/api/job/beta").JSON(&status)
+	assert.Nil(err)
+	assert.Equal(http.StatusOK, res.StatusCode)
+	assert.Empty(status.Current)
+	assert.Len(status.History, 1)
+	assert.Equal(cron.JobInvocationStatusCancelled, status.History[0].Status)
+}
+
+func TestControllerEnableDisable(t *testing.T) {
+	assert := assert.New(t)
+
+	jobs := newTestJobs(t)
+	app := web.MustNew()
+	app.Register(NewController(jobs.JobManager))
+
+	res, err := web.MockPost(app, "/cron/api/job/alpha/disable", nil).Discard()
+	assert.Nil(err)
+	assert.Equal(http.StatusOK, res.StatusCode)
+	assert.True(jobs.JobManager.IsJobDisabled("alpha"))
+
+	res, err = web.MockPost(app, "/cron/api/job/alpha/enable", nil).Discard()
+	assert.Nil(err)
+	assert.Equal(http.StatusOK, res.StatusCode)
+	assert.False(jobs.JobManager.IsJobDisabled("alpha"))
+
+	res, err = web.MockPost(app, "/cron/api/job/gamma/disable", nil).Discard()
+	assert.Nil(err)
+	assert.Equal(http.StatusNotFound, res.StatusCode)
+}
+
+func TestControllerViews(t *testing.T) {
+	assert := assert.New(t)
+
+	jobs := newTestJobs(t)
+	app := web.MustNew()
+	app.Register(NewController(jobs.JobManager))
+
+	contents, res, err := web.MockGet(app, "/cron").Bytes()
+	assert.Nil(err)
+	assert.Equal(http.StatusOK, res.StatusCode)
+	assert.True(strings.Contains(string(contents), `href="/cron/job/alpha"`), string(contents))
+	assert.True(strings.Contains(string(contents), `href="/cron/job/beta"`), string(contents))
+
+	contents, res, err = web.MockGet(app, "/cron", r2.OptQueryValue("selector", "team=a")).Bytes()
+	assert.Nil(err)
+	assert.Equal(http.StatusOK, res.StatusCode)
+	assert.False(strings.Contains(string(contents), `href="/cron/job/beta"`), string(contents))
+
+	// running a job from the form redirects back to the job.
+	contents, res, err = web.MockPost(app, "/cron/job/alpha/run", nil, r2.OptPostFormValue("parameters", "foo=bar\nbuzz = fuzz\n")).Bytes()
+	assert.Nil(err)
+	assert.Equal(http.StatusOK, res.StatusCode)
+	assert.Equal("/cron/job/alpha", res.Request.URL.Path)
+	assert.True(strings.Contains(string(contents), "<h2>alpha</h2>"), string(contents))
+	parameters := <-jobs.Parameters
+	assert.Equal("bar", parameters["foo"])
+	assert.Equal("fuzz", parameters["buzz"])
+
+	res, err = web.MockPost(app, "/cron/job/alpha/run", nil, r2.OptPostFormValue("parameters", "foo")).Discard()
+	assert.Nil(err)
+	assert.Equal(http.StatusBadRequest, res.StatusCode)
+
+	res, err = web.MockGet(app, "/cron/job/gamma").Discard()
+	assert.Nil(err)
+	assert.Equal(http.StatusNotFound, res.StatusCode)
+}
+
+func TestControllerMiddleware(t *testing.T) {
+	assert := assert.New(t)
+
+	jobs := newTestJobs(t)
+	app := web.MustNew()
+	forbidden := func(action web.Action) web.Action {
+		return func(r *web.Ctx) web.Result {
+			if r.Request.Header.Get("X-Authorized") != "true" {
+				return web.JSON.Status(http.StatusForbidden)
+			}
+			return action(r)
+		}
+	}
+	app.Register(NewController(jobs.JobManager, OptMiddleware(forbidden)))
+
+	for _, path := range []string{"/cron", "/cron/job/alpha", "/cron/api/jobs", "/cron/api/job/alpha"} {
+		res, err := web.MockGet(app, path).Discard()
+		assert.Nil(err)
+		assert.Equal(http.StatusForbidden, res.StatusCode, path)
+	}
+	for _, path := range []string{"/cron/job/alpha/run", "/cron/api/job/alpha/run", "/cron/api/job/alpha/disable"} {
+		res, err := web.MockPost(app, path, nil).Discard()
+		assert.Nil(err)
+		assert.Equal(http.StatusForbidden, res.StatusCode, path)
+	}
+	assert.False(jobs.JobManager.IsJobDisabled("alpha"))
+
+	res, err := web.MockGet(app, "/cron/api/jobs", r2.OptHeaderValue("X-Authorized", "true")).Discard()
+	assert.Nil(err)
+	assert.Equal(http.StatusOK, res.StatusCode)
+}
@@ -0,0 +1,18 @@
+/*
+Package cronweb includes a `web` controller for managing the jobs of a `cron.JobManager`.
+
+The controller serves a JSON api and a simple dashboard that list the jobs with their schedules, next runtimes,
+labels and current and recent invocations, and that let operators run jobs (with parameters), cancel them,
+and enable or disable them:
+
+	app := web.MustNew()
+	app.Register(cronweb.NewController(jm))
+
+Jobs can be filtered by their labels with a `selector` query parameter, e.g. `/cron/api/jobs?selector=enabled=true`.
+
+The controller doesn't authenticate requests, and it serves invocation parameters and captured output unredacted;
+protect its routes with the middleware the app uses to authenticate requests:
+
+	app.Register(cronweb.NewController(jm, cronweb.OptMiddleware(authed)))
+*/
+package cronweb
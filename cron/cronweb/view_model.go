@@ -0,0 +1,112 @@
+package cronweb
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/blend/go-sdk/cron"
+	"github.com/blend/go-sdk/timeutil"
+)
+
+// JobStatus is the status of a job.
+type JobStatus struct {
+	Name        string                 `json:"name"`
+	Description string                 `json:"description,omitempty"`
+	Schedule    string                 `json:"schedule,omitempty"`
+	State       cron.JobSchedulerState `json:"state"`
+	Disabled    bool                   `json:"disabled"`
+	NextRuntime *time.Time             `json:"nextRuntime,omitempty"`
+	Labels      map[string]string      `json:"labels"`
+	Current     []Invocation           `json:"current"`
+	History     []Invocation           `json:"history"`
+}
+
+// Invocation is a job invocation.
+type Invocation struct {
+	ID         string                   `json:"id"`
+	JobName    string                   `json:"jobName"`
+	Status     cron.JobInvocationStatus `json:"status"`
+	Started    time.Time                `json:"started"`
+	Complete   *time.Time               `json:"complete,omitempty"`
+	Elapsed    float64                  `json:"elapsed"`
+	Err        string                   `json:"err,omitempty"`
+	Parameters cron.JobParameters       `json:"parameters,omitempty"`
+	Attempts   int                      `json:"attempts,omitempty"`
+	Output     string                   `json:"output,omitempty"`
+	Children   []Invocation             `json:"children,omitempty"`
+}
+
+// ElapsedDuration returns the elapsed time of the invocation as a duration.
+func (i Invocation) ElapsedDuration() time.Duration {
+	return timeutil.FromMilliseconds(i.Elapsed)
+}
+
+// NewJobStatus returns the status of a job from its scheduler and its recent invocations.
+func NewJobStatus(js *cron.JobScheduler, history []*cron.JobInvocation) JobStatus {
+	status := JobStatus{
+		Name:        js.Name(),
+		Description: js.Description(),
+		State:       js.State(),
+		Disabled:    js.Disabled(),
+		Labels:      js.Labels(),
+		Current:     NewInvocations(js.Running()),
+		History:     NewInvocations(history),
+	}
+	if js.JobSchedule != nil {
+		status.Schedule = fmt.Sprint(js.JobSchedule)
+	}
+	if nextRuntime := js.GetNextRuntime(); !nextRuntime.IsZero() {
+		status.NextRuntime = &nextRuntime
+	}
+	return status
+}
+
+// NewInvocations returns invocations from job invocations.
+func NewInvocations(invocations []*cron.JobInvocation) []Invocation {
+	output := make([]Invocation, 0, len(invocations))
+	for _, ji := range invocations {
+		output = append(output, NewInvocation(ji))
+	}
+	return output
+}
+
+// NewInvocation returns an invocation from a job invocation.
+func NewInvocation(ji *cron.JobInvocation) Invocation {
+	invocation := Invocation{
+		ID:         ji.ID,
+		JobName:    ji.JobName,
+		Status:     ji.Status,
+		Started:    ji.Started,
+		Elapsed:    timeutil.Milliseconds(ji.Elapsed()),
+		Parameters: ji.Parameters,
+		Attempts:   len(ji.Attempts),
+		Output:     ji.Output,
+	}
+	if !ji.Complete.IsZero() {
+		complete := ji.Complete
+		invocation.Complete = &complete
+	} else if !ji.Started.IsZero() {
+		invocation.Elapsed = timeutil.Milliseconds(cron.Now().Sub(ji.Started))
+	}
+	if ji.Err != nil {
+		invocation.Err = ji.Err.Error()
+	}
+	if len(ji.Children) > 0 {
+		invocation.Children = NewInvocations(ji.Children)
+	}
+	return invocation
+}
+
+// jobsViewModel is the view model for the jobs view.
+type jobsViewModel struct {
+	BasePath string
+	Selector string
+	State    cron.JobManagerState
+	Jobs     []JobStatus
+}
+
+// jobViewModel is the view model for the job view.
+type jobViewModel struct {
+	BasePath string
+	Job      JobStatus
+}
@@ -0,0 +1,22 @@
+package cronweb
+
+import (
+	"testing"
+	"time"
+
+	"github.com/blend/go-sdk/assert"
+	"github.com/blend/go-sdk/cron"
+)
+
+func TestNewInvocationElapsed(t *testing.T) {
+	assert := assert.New(t)
+
+	started := time.Date(2020, 01, 02, 03, 04, 05, 0, time.UTC)
+	invocation := NewInvocation(&cron.JobInvocation{
+		Started:  started,
+		Complete: started.Add(1500 * time.Millisecond),
+		Status:   cron.JobInvocationStatusSuccess,
+	})
+	assert.Equal(float64(1500), invocation.Elapsed)
+	assert.Equal(1500*time.Millisecond, invocation.ElapsedDuration())
+}
@@ -0,0 +1,90 @@
+package cronweb
+
+const (
+	viewNameJobs = "cronweb_jobs"
+	viewNameJob  = "cronweb_job"
+)
+
+const viewStyle = `<style>
+body { font-family: sans-serif; margin: 2em; }
+table { border-collapse: collapse; width: 100%; margin-bottom: 2em; }
+th, td { text-align: left; padding: 0.3em 0.6em; border-bottom: 1px solid #ddd; vertical-align: top; }
+.labels span { display: inline-block; background: #eee; border-radius: 3px; padding: 0 0.3em; margin: 0 0.2em 0.2em 0; font-size: 0.85em; }
+.status-success { color: #2a7; } .status-errored { color: #c33; } .status-running { color: #27c; } .status-cancelled, .status-skipped { color: #888; }
+form.inline { display: inline; }
+pre { white-space: pre-wrap; margin: 0; }
+</style>`
+
+const viewJobs = `{{ define "cronweb_jobs" }}<html><head><title>Jobs</title>` + viewStyle + `</head><body>
+{{ with .ViewModel }}
+<h2>Jobs <small>({{ .State }})</small></h2>
+<form method="GET" action="{{ .BasePath }}">
+	<input type="text" name="selector" value="{{ .Selector }}" placeholder="selector, e.g. enabled=true" size="40" />
+	<input type="submit" value="Filter" />
+</form>
+<table>
+	<tr><th>Name</th><th>Schedule</th><th>Enabled</th><th>Next Run</th><th>Running</th><th>Last Run</th><th>Labels</th></tr>
+	{{ range .Jobs }}
+	<tr>
+		<td><a href="{{ $.ViewModel.BasePath }}/job/{{ .Name }}">{{ .Name }}</a><br /><small>{{ .Description }}</small></td>
+		<td>{{ .Schedule }}</td>
+		<td>{{ if .Disabled }}no{{ else }}yes{{ end }}</td>
+		<td>{{ with .NextRuntime }}{{ .Format "2006-01-02 15:04:05 MST" }}{{ end }}</td>
+		<td>{{ len .Current }}</td>
+		<td>{{ with .History }}{{ with index . 0 }}<span class="status-{{ .Status }}">{{ .Status }}</span> {{ .Started.Format "2006-01-02 15:04:05 MST" }}{{ end }}{{ end }}</td>
+		<td class="labels">{{ range $key, $value := .Labels }}<span>{{ $key }}={{ $value }}</span>{{ end }}</td>
+	</tr>
+	{{ else }}
+	<tr><td colspan="7">No jobs</td></tr>
+	{{ end }}
+</table>
+{{ end }}
+</body></html>{{ end }}`
+
+const viewJob = `{{ define "cronweb_invocations" }}<table>
+	<tr><th>ID</th><th>Status</th><th>Started</th><th>Elapsed</th><th>Parameters</th><th>Error</th></tr>
+	{{ range . }}
+	<tr>
+		<td><small>{{ .ID }}</small></td>
+		<td class="status-{{ .Status }}">{{ .Status }}{{ if gt .Attempts 1 }} ({{ .Attempts }} attempts){{ end }}</td>
+		<td>{{ if not .Started.IsZero }}{{ .Started.Format "2006-01-02 15:04:05 MST" }}{{ end }}</td>
+		<td>{{ .ElapsedDuration | duration_round_millis }}</td>
+		<td>{{ range $key, $value := .Parameters }}{{ $key }}={{ $value }}<br />{{ end }}</td>
+		<td><pre>{{ .Err }}</pre></td>
+	</tr>
+	{{ if .Children }}<tr><td></td><td colspan="5">{{ template "cronweb_invocations" .Children }}</td></tr>{{ end }}
+	{{ if .Output }}<tr><td></td><td colspan="5"><pre>{{ .Output }}</pre></td></tr>{{ end }}
+	{{ else }}
+	<tr><td colspan="6">None</td></tr>
+	{{ end }}
+</table>{{ end }}
+{{ define "cronweb_job" }}<html><head><title>{{ .ViewModel.Job.Name }}</title>` + viewStyle + `</head><body>
+<p><a href="{{ .ViewModel.BasePath }}">Jobs</a></p>
+{{ with .ViewModel.Job }}
+<h2>{{ .Name }}</h2>
+<p>{{ .Description }}</p>
+<table>
+	<tr><th>Schedule</th><td>{{ .Schedule }}</td></tr>
+	<tr><th>Scheduler</th><td>{{ .State }}</td></tr>
+	<tr><th>Enabled</th><td>{{ if .Disabled }}no{{ else }}yes{{ end }}</td></tr>
+	<tr><th>Next Run</th><td>{{ with .NextRuntime }}{{ .Format "2006-01-02 15:04:05 MST" }}{{ end }}</td></tr>
+	<tr><th>Labels</th><td class="labels">{{ range $key, $value := .Labels }}<span>{{ $key }}={{ $value }}</span>{{ end }}</td></tr>
+</table>
+<form method="POST" action="{{ $.ViewModel.BasePath }}/job/{{ .Name }}/run">
+	<textarea name="parameters" rows="3" cols="40" placeholder="key=value (one per line)"></textarea><br />
+	<input type="submit" value="Run" />
+</form>
+<p>
+	<form class="inline" method="POST" action="{{ $.ViewModel.BasePath }}/job/{{ .Name }}/cancel"><input type="submit" value="Cancel" /></form>
+	{{ if .Disabled }}
+	<form class="inline" method="POST" action="{{ $.ViewModel.BasePath }}/job/{{ .Name }}/enable"><input type="submit" value="Enable" /></form>
+	{{ else }}
+	<form class="inline" method="POST" action="{{ $.ViewModel.BasePath }}/job/{{ .Name }}/disable"><input type="submit" value="Disable" /></form>
+	{{ end }}
+</p>
+<h3>Running</h3>
+{{ template "cronweb_invocations" .Current }}
+<h3>Recent</h3>
+{{ template "cronweb_invocations" .History }}
+{{ end }}
+</body></html>{{ end }}`
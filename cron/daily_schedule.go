@@ -13,33 +13,59 @@ var (
 
 // WeeklyAtUTC returns a schedule that fires on every of the given days at the given time by hour, minute and second in UTC.
 func WeeklyAtUTC(hour, minute, second int, days ...time.Weekday) Schedule {
+	return WeeklyAt(hour, minute, second, nil, days...)
+}
+
+// WeeklyAt returns a schedule that fires on every of the given days at the given time by hour, minute and second in a given location.
+// If the location is nil, UTC is used.
+func WeeklyAt(hour, minute, second int, location *time.Location, days ...time.Weekday) Schedule {
 	dayOfWeekMask := uint(0)
 	for _, day := range days {
 		dayOfWeekMask = dayOfWeekMask | 1<<uint(day)
 	}
 
-	return &DailySchedule{DayOfWeekMask: dayOfWeekMask, TimeOfDayUTC: time.Date(0, 0, 0, hour, minute, second, 0, time.UTC)}
+	return &DailySchedule{DayOfWeekMask: dayOfWeekMask, TimeOfDayUTC: time.Date(0, 0, 0, hour, minute, second, 0, time.UTC), Location: location}
 }
 
 // DailyAtUTC returns a schedule that fires every day at the given hour, minute and second in UTC.
 func DailyAtUTC(hour, minute, second int) Schedule {
-	return &DailySchedule{DayOfWeekMask: AllDaysMask, TimeOfDayUTC: time.Date(0, 0, 0, hour, minute, second, 0, time.UTC)}
+	return DailyAt(hour, minute, second, nil)
+}
+
+// DailyAt returns a schedule that fires every day at the given hour, minute and second in a given location.
+// If the location is nil, UTC is used.
+func DailyAt(hour, minute, second int, location *time.Location) Schedule {
+	return &DailySchedule{DayOfWeekMask: AllDaysMask, TimeOfDayUTC: time.Date(0, 0, 0, hour, minute, second, 0, time.UTC), Location: location}
 }
 
 // WeekdaysAtUTC returns a schedule that fires every week day at the given hour, minute and second in UTC>
 func WeekdaysAtUTC(hour, minute, second int) Schedule {
-	return &DailySchedule{DayOfWeekMask: WeekDaysMask, TimeOfDayUTC: time.Date(0, 0, 0, hour, minute, second, 0, time.UTC)}
+	return WeekdaysAt(hour, minute, second, nil)
+}
+
+// WeekdaysAt returns a schedule that fires every week day at the given hour, minute and second in a given location.
+// If the location is nil, UTC is used.
+func WeekdaysAt(hour, minute, second int, location *time.Location) Schedule {
+	return &DailySchedule{DayOfWeekMask: WeekDaysMask, TimeOfDayUTC: time.Date(0, 0, 0, hour, minute, second, 0, time.UTC), Location: location}
 }
 
 // WeekendsAtUTC returns a schedule that fires every weekend day at the given hour, minut and second.
 func WeekendsAtUTC(hour, minute, second int) Schedule {
-	return &DailySchedule{DayOfWeekMask: WeekendDaysMask, TimeOfDayUTC: time.Date(0, 0, 0, hour, minute, second, 0, time.UTC)}
+	return WeekendsAt(hour, minute, second, nil)
+}
+
+// WeekendsAt returns a schedule that fires every weekend day at the given hour, minute and second in a given location.
+// If the location is nil, UTC is used.
+func WeekendsAt(hour, minute, second int, location *time.Location) Schedule {
+	return &DailySchedule{DayOfWeekMask: WeekendDaysMask, TimeOfDayUTC: time.Date(0, 0, 0, hour, minute, second, 0, time.UTC), Location: location}
 }
 
 // DailySchedule is a schedule that fires every day that satisfies the DayOfWeekMask at the given TimeOfDayUTC.
+// If Location is set, TimeOfDayUTC is the time of day in that location, otherwise it is in UTC.
 type DailySchedule struct {
 	DayOfWeekMask uint
 	TimeOfDayUTC  time.Time
+	Location      *time.Location
 }
 
 func (ds DailySchedule) String() string {
@@ -50,9 +76,9 @@ func (ds DailySchedule) String() string {
 				days = append(days, d.String())
 			}
 		}
-		return fmt.Sprintf("%s on %s each week", ds.TimeOfDayUTC.Format(time.RFC3339), strings.Join(days, ", "))
+		return fmt.Sprintf("%s on %s each week%s", ds.TimeOfDayUTC.Format(time.RFC3339), strings.Join(days, ", "), formatLocation(ds.Location))
 	}
-	return fmt.Sprintf("%s every day", ds.TimeOfDayUTC.Format(time.RFC3339))
+	return fmt.Sprintf("%s every day%s", ds.TimeOfDayUTC.Format(time.RFC3339), formatLocation(ds.Location))
 }
 
 func (ds DailySchedule) checkDayOfWeekMask(day time.Weekday) bool {
@@ -63,10 +89,11 @@ func (ds DailySchedule) checkDayOfWeekMask(day time.Weekday) bool {
 
 // Next implements Schedule.
 func (ds DailySchedule) Next(after time.Time) time.Time {
-	if after.IsZero() {
-		after = Now()
-	}
+	return nextInLocation(after, ds.Location, ds.next)
+}
 
+// next returns the next wall clock time after a given wall clock time.
+func (ds DailySchedule) next(after time.Time) time.Time {
 	todayInstance := time.Date(after.Year(), after.Month(), after.Day(), ds.TimeOfDayUTC.Hour(), ds.TimeOfDayUTC.Minute(), ds.TimeOfDayUTC.Second(), 0, time.UTC)
 	for day := 0; day < 8; day++ {
 		next := todayInstance.AddDate(0, 0, day) //the first run here it should be adding nothing, i.e. returning todayInstance ...
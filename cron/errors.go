@@ -17,6 +17,21 @@ const (
 
 	// ErrJobAlreadyRunning is a common error.
 	ErrJobAlreadyRunning ex.Class = "job already running"
+
+	// ErrJobLocked is returned by job lockers if a lease on a job is held elsewhere.
+	ErrJobLocked ex.Class = "job locked"
+
+	// ErrJobLeaseLost is returned by job leases if they cannot be renewed.
+	ErrJobLeaseLost ex.Class = "job lease lost"
+
+	// ErrJobConcurrencyLimit is returned if a job can't start because the job manager's concurrency limit was reached.
+	ErrJobConcurrencyLimit ex.Class = "job concurrency limit reached"
+
+	// ErrWorkflowInvalid is returned if a workflow's steps are invalid.
+	ErrWorkflowInvalid ex.Class = "workflow invalid"
+
+	// ErrWorkflowFailed is returned if any of a workflow's steps failed.
+	ErrWorkflowFailed ex.Class = "workflow failed"
 )
 
 // IsJobNotLoaded returns if the error is a job not loaded error.
@@ -43,3 +58,13 @@ func IsJobCancelled(err error) bool {
 func IsJobAlreadyRunning(err error) bool {
 	return ex.Is(err, ErrJobAlreadyRunning)
 }
+
+// IsJobLocked returns if the error is a job locked error.
+func IsJobLocked(err error) bool {
+	return ex.Is(err, ErrJobLocked)
+}
+
+// IsJobConcurrencyLimit returns if the error is a job concurrency limit error.
+func IsJobConcurrencyLimit(err error) bool {
+	return ex.Is(err, ErrJobConcurrencyLimit)
+}
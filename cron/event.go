@@ -52,6 +52,11 @@ func OptEventErr(err error) EventOption {
 	return func(e *Event) { e.Err = err }
 }
 
+// OptEventAttempt sets a field.
+func OptEventAttempt(attempt int) EventOption {
+	return func(e *Event) { e.Attempt = attempt }
+}
+
 // OptEventElapsed sets a field.
 func OptEventElapsed(elapsed time.Duration) EventOption {
 	return func(e *Event) { e.Elapsed = elapsed }
@@ -63,6 +68,7 @@ type Event struct {
 	JobName       string
 	JobInvocation string
 	Err           error
+	Attempt       int
 	Elapsed       time.Duration
 }
 
@@ -76,6 +82,10 @@ func (e Event) Complete() bool {
 
 // WriteText implements logger.TextWritable.
 func (e Event) WriteText(tf logger.TextFormatter, wr io.Writer) {
+	if e.Attempt > 0 {
+		io.WriteString(wr, logger.Space)
+		io.WriteString(wr, fmt.Sprintf("attempt %d", e.Attempt))
+	}
 	if e.Elapsed > 0 {
 		io.WriteString(wr, logger.Space)
 		io.WriteString(wr, fmt.Sprintf("(%v)", e.Elapsed))
@@ -84,9 +94,13 @@ func (e Event) WriteText(tf logger.TextFormatter, wr io.Writer) {
 
 // Decompose implements logger.JSONWritable.
 func (e Event) Decompose() map[string]interface{} {
-	return map[string]interface{}{
+	output := map[string]interface{}{
 		"jobName": e.JobName,
 		"err":     e.Err,
 		"elapsed": timeutil.Milliseconds(e.Elapsed),
 	}
+	if e.Attempt > 0 {
+		output["attempt"] = e.Attempt
+	}
+	return output
 }
@@ -0,0 +1,73 @@
+package cron
+
+import (
+	"fmt"
+	"math/rand"
+	"sync"
+	"time"
+)
+
+// Interface assertions.
+var (
+	_ Schedule     = (*JitterSchedule)(nil)
+	_ fmt.Stringer = (*JitterSchedule)(nil)
+)
+
+// Jitter returns a schedule that delays each time of a given schedule by a random duration up to a window,
+// e.g. so that a job loaded in every replica of a service doesn't run in all of them at the same time.
+func Jitter(schedule Schedule, window time.Duration) *JitterSchedule {
+	return &JitterSchedule{
+		Schedule: schedule,
+		Window:   window,
+	}
+}
+
+// JitterSchedule delays each time of a schedule by a random duration up to a window.
+//
+// The times of the underlying schedule are still used to acquire leases from job lockers and to catch up missed
+// runs, so that each scheduled time only runs once across job managers even though it runs at a different time in each.
+type JitterSchedule struct {
+	Schedule Schedule
+	Window   time.Duration
+
+	mu        sync.Mutex
+	scheduled time.Time
+	jittered  time.Time
+}
+
+// String returns a string representation of the schedule.
+func (j *JitterSchedule) String() string {
+	return fmt.Sprintf("%v, with a jitter of up to %v", j.Schedule, j.Window)
+}
+
+// Next implements Schedule.
+func (j *JitterSchedule) Next(after time.Time) time.Time {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	// the next time is relative to the previous time of the underlying schedule, so the jitter doesn't accumulate.
+	if !after.IsZero() && after.Equal(j.jittered) {
+		after = j.scheduled
+	}
+	next := j.Schedule.Next(after)
+	if next.IsZero() {
+		return Zero
+	}
+	j.scheduled = next
+	j.jittered = next
+	if j.Window > 0 {
+		j.jittered = next.Add(time.Duration(rand.Int63n(int64(j.Window))))
+	}
+	return j.jittered
+}
+
+// Scheduled returns the time of the underlying schedule that the last time returned by `Next` was jittered from.
+// Other times are returned as they are.
+func (j *JitterSchedule) Scheduled(jittered time.Time) time.Time {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	if jittered.Equal(j.jittered) {
+		return j.scheduled
+	}
+	return jittered
+}
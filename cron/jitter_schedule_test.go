@@ -0,0 +1,38 @@
+package cron
+
+import (
+	"testing"
+	"time"
+
+	"github.com/blend/go-sdk/assert"
+)
+
+func TestJitterSchedule(t *testing.T) {
+	assert := assert.New(t)
+
+	schedule := Jitter(DailyAtUTC(12, 0, 0), 10*time.Minute)
+	next := time.Date(2020, 01, 02, 0, 0, 0, 0, time.UTC)
+	expected := time.Date(2020, 01, 02, 12, 0, 0, 0, time.UTC)
+	for x := 0; x < 10; x++ {
+		next = schedule.Next(next)
+		assert.False(next.Before(expected), next.String())
+		assert.True(next.Before(expected.Add(10*time.Minute)), next.String())
+		assert.Equal(expected, schedule.Scheduled(next))
+		expected = expected.AddDate(0, 0, 1)
+	}
+	other := time.Date(2021, 01, 02, 12, 03, 04, 0, time.UTC)
+	assert.Equal(other, schedule.Scheduled(other))
+
+	// the jitter doesn't accumulate for schedules relative to the previous time.
+	start := time.Date(2020, 01, 02, 0, 0, 0, 0, time.UTC)
+	interval := Jitter(Every(time.Hour), time.Minute)
+	next = start
+	for x := 1; x <= 10; x++ {
+		next = interval.Next(next)
+		assert.False(next.Before(start.Add(time.Duration(x)*time.Hour)), next.String())
+		assert.True(next.Before(start.Add(time.Duration(x)*time.Hour+time.Minute)), next.String())
+	}
+	assert.Equal("every 1h0m0s, with a jitter of up to 1m0s", interval.String())
+
+	assert.True(Jitter(Immediately(), 0).Next(Zero).Before(Now().Add(time.Millisecond)))
+}
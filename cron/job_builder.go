@@ -66,6 +66,41 @@ func OptJobShutdownGracePeriod(d time.Duration) JobBuilderOption {
 	return func(jb *JobBuilder) { jb.JobConfig.ShutdownGracePeriod = d }
 }
 
+// OptJobDistributedLock is a job builder sets if the job acquires a lease from the job manager's job locker before each invocation.
+func OptJobDistributedLock(distributedLock bool) JobBuilderOption {
+	return func(jb *JobBuilder) { jb.JobConfig.DistributedLock = ref.Bool(distributedLock) }
+}
+
+// OptJobRetryPolicy is a job builder sets the job retry policy.
+func OptJobRetryPolicy(policy RetryPolicy) JobBuilderOption {
+	return func(jb *JobBuilder) { jb.JobConfig.RetryPolicy = policy }
+}
+
+// OptJobConcurrencyPolicy is a job builder sets the job concurrency policy.
+func OptJobConcurrencyPolicy(policy ConcurrencyPolicy) JobBuilderOption {
+	return func(jb *JobBuilder) { jb.JobConfig.ConcurrencyPolicy = policy }
+}
+
+// OptJobMaxConcurrency is a job builder sets the maximum concurrent invocations with the `allow` concurrency policy.
+func OptJobMaxConcurrency(max int) JobBuilderOption {
+	return func(jb *JobBuilder) { jb.JobConfig.MaxConcurrency = max }
+}
+
+// OptJobCatchUpPolicy is a job builder sets the job catch-up policy.
+func OptJobCatchUpPolicy(policy CatchUpPolicy) JobBuilderOption {
+	return func(jb *JobBuilder) { jb.JobConfig.CatchUpPolicy = policy }
+}
+
+// OptJobCatchUpMaxRuns is a job builder sets the maximum number of missed runs that are run with the `each` catch-up policy.
+func OptJobCatchUpMaxRuns(max int) JobBuilderOption {
+	return func(jb *JobBuilder) { jb.JobConfig.CatchUpMaxRuns = max }
+}
+
+// OptJobOutputMaxBytes is a job builder sets the maximum number of bytes of output kept for each invocation.
+func OptJobOutputMaxBytes(max int) JobBuilderOption {
+	return func(jb *JobBuilder) { jb.JobConfig.OutputMaxBytes = max }
+}
+
 // OptJobDisabled is a job builder sets the job timeout provder.
 func OptJobDisabled(disabled bool) JobBuilderOption {
 	return func(jb *JobBuilder) { jb.JobConfig.Disabled = ref.Bool(disabled) }
@@ -96,6 +131,11 @@ func OptJobOnSuccess(handler func(context.Context)) JobBuilderOption {
 	return func(jb *JobBuilder) { jb.JobLifecycle.OnSuccess = handler }
 }
 
+// OptJobOnRetry sets a lifecycle hook.
+func OptJobOnRetry(handler func(context.Context)) JobBuilderOption {
+	return func(jb *JobBuilder) { jb.JobLifecycle.OnRetry = handler }
+}
+
 // OptJobOnBroken sets a lifecycle hook.
 func OptJobOnBroken(handler func(context.Context)) JobBuilderOption {
 	return func(jb *JobBuilder) { jb.JobLifecycle.OnBroken = handler }
@@ -20,6 +20,30 @@ type JobConfig struct {
 	ShouldSkipLoggerListeners *bool `json:"shouldSkipLoggerListeners" yaml:"shouldSkipLoggerListeners"`
 	// ShouldSkipLoggerOutput skips writing logger output if it is set to true.
 	ShouldSkipLoggerOutput *bool `json:"shouldSkipLoggerOutput" yaml:"shouldSkipLoggerOutput"`
+	// DistributedLock acquires a lease from the job manager's job locker before each invocation
+	// so that the job only runs in one of many job managers (e.g. in each replica of a service).
+	DistributedLock *bool `json:"distributedLock" yaml:"distributedLock"`
+	// DistributedLockTTL is how long a lease is held if it is not renewed; it is renewed while an invocation runs.
+	DistributedLockTTL time.Duration `json:"distributedLockTTL" yaml:"distributedLockTTL"`
+	// HistoryMaxCount is the number of invocations kept in the job manager's history store for the job.
+	HistoryMaxCount int `json:"historyMaxCount" yaml:"historyMaxCount"`
+	// HistoryMaxAge is how long invocations are kept in the job manager's history store for the job.
+	HistoryMaxAge time.Duration `json:"historyMaxAge" yaml:"historyMaxAge"`
+	// RetryPolicy retries the job within the same invocation if it returns an error.
+	RetryPolicy RetryPolicy `json:"retryPolicy" yaml:"retryPolicy"`
+	// ConcurrencyPolicy determines what happens if the job is triggered while it is running; it defaults to `forbid`.
+	ConcurrencyPolicy ConcurrencyPolicy `json:"concurrencyPolicy" yaml:"concurrencyPolicy"`
+	// MaxConcurrency is the maximum number of concurrent invocations with the `allow` concurrency policy; if unset it is unlimited.
+	MaxConcurrency int `json:"maxConcurrency" yaml:"maxConcurrency"`
+	// CatchUpPolicy determines which of the runs the job missed while no job manager was running are run when it starts,
+	// using the last runs recorded in the job manager's last run store; it defaults to `none`.
+	CatchUpPolicy CatchUpPolicy `json:"catchUpPolicy" yaml:"catchUpPolicy"`
+	// CatchUpMaxRuns is the maximum number of missed runs that are run with the `each` catch-up policy.
+	CatchUpMaxRuns int `json:"catchUpMaxRuns" yaml:"catchUpMaxRuns"`
+	// ShouldSkipOutputCapture skips capturing the output of each invocation if it is set to true.
+	ShouldSkipOutputCapture *bool `json:"shouldSkipOutputCapture" yaml:"shouldSkipOutputCapture"`
+	// OutputMaxBytes is the maximum number of bytes of output kept for each invocation; older output is dropped past it.
+	OutputMaxBytes int `json:"outputMaxBytes" yaml:"outputMaxBytes"`
 }
 
 // DisabledOrDefault returns a value or a default.
@@ -61,3 +85,83 @@ func (jc JobConfig) ShouldSkipLoggerOutputOrDefault() bool {
 	}
 	return DefaultShouldSkipLoggerOutput
 }
+
+// ShouldSkipOutputCaptureOrDefault returns a value or a default.
+func (jc JobConfig) ShouldSkipOutputCaptureOrDefault() bool {
+	if jc.ShouldSkipOutputCapture != nil {
+		return *jc.ShouldSkipOutputCapture
+	}
+	return DefaultShouldSkipOutputCapture
+}
+
+// DistributedLockOrDefault returns a value or a default.
+func (jc JobConfig) DistributedLockOrDefault() bool {
+	if jc.DistributedLock != nil {
+		return *jc.DistributedLock
+	}
+	return DefaultDistributedLock
+}
+
+// DistributedLockTTLOrDefault returns a value or a default.
+func (jc JobConfig) DistributedLockTTLOrDefault() time.Duration {
+	if jc.DistributedLockTTL > 0 {
+		return jc.DistributedLockTTL
+	}
+	return DefaultDistributedLockTTL
+}
+
+// HistoryMaxCountOrDefault returns a value or a default.
+func (jc JobConfig) HistoryMaxCountOrDefault() int {
+	if jc.HistoryMaxCount > 0 {
+		return jc.HistoryMaxCount
+	}
+	return DefaultHistoryMaxCount
+}
+
+// HistoryMaxAgeOrDefault returns a value or a default.
+func (jc JobConfig) HistoryMaxAgeOrDefault() time.Duration {
+	if jc.HistoryMaxAge > 0 {
+		return jc.HistoryMaxAge
+	}
+	return DefaultHistoryMaxAge
+}
+
+// HistoryRetention returns the retention policy for the job's history.
+func (jc JobConfig) HistoryRetention() JobHistoryRetention {
+	return JobHistoryRetention{
+		MaxCount: jc.HistoryMaxCountOrDefault(),
+		MaxAge:   jc.HistoryMaxAgeOrDefault(),
+	}
+}
+
+// ConcurrencyPolicyOrDefault returns a value or a default.
+func (jc JobConfig) ConcurrencyPolicyOrDefault() ConcurrencyPolicy {
+	if jc.ConcurrencyPolicy != "" {
+		return jc.ConcurrencyPolicy
+	}
+	return DefaultConcurrencyPolicy
+}
+
+// CatchUpPolicyOrDefault returns a value or a default.
+func (jc JobConfig) CatchUpPolicyOrDefault() CatchUpPolicy {
+	if jc.CatchUpPolicy != "" {
+		return jc.CatchUpPolicy
+	}
+	return DefaultCatchUpPolicy
+}
+
+// CatchUpMaxRunsOrDefault returns a value or a default.
+func (jc JobConfig) CatchUpMaxRunsOrDefault() int {
+	if jc.CatchUpMaxRuns > 0 {
+		return jc.CatchUpMaxRuns
+	}
+	return DefaultCatchUpMaxRuns
+}
+
+// OutputMaxBytesOrDefault returns a value or a default.
+func (jc JobConfig) OutputMaxBytesOrDefault() int {
+	if jc.OutputMaxBytes > 0 {
+		return jc.OutputMaxBytes
+	}
+	return DefaultOutputMaxBytes
+}
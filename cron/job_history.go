@@ -0,0 +1,174 @@
+package cron
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+)
+
+/*
+JobHistoryStore records job invocations so that they outlive the job scheduler (and process) that ran them.
+
+It is given each invocation by the job scheduler when it begins (with status `running`) and again when it
+completes, so saving an invocation with an id that was already saved should replace it.
+*/
+type JobHistoryStore interface {
+	// Save adds or replaces an invocation by its id.
+	Save(ctx context.Context, ji *JobInvocation) error
+	// Query returns the invocations that match a query, most recently started first.
+	Query(ctx context.Context, query JobHistoryQuery) ([]*JobInvocation, error)
+	// Cull removes the invocations for a job that are outside a retention policy.
+	Cull(ctx context.Context, jobName string, retention JobHistoryRetention) error
+}
+
+// JobHistoryQuery filters the invocations returned from a history store.
+// Fields that are unset (or zero) are not filtered on.
+type JobHistoryQuery struct {
+	// JobName returns invocations for a single job.
+	JobName string
+	// Statuses returns invocations with any of the given statuses.
+	Statuses []JobInvocationStatus
+	// After returns invocations started at or after a time.
+	After time.Time
+	// Before returns invocations started before a time.
+	Before time.Time
+	// Limit is the maximum number of invocations to return.
+	Limit int
+}
+
+// Matches returns if an invocation matches the query (ignoring the limit).
+func (jhq JobHistoryQuery) Matches(ji *JobInvocation) bool {
+	if jhq.JobName != "" && ji.JobName != jhq.JobName {
+		return false
+	}
+	if len(jhq.Statuses) > 0 {
+		var found bool
+		for _, status := range jhq.Statuses {
+			if ji.Status == status {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	if !jhq.After.IsZero() && ji.Started.Before(jhq.After) {
+		return false
+	}
+	if !jhq.Before.IsZero() && !ji.Started.Before(jhq.Before) {
+		return false
+	}
+	return true
+}
+
+// JobHistoryRetention is a retention policy for the invocations of a job.
+// Fields that are unset (or zero) do not remove invocations.
+type JobHistoryRetention struct {
+	// MaxCount is the number of most recently started invocations to keep.
+	MaxCount int
+	// MaxAge removes invocations that started longer ago than the age.
+	MaxAge time.Duration
+}
+
+// Cutoff returns the start time invocations must be at or after to be kept by the max age.
+func (jhr JobHistoryRetention) Cutoff(now time.Time) time.Time {
+	if jhr.MaxAge > 0 {
+		return now.Add(-jhr.MaxAge)
+	}
+	return Zero
+}
+
+var (
+	_ JobHistoryStore = (*InMemoryJobHistoryStore)(nil)
+)
+
+// NewInMemoryJobHistoryStore returns a new in memory job history store.
+func NewInMemoryJobHistoryStore() *InMemoryJobHistoryStore {
+	return &InMemoryJobHistoryStore{
+		invocations: make(map[string][]*JobInvocation),
+	}
+}
+
+// InMemoryJobHistoryStore is a job history store that holds invocations in memory.
+// It does not outlive the process, but is useful for tests and for querying recent invocations.
+type InMemoryJobHistoryStore struct {
+	mu          sync.Mutex
+	invocations map[string][]*JobInvocation
+}
+
+// Save implements JobHistoryStore.
+func (imh *InMemoryJobHistoryStore) Save(_ context.Context, ji *JobInvocation) error {
+	imh.mu.Lock()
+	defer imh.mu.Unlock()
+
+	saved := ji.Clone()
+	saved.Cancel = nil
+	saved.State = nil
+
+	invocations := imh.invocations[ji.JobName]
+	for index := range invocations {
+		if invocations[index].ID == ji.ID {
+			invocations[index] = saved
+			return nil
+		}
+	}
+	invocations = append(invocations, saved)
+	sort.SliceStable(invocations, func(i, j int) bool {
+		return invocations[i].Started.After(invocations[j].Started)
+	})
+	imh.invocations[ji.JobName] = invocations
+	return nil
+}
+
+// Query implements JobHistoryStore.
+func (imh *InMemoryJobHistoryStore) Query(_ context.Context, query JobHistoryQuery) ([]*JobInvocation, error) {
+	imh.mu.Lock()
+	defer imh.mu.Unlock()
+
+	var output []*JobInvocation
+	for jobName, invocations := range imh.invocations {
+		if query.JobName != "" && jobName != query.JobName {
+			continue
+		}
+		for _, ji := range invocations {
+			if query.Matches(ji) {
+				output = append(output, ji.Clone())
+			}
+		}
+	}
+	sort.SliceStable(output, func(i, j int) bool {
+		return output[i].Started.After(output[j].Started)
+	})
+	if query.Limit > 0 && len(output) > query.Limit {
+		output = output[:query.Limit]
+	}
+	return output, nil
+}
+
+// Cull implements JobHistoryStore.
+func (imh *InMemoryJobHistoryStore) Cull(_ context.Context, jobName string, retention JobHistoryRetention) error {
+	imh.mu.Lock()
+	defer imh.mu.Unlock()
+
+	invocations := imh.invocations[jobName]
+	if retention.MaxCount > 0 && len(invocations) > retention.MaxCount {
+		invocations = invocations[:retention.MaxCount]
+	}
+	if cutoff := retention.Cutoff(Now()); !cutoff.IsZero() {
+		kept := invocations[:0]
+		for _, ji := range invocations {
+			if !ji.Started.Before(cutoff) {
+				kept = append(kept, ji)
+			}
+		}
+		invocations = kept
+	}
+	if len(invocations) == 0 {
+		delete(imh.invocations, jobName)
+		return nil
+	}
+	imh.invocations[jobName] = invocations
+	return nil
+}
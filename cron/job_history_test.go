@@ -0,0 +1,166 @@
+package cron
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/blend/go-sdk/assert"
+)
+
+func TestInMemoryJobHistoryStore(t *testing.T) {
+	assert := assert.New(t)
+
+	ctx := context.Background()
+	store := NewInMemoryJobHistoryStore()
+
+	started := time.Date(2020, 01, 02, 03, 04, 05, 0, time.UTC)
+	statuses := []JobInvocationStatus{JobInvocationStatusSuccess, JobInvocationStatusErrored}
+	for index := 0; index < 10; index++ {
+		for _, jobName := range []string{"test", "other"} {
+			assert.Nil(store.Save(ctx, &JobInvocation{
+				ID:         fmt.Sprintf("%s-%d", jobName, index),
+				JobName:    jobName,
+				Started:    started.Add(time.Duration(index) * time.Minute),
+				Status:     statuses[index%2],
+				Parameters: JobParameters{"index": fmt.Sprint(index)},
+			}))
+		}
+	}
+
+	history, err := store.Query(ctx, JobHistoryQuery{JobName: "test"})
+	assert.Nil(err)
+	assert.Len(history, 10)
+	assert.Equal("test-9", history[0].ID)
+	assert.Equal("9", history[0].Parameters["index"])
+
+	history, err = store.Query(ctx, JobHistoryQuery{
+		JobName:  "test",
+		Statuses: []JobInvocationStatus{JobInvocationStatusErrored},
+		After:    started.Add(2 * time.Minute),
+		Before:   started.Add(7 * time.Minute),
+	})
+	assert.Nil(err)
+	assert.Len(history, 2)
+	assert.Equal("test-5", history[0].ID)
+	assert.Equal("test-3", history[1].ID)
+
+	history, err = store.Query(ctx, JobHistoryQuery{Limit: 3})
+	assert.Nil(err)
+	assert.Len(history, 3)
+	assert.Equal(started.Add(9*time.Minute), history[0].Started)
+	assert.Equal(started.Add(8*time.Minute), history[2].Started)
+
+	// saving an invocation again replaces it.
+	assert.Nil(store.Save(ctx, &JobInvocation{ID: "test-9", JobName: "test", Started: started.Add(9 * time.Minute), Status: JobInvocationStatusCancelled}))
+	history, err = store.Query(ctx, JobHistoryQuery{JobName: "test", Limit: 1})
+	assert.Nil(err)
+	assert.Len(history, 1)
+	assert.Equal(JobInvocationStatusCancelled, history[0].Status)
+
+	assert.Nil(store.Cull(ctx, "test", JobHistoryRetention{MaxCount: 4}))
+	history, err = store.Query(ctx, JobHistoryQuery{JobName: "test"})
+	assert.Nil(err)
+	assert.Len(history, 4)
+	assert.Equal("test-6", history[3].ID)
+
+	history, err = store.Query(ctx, JobHistoryQuery{JobName: "other"})
+	assert.Nil(err)
+	assert.Len(history, 10)
+
+	// the max age removes invocations started before 03:09:35.
+	assert.Nil(store.Cull(ctx, "other", JobHistoryRetention{MaxAge: time.Since(started.Add(5*time.Minute + 30*time.Second))}))
+	history, err = store.Query(ctx, JobHistoryQuery{JobName: "other"})
+	assert.Nil(err)
+	assert.Len(history, 4)
+	assert.Equal("other-6", history[3].ID)
+}
+
+func TestJobSchedulerHistory(t *testing.T) {
+	assert := assert.New(t)
+
+	store := NewInMemoryJobHistoryStore()
+	running := make(chan struct{})
+	finish := make(chan struct{})
+	js := NewJobScheduler(NewJob(
+		OptJobName("history"),
+		OptJobConfig(JobConfig{HistoryMaxCount: 2}),
+		OptJobAction(func(ctx context.Context) error {
+			running <- struct{}{}
+			<-finish
+			if GetJobParameterValues(ctx)["fail"] == "true" {
+				return fmt.Errorf("failed")
+			}
+			return nil
+		}),
+	), OptJobSchedulerHistoryStore(store))
+
+	ji, done, err := js.RunAsyncContext(WithJobParameterValues(context.Background(), JobParameters{"fail": "true"}))
+	assert.Nil(err)
+	<-running
+
+	history, err := js.History(context.Background(), JobHistoryQuery{})
+	assert.Nil(err)
+	assert.Len(history, 1)
+	assert.Equal(ji.ID, history[0].ID)
+	assert.Equal(JobInvocationStatusRunning, history[0].Status)
+
+	finish <- struct{}{}
+	<-done
+
+	history, err = js.History(context.Background(), JobHistoryQuery{})
+	assert.Nil(err)
+	assert.Len(history, 1)
+	assert.Equal(JobInvocationStatusErrored, history[0].Status)
+	assert.Equal("failed", history[0].Err.Error())
+	assert.Equal("true", history[0].Parameters["fail"])
+	assert.False(history[0].Complete.IsZero())
+
+	for index := 0; index < 2; index++ {
+		_, done, err = js.RunAsync()
+		assert.Nil(err)
+		<-running
+		finish <- struct{}{}
+		<-done
+	}
+
+	// the retention policy keeps the two most recent invocations.
+	history, err = js.History(context.Background(), JobHistoryQuery{})
+	assert.Nil(err)
+	assert.Len(history, 2)
+	for _, ji := range history {
+		assert.Equal(JobInvocationStatusSuccess, ji.Status)
+	}
+
+	// a new scheduler restores the last invocation from the history.
+	restored := NewJobScheduler(NewJob(OptJobName("history"), OptJobAction(noop)), OptJobSchedulerHistoryStore(store))
+	assert.Nil(restored.OnLoad(context.Background()))
+	assert.NotNil(restored.Last())
+	assert.Equal(history[0].ID, restored.Last().ID)
+}
+
+func TestJobManagerHistory(t *testing.T) {
+	assert := assert.New(t)
+
+	store := NewInMemoryJobHistoryStore()
+	jm := New(OptHistoryStore(store))
+	assert.Nil(jm.LoadJobs(
+		NewJob(OptJobName("first"), OptJobAction(noop)),
+		NewJob(OptJobName("second"), OptJobAction(noop)),
+	))
+
+	for _, jobName := range []string{"first", "second"} {
+		_, done, err := jm.RunJob(jobName)
+		assert.Nil(err)
+		<-done
+	}
+
+	history, err := jm.History(context.Background(), JobHistoryQuery{Statuses: []JobInvocationStatus{JobInvocationStatusSuccess}})
+	assert.Nil(err)
+	assert.Len(history, 2)
+
+	history, err = New().History(context.Background(), JobHistoryQuery{})
+	assert.Nil(err)
+	assert.Empty(history)
+}
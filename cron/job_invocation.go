@@ -2,8 +2,10 @@ package cron
 
 import (
 	"context"
+	"sync"
 	"time"
 
+	"github.com/blend/go-sdk/ex"
 	"github.com/blend/go-sdk/uuid"
 )
 
@@ -33,6 +35,26 @@ func GetJobInvocation(ctx context.Context) *JobInvocation {
 	return nil
 }
 
+type contextKeyJobInvocationLock struct{}
+
+// withJobInvocationLock adds the lock that guards changes to the job invocation to a context.
+func withJobInvocationLock(ctx context.Context, lock sync.Locker) context.Context {
+	return context.WithValue(ctx, contextKeyJobInvocationLock{}, lock)
+}
+
+// updateJobInvocation changes the job invocation in a context while holding its lock, if it has one.
+func updateJobInvocation(ctx context.Context, update func(*JobInvocation)) {
+	ji := GetJobInvocation(ctx)
+	if ji == nil {
+		return
+	}
+	if lock, ok := ctx.Value(contextKeyJobInvocationLock{}).(sync.Locker); ok {
+		lock.Lock()
+		defer lock.Unlock()
+	}
+	update(ji)
+}
+
 // NewJobInvocationID returns a new pseudo-unique job invocation identifier.
 func NewJobInvocationID() string {
 	return uuid.V4().String()
@@ -49,9 +71,15 @@ type JobInvocation struct {
 
 	Parameters JobParameters       `json:"parameters"`
 	Status     JobInvocationStatus `json:"status"`
+	Output     string              `json:"output,omitempty"`
 	State      interface{}         `json:"-"`
 
+	Attempts []JobInvocationAttempt `json:"attempts,omitempty"`
+	Children []*JobInvocation       `json:"children,omitempty"`
+
 	Cancel context.CancelFunc `json:"-"`
+
+	output *jobInvocationOutput
 }
 
 // Elapsed returns the elapsed time for the invocation.
@@ -62,9 +90,26 @@ func (ji *JobInvocation) Elapsed() time.Duration {
 	return 0
 }
 
+// cancelChildren marks the child invocations that are still running as cancelled.
+func (ji *JobInvocation) cancelChildren(now time.Time) {
+	for index, child := range ji.Children {
+		if child.Status != JobInvocationStatusRunning {
+			continue
+		}
+		cancelled := child.Clone()
+		cancelled.Status = JobInvocationStatusCancelled
+		cancelled.Err = ex.New(ErrJobCancelled)
+		cancelled.Complete = now
+		cancelled.cancelChildren(now)
+		ji.Children[index] = cancelled
+	}
+}
+
 // Clone clones the job invocation.
+//
+// While the invocation is running, the clone's output is the output captured so far.
 func (ji *JobInvocation) Clone() *JobInvocation {
-	return &JobInvocation{
+	clone := &JobInvocation{
 		ID:      ji.ID,
 		JobName: ji.JobName,
 
@@ -74,8 +119,34 @@ func (ji *JobInvocation) Clone() *JobInvocation {
 
 		Parameters: ji.Parameters,
 		Status:     ji.Status,
+		Output:     ji.Output,
 		State:      ji.State,
 
+		Attempts: append([]JobInvocationAttempt(nil), ji.Attempts...),
+		Children: append([]*JobInvocation(nil), ji.Children...),
+
 		Cancel: ji.Cancel,
+
+		output: ji.output,
+	}
+	if ji.output != nil {
+		clone.Output = ji.output.String()
 	}
+	return clone
+}
+
+// JobInvocationAttempt is metadata for an attempt to run the job within an invocation,
+// of which there is more than one if the job is retried.
+type JobInvocationAttempt struct {
+	Started  time.Time `json:"started"`
+	Complete time.Time `json:"complete"`
+	Err      error     `json:"err"`
+}
+
+// Elapsed returns the elapsed time for the attempt.
+func (jia JobInvocationAttempt) Elapsed() time.Duration {
+	if !jia.Complete.IsZero() {
+		return jia.Complete.Sub(jia.Started)
+	}
+	return 0
 }
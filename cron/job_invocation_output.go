@@ -0,0 +1,73 @@
+package cron
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"io/ioutil"
+	"sync"
+
+	"github.com/blend/go-sdk/logger"
+)
+
+// jobInvocationOutputFormatter formats the logger events captured in job invocation outputs.
+var jobInvocationOutputFormatter = logger.NewTextOutputFormatter(logger.OptTextNoColor())
+
+type contextKeyJobInvocationOutput struct{}
+
+// withJobInvocationOutput adds the output of a job invocation to a context.
+func withJobInvocationOutput(ctx context.Context, output *jobInvocationOutput) context.Context {
+	return context.WithValue(ctx, contextKeyJobInvocationOutput{}, output)
+}
+
+// GetJobInvocationOutput returns a writer for the output of the job invocation in a given context,
+// e.g. for the stdout and stderr of a command the job runs, or a discarding writer if it has none.
+//
+// The output is kept on the invocation (as `JobInvocation.Output`) with the logger events triggered
+// with the invocation context, and saved with the rest of the invocation to the job manager's history store.
+func GetJobInvocationOutput(ctx context.Context) io.Writer {
+	if value := ctx.Value(contextKeyJobInvocationOutput{}); value != nil {
+		if typed, ok := value.(*jobInvocationOutput); ok {
+			return typed
+		}
+	}
+	return ioutil.Discard
+}
+
+// newJobInvocationOutput returns a new job invocation output that keeps up to a given number of bytes.
+func newJobInvocationOutput(maxBytes int) *jobInvocationOutput {
+	return &jobInvocationOutput{
+		maxBytes: maxBytes,
+	}
+}
+
+// jobInvocationOutput is a bounded buffer for the output of a job invocation.
+// Once it is full, the oldest output is dropped to make room for new output.
+type jobInvocationOutput struct {
+	mu       sync.Mutex
+	maxBytes int
+	contents []byte
+}
+
+// Write implements io.Writer.
+func (jio *jobInvocationOutput) Write(p []byte) (int, error) {
+	jio.mu.Lock()
+	defer jio.mu.Unlock()
+
+	jio.contents = append(jio.contents, p...)
+	if over := len(jio.contents) - jio.maxBytes; over > 0 {
+		// drop the rest of a partially dropped line, so the output starts at the beginning of a line.
+		if index := bytes.IndexByte(jio.contents[over:], '\n'); index >= 0 && over+index+1 < len(jio.contents) {
+			over += index + 1
+		}
+		jio.contents = append(jio.contents[:0], jio.contents[over:]...)
+	}
+	return len(p), nil
+}
+
+// String returns the output.
+func (jio *jobInvocationOutput) String() string {
+	jio.mu.Lock()
+	defer jio.mu.Unlock()
+	return string(jio.contents)
+}
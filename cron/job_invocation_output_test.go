@@ -0,0 +1,101 @@
+package cron
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"strings"
+	"testing"
+
+	"github.com/blend/go-sdk/assert"
+	"github.com/blend/go-sdk/logger"
+	"github.com/blend/go-sdk/ref"
+)
+
+func TestJobInvocationOutput(t *testing.T) {
+	assert := assert.New(t)
+
+	output := newJobInvocationOutput(16)
+	fmt.Fprintln(output, "one")
+	fmt.Fprintln(output, "two")
+	assert.Equal("one\ntwo\n", output.String())
+
+	// the oldest lines are dropped once the output is full.
+	fmt.Fprintln(output, "three")
+	fmt.Fprintln(output, "four")
+	assert.Equal("two\nthree\nfour\n", output.String())
+
+	// a single line longer than the limit keeps its end.
+	fmt.Fprint(output, "0123456789abcdefghij")
+	assert.Equal("456789abcdefghij", output.String())
+
+	assert.Equal(ioutil.Discard, GetJobInvocationOutput(context.Background()))
+	assert.Equal(output, GetJobInvocationOutput(withJobInvocationOutput(context.Background(), output)))
+}
+
+func TestJobSchedulerOutput(t *testing.T) {
+	assert := assert.New(t)
+
+	log := logger.All(logger.OptOutput(ioutil.Discard))
+	defer log.Close()
+
+	store := NewInMemoryJobHistoryStore()
+	running := make(chan struct{})
+	finish := make(chan struct{})
+	js := NewJobScheduler(NewJob(
+		OptJobName("output"),
+		OptJobAction(func(ctx context.Context) error {
+			log.WithContext(ctx).Infof("logged with the invocation context")
+			log.Infof("logged without the invocation context")
+			fmt.Fprintln(GetJobInvocationOutput(ctx), "written to the invocation output")
+			running <- struct{}{}
+			<-finish
+			return nil
+		}),
+	), OptJobSchedulerLog(log), OptJobSchedulerHistoryStore(store))
+
+	_, done, err := js.RunAsync()
+	assert.Nil(err)
+	<-running
+
+	// the output captured so far is available while the invocation runs.
+	current := js.Running()
+	assert.Len(current, 1)
+	assert.True(strings.Contains(current[0].Output, "logged with the invocation context"), current[0].Output)
+	assert.True(strings.Contains(current[0].Output, "written to the invocation output"), current[0].Output)
+	assert.False(strings.Contains(current[0].Output, "logged without the invocation context"), current[0].Output)
+
+	finish <- struct{}{}
+	<-done
+
+	last := js.Last()
+	assert.NotNil(last)
+	assert.True(strings.Contains(last.Output, "written to the invocation output"), last.Output)
+	assert.True(strings.Contains(last.Output, FlagComplete), last.Output)
+
+	history, err := js.History(context.Background(), JobHistoryQuery{})
+	assert.Nil(err)
+	assert.Len(history, 1)
+	assert.Equal(last.Output, history[0].Output)
+}
+
+func TestJobSchedulerOutputSkipped(t *testing.T) {
+	assert := assert.New(t)
+
+	log := logger.All(logger.OptOutput(ioutil.Discard))
+	defer log.Close()
+
+	js := NewJobScheduler(NewJob(
+		OptJobName("output"),
+		OptJobConfig(JobConfig{ShouldSkipOutputCapture: ref.Bool(true)}),
+		OptJobAction(func(ctx context.Context) error {
+			log.WithContext(ctx).Infof("logged with the invocation context")
+			fmt.Fprintln(GetJobInvocationOutput(ctx), "written to the invocation output")
+			return nil
+		}),
+	), OptJobSchedulerLog(log))
+
+	js.Run()
+	assert.NotNil(js.Last())
+	assert.Empty(js.Last().Output)
+}
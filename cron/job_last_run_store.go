@@ -0,0 +1,57 @@
+package cron
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+/*
+JobLastRunStore records the last time each job was scheduled to run, so that the runs a job missed while
+no job manager was running (e.g. during a deploy or an outage) can be caught up when it starts.
+
+It is only used for jobs with a `JobConfig.CatchUpPolicy`; the job scheduler records the time of each of their
+scheduled runs (whether or not the run starts), and finds the runs they missed from the last recorded time when it starts.
+*/
+type JobLastRunStore interface {
+	// LastRun returns the last time a job was scheduled to run, or a zero time if none was recorded.
+	LastRun(ctx context.Context, jobName string) (time.Time, error)
+	// SetLastRun records the last time a job was scheduled to run.
+	// It should not replace a later time, e.g. one recorded by another job manager.
+	SetLastRun(ctx context.Context, jobName string, runtime time.Time) error
+}
+
+var (
+	_ JobLastRunStore = (*InMemoryJobLastRunStore)(nil)
+)
+
+// NewInMemoryJobLastRunStore returns a new in memory last run store.
+func NewInMemoryJobLastRunStore() *InMemoryJobLastRunStore {
+	return &InMemoryJobLastRunStore{
+		lastRuns: make(map[string]time.Time),
+	}
+}
+
+// InMemoryJobLastRunStore is a last run store that keeps the last runs in memory.
+// As it doesn't outlive the process, it is mostly useful for tests.
+type InMemoryJobLastRunStore struct {
+	mu       sync.Mutex
+	lastRuns map[string]time.Time
+}
+
+// LastRun implements JobLastRunStore.
+func (ilr *InMemoryJobLastRunStore) LastRun(_ context.Context, jobName string) (time.Time, error) {
+	ilr.mu.Lock()
+	defer ilr.mu.Unlock()
+	return ilr.lastRuns[jobName], nil
+}
+
+// SetLastRun implements JobLastRunStore.
+func (ilr *InMemoryJobLastRunStore) SetLastRun(_ context.Context, jobName string, runtime time.Time) error {
+	ilr.mu.Lock()
+	defer ilr.mu.Unlock()
+	if runtime.After(ilr.lastRuns[jobName]) {
+		ilr.lastRuns[jobName] = runtime.UTC()
+	}
+	return nil
+}
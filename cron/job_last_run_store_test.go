@@ -0,0 +1,100 @@
+package cron
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/blend/go-sdk/assert"
+)
+
+func TestInMemoryJobLastRunStore(t *testing.T) {
+	assert := assert.New(t)
+
+	ctx := context.Background()
+	store := NewInMemoryJobLastRunStore()
+	lastRun, err := store.LastRun(ctx, "test")
+	assert.Nil(err)
+	assert.True(lastRun.IsZero())
+
+	runtime := time.Date(2020, 01, 02, 03, 04, 05, 0, time.UTC)
+	assert.Nil(store.SetLastRun(ctx, "test", runtime))
+	assert.Nil(store.SetLastRun(ctx, "test", runtime.Add(-time.Hour)))
+	lastRun, err = store.LastRun(ctx, "test")
+	assert.Nil(err)
+	assert.Equal(runtime, lastRun)
+}
+
+func TestMissedRuns(t *testing.T) {
+	assert := assert.New(t)
+
+	lastRun := time.Date(2020, 01, 02, 03, 04, 05, 0, time.UTC)
+	now := lastRun.Add(3*time.Hour + 30*time.Minute)
+	assert.Equal([]time.Time{
+		lastRun.Add(time.Hour),
+		lastRun.Add(2 * time.Hour),
+		lastRun.Add(3 * time.Hour),
+	}, missedRuns(Every(time.Hour), lastRun, now, 10))
+	assert.Equal([]time.Time{lastRun.Add(3 * time.Hour)}, missedRuns(Every(time.Hour), lastRun, now, 1))
+	assert.Empty(missedRuns(Every(time.Hour), lastRun, lastRun.Add(30*time.Minute), 10))
+	assert.Empty(missedRuns(nil, lastRun, now, 10))
+
+	assert.Nil(catchUpSchedule(Immediately()))
+	assert.Equal(Every(time.Hour), catchUpSchedule(Jitter(Immediately().Then(Every(time.Hour)), time.Minute)))
+}
+
+func TestJobSchedulerCatchUp(t *testing.T) {
+	assert := assert.New(t)
+
+	testCatchUp := func(name string, policy CatchUpPolicy, maxRuns int, lastRun time.Time) (*InMemoryJobLastRunStore, []time.Time) {
+		store := NewInMemoryJobLastRunStore()
+		if !lastRun.IsZero() {
+			assert.Nil(store.SetLastRun(context.Background(), name, lastRun))
+		}
+		runtimes := make(chan time.Time, 10)
+		js := NewJobScheduler(NewJob(
+			OptJobName(name),
+			OptJobSchedule(Every(time.Hour)),
+			OptJobCatchUpPolicy(policy),
+			OptJobCatchUpMaxRuns(maxRuns),
+			OptJobAction(func(ctx context.Context) error {
+				runtimes <- getJobRuntime(ctx)
+				return nil
+			}),
+		), OptJobSchedulerLastRunStore(store))
+		go js.Start()
+		<-js.NotifyStarted()
+		// the next run is an hour away, so the scheduler is idle once it has caught up.
+		for js.GetNextRuntime().IsZero() {
+			time.Sleep(time.Millisecond)
+		}
+		assert.Nil(js.Stop())
+		close(runtimes)
+
+		var output []time.Time
+		for runtime := range runtimes {
+			output = append(output, runtime)
+		}
+		return store, output
+	}
+
+	lastRun := Now().Add(-(3*time.Hour + 30*time.Minute))
+	store, runtimes := testCatchUp("each", CatchUpPolicyEach, 2, lastRun)
+	assert.Equal([]time.Time{lastRun.Add(2 * time.Hour), lastRun.Add(3 * time.Hour)}, runtimes)
+	recorded, _ := store.LastRun(context.Background(), "each")
+	assert.Equal(lastRun.Add(3*time.Hour), recorded)
+
+	_, runtimes = testCatchUp("once", CatchUpPolicyOnce, 0, lastRun)
+	assert.Equal([]time.Time{lastRun.Add(3 * time.Hour)}, runtimes)
+
+	store, runtimes = testCatchUp("none", CatchUpPolicyNone, 0, lastRun)
+	assert.Empty(runtimes)
+	recorded, _ = store.LastRun(context.Background(), "none")
+	assert.Equal(lastRun, recorded)
+
+	// the first time a job is scheduled nothing is caught up, but the time is recorded.
+	store, runtimes = testCatchUp("first", CatchUpPolicyEach, 0, Zero)
+	assert.Empty(runtimes)
+	recorded, _ = store.LastRun(context.Background(), "first")
+	assert.False(recorded.IsZero())
+}
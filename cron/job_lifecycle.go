@@ -25,8 +25,12 @@ type JobLifecycle struct {
 	// OnSuccess is called if the job completes without an error.
 	OnSuccess func(context.Context)
 
+	// OnRetry is called if the job returns an error and will be retried
+	// according to the retry policy in the .Config().
+	OnRetry func(context.Context)
+
 	// OnBroken is called if the job errors after having completed successfully
-	// the previous invocation.
+	// the previous invocation; if the job is retried, only once retries are exhausted.
 	OnBroken func(context.Context)
 	// OnFixed is called if the job completes successfully after having
 	// returned an error on the previous invocation.
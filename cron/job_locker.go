@@ -0,0 +1,125 @@
+package cron
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/blend/go-sdk/ex"
+)
+
+/*
+JobLocker acquires leases on jobs so that a job invocation only runs in one of many job managers
+that have the same jobs loaded (e.g. one in each replica of a service).
+
+It is consulted by the job scheduler before each invocation of a job with `JobConfig.DistributedLock` set,
+and the lease is renewed while the invocation runs and released when it completes.
+
+Leases are acquired for the time an invocation was scheduled for, so for schedules that fire at fixed times
+(e.g. `DailyAt` or cron strings) each scheduled time only runs once across job managers. Interval schedules
+(e.g. `Every`) are relative to when each job manager started, so for them the lease only prevents concurrent invocations.
+*/
+type JobLocker interface {
+	// Lock acquires a lease on a job for an invocation scheduled at a given time that expires after a ttl.
+	// It should return an error with class `ErrJobLocked` if an unexpired lease for the job is held elsewhere,
+	// or if a lease was already acquired for the same scheduled time.
+	Lock(ctx context.Context, jobName string, runtime time.Time, ttl time.Duration) (JobLease, error)
+}
+
+// JobLease is a lease on a job acquired from a job locker.
+type JobLease interface {
+	// Renew extends the lease so that it expires after the ttl.
+	// It should return an error if the lease has expired or is now held elsewhere.
+	Renew(ctx context.Context, ttl time.Duration) error
+	// Release releases the lease.
+	Release(ctx context.Context) error
+}
+
+type contextKeyJobRuntime struct{}
+
+// withJobRuntime adds the time an invocation was scheduled for to a context.
+func withJobRuntime(ctx context.Context, runtime time.Time) context.Context {
+	return context.WithValue(ctx, contextKeyJobRuntime{}, runtime)
+}
+
+// getJobRuntime gets the time an invocation was scheduled for from a context.
+func getJobRuntime(ctx context.Context) time.Time {
+	if value, ok := ctx.Value(contextKeyJobRuntime{}).(time.Time); ok {
+		return value
+	}
+	return Zero
+}
+
+var (
+	_ JobLocker = (*InMemoryJobLocker)(nil)
+)
+
+// NewInMemoryJobLocker returns a new in memory job locker.
+func NewInMemoryJobLocker() *InMemoryJobLocker {
+	return &InMemoryJobLocker{
+		leases: make(map[string]*inMemoryJobLease),
+	}
+}
+
+// InMemoryJobLocker is a job locker that holds leases in memory.
+// It can be shared by job managers in the same process, and is useful for tests.
+type InMemoryJobLocker struct {
+	mu     sync.Mutex
+	leases map[string]*inMemoryJobLease
+}
+
+// Lock implements JobLocker.
+func (iml *InMemoryJobLocker) Lock(_ context.Context, jobName string, runtime time.Time, ttl time.Duration) (JobLease, error) {
+	iml.mu.Lock()
+	defer iml.mu.Unlock()
+
+	now := Now()
+	if existing, ok := iml.leases[jobName]; ok {
+		if existing.Expires.After(now) {
+			return nil, ex.New(ErrJobLocked, ex.OptMessagef("job: %s", jobName))
+		}
+		if !runtime.After(existing.Runtime) {
+			return nil, ex.New(ErrJobLocked, ex.OptMessagef("job: %s; already ran for %s", jobName, FormatTime(runtime)))
+		}
+	}
+	lease := &inMemoryJobLease{
+		locker:  iml,
+		JobName: jobName,
+		Runtime: runtime,
+		Expires: now.Add(ttl),
+	}
+	iml.leases[jobName] = lease
+	return lease, nil
+}
+
+type inMemoryJobLease struct {
+	locker  *InMemoryJobLocker
+	JobName string
+	Runtime time.Time
+	Expires time.Time
+}
+
+// Renew implements JobLease.
+func (iml *inMemoryJobLease) Renew(_ context.Context, ttl time.Duration) error {
+	iml.locker.mu.Lock()
+	defer iml.locker.mu.Unlock()
+
+	now := Now()
+	if iml.locker.leases[iml.JobName] != iml || !iml.Expires.After(now) {
+		return ex.New(ErrJobLeaseLost, ex.OptMessagef("job: %s", iml.JobName))
+	}
+	iml.Expires = now.Add(ttl)
+	return nil
+}
+
+// Release implements JobLease.
+// The lease is kept (expired) so that the runtime it was acquired for is not run again.
+func (iml *inMemoryJobLease) Release(_ context.Context) error {
+	iml.locker.mu.Lock()
+	defer iml.locker.mu.Unlock()
+
+	if iml.locker.leases[iml.JobName] == iml {
+		iml.Expires = Zero
+	}
+	return nil
+}
@@ -0,0 +1,148 @@
+package cron
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/blend/go-sdk/assert"
+	"github.com/blend/go-sdk/ex"
+	"github.com/blend/go-sdk/ref"
+)
+
+func TestInMemoryJobLocker(t *testing.T) {
+	assert := assert.New(t)
+
+	ctx := context.Background()
+	runtime := time.Date(2020, 01, 02, 03, 04, 05, 0, time.UTC)
+	locker := NewInMemoryJobLocker()
+
+	lease, err := locker.Lock(ctx, "test", runtime, time.Minute)
+	assert.Nil(err)
+	assert.NotNil(lease)
+
+	_, err = locker.Lock(ctx, "test", runtime.Add(time.Second), time.Minute)
+	assert.True(IsJobLocked(err))
+
+	other, err := locker.Lock(ctx, "other", runtime, time.Minute)
+	assert.Nil(err)
+	assert.Nil(other.Release(ctx))
+
+	assert.Nil(lease.Renew(ctx, time.Minute))
+	assert.Nil(lease.Release(ctx))
+	assert.True(ex.Is(lease.Renew(ctx, time.Minute), ErrJobLeaseLost))
+
+	// the same scheduled time doesn't run again once released.
+	_, err = locker.Lock(ctx, "test", runtime, time.Minute)
+	assert.True(IsJobLocked(err))
+
+	lease, err = locker.Lock(ctx, "test", runtime.Add(time.Second), time.Millisecond)
+	assert.Nil(err)
+	time.Sleep(2 * time.Millisecond)
+
+	// an expired lease can be taken over, and can no longer be renewed.
+	_, err = locker.Lock(ctx, "test", runtime.Add(2*time.Second), time.Minute)
+	assert.Nil(err)
+	assert.True(ex.Is(lease.Renew(ctx, time.Minute), ErrJobLeaseLost))
+}
+
+func TestJobSchedulerDistributedLock(t *testing.T) {
+	assert := assert.New(t)
+
+	locker := NewInMemoryJobLocker()
+	started := make(chan struct{})
+	finish := make(chan struct{})
+	newScheduler := func() *JobScheduler {
+		return NewJobScheduler(NewJob(
+			OptJobName("locked"),
+			OptJobDistributedLock(true),
+			OptJobAction(func(_ context.Context) error {
+				started <- struct{}{}
+				<-finish
+				return nil
+			}),
+		), OptJobSchedulerLocker(locker))
+	}
+	first, second := newScheduler(), newScheduler()
+
+	runtime := Now()
+	ctx := withJobRuntime(context.Background(), runtime)
+	_, done, err := first.RunAsyncContext(ctx)
+	assert.Nil(err)
+	<-started
+
+	_, _, err = second.RunAsyncContext(withJobRuntime(context.Background(), runtime.Add(time.Second)))
+	assert.True(IsJobLocked(err))
+	assert.True(second.IsIdle())
+
+	close(finish)
+	<-done
+
+	// the same scheduled time doesn't run again in another scheduler.
+	_, _, err = second.RunAsyncContext(ctx)
+	assert.True(IsJobLocked(err))
+
+	_, done, err = second.RunAsyncContext(withJobRuntime(context.Background(), runtime.Add(time.Second)))
+	assert.Nil(err)
+	<-started
+	<-done
+	assert.Equal(JobInvocationStatusSuccess, second.Last().Status)
+}
+
+func TestJobSchedulerDistributedLockNotConfigured(t *testing.T) {
+	assert := assert.New(t)
+
+	locker := NewInMemoryJobLocker()
+	_, err := locker.Lock(context.Background(), "unlocked", Now(), time.Minute)
+	assert.Nil(err)
+
+	js := NewJobScheduler(NewJob(OptJobName("unlocked"), OptJobAction(noop)), OptJobSchedulerLocker(locker))
+	_, done, err := js.RunAsync()
+	assert.Nil(err)
+	<-done
+	assert.Equal(JobInvocationStatusSuccess, js.Last().Status)
+}
+
+type lostJobLocker struct {
+	released chan struct{}
+}
+
+func (ljl lostJobLocker) Lock(_ context.Context, _ string, _ time.Time, _ time.Duration) (JobLease, error) {
+	return ljl, nil
+}
+
+func (ljl lostJobLocker) Renew(_ context.Context, _ time.Duration) error {
+	return ex.New(ErrJobLeaseLost)
+}
+
+func (ljl lostJobLocker) Release(_ context.Context) error {
+	close(ljl.released)
+	return nil
+}
+
+func TestJobSchedulerDistributedLockLost(t *testing.T) {
+	assert := assert.New(t)
+
+	locker := lostJobLocker{released: make(chan struct{})}
+	js := NewJobScheduler(NewJob(
+		OptJobName("lost"),
+		OptJobConfig(JobConfig{
+			DistributedLock:    ref.Bool(true),
+			DistributedLockTTL: 30 * time.Millisecond,
+		}),
+		OptJobAction(func(ctx context.Context) error {
+			<-ctx.Done()
+			return nil
+		}),
+	), OptJobSchedulerLocker(locker))
+
+	_, done, err := js.RunAsync()
+	assert.Nil(err)
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		assert.FailNow("the invocation should have been cancelled when the lease was lost")
+	}
+	<-locker.released
+	assert.Equal(JobInvocationStatusCancelled, js.Last().Status)
+}
@@ -27,12 +27,16 @@ func New(options ...JobManagerOption) *JobManager {
 // JobManager is the main orchestration and job management object.
 type JobManager struct {
 	sync.Mutex
-	Latch   *async.Latch
-	Tracer  Tracer
-	Log     logger.Log
-	Started time.Time
-	Stopped time.Time
-	Jobs    map[string]*JobScheduler
+	Latch              *async.Latch
+	Tracer             Tracer
+	Log                logger.Log
+	Locker             JobLocker
+	HistoryStore       JobHistoryStore
+	LastRunStore       JobLastRunStore
+	ConcurrencyLimiter *ConcurrencyLimiter
+	Started            time.Time
+	Stopped            time.Time
+	Jobs               map[string]*JobScheduler
 }
 
 //
@@ -120,6 +124,10 @@ func (jm *JobManager) LoadJobs(jobs ...Job) error {
 			job,
 			OptJobSchedulerLog(jm.Log),
 			OptJobSchedulerTracer(jm.Tracer),
+			OptJobSchedulerLocker(jm.Locker),
+			OptJobSchedulerHistoryStore(jm.HistoryStore),
+			OptJobSchedulerLastRunStore(jm.LastRunStore),
+			OptJobSchedulerConcurrencyLimiter(jm.ConcurrencyLimiter),
 		)
 		if err := jobScheduler.OnLoad(context.Background()); err != nil {
 			return err
@@ -256,6 +264,15 @@ func (jm *JobManager) CancelJob(jobName string) (err error) {
 	return
 }
 
+// History returns the invocations of all jobs from the history store that match a query.
+// If there is no history store it returns nothing.
+func (jm *JobManager) History(ctx context.Context, query JobHistoryQuery) ([]*JobInvocation, error) {
+	if jm.HistoryStore == nil {
+		return nil, nil
+	}
+	return jm.HistoryStore.Query(ctx, query)
+}
+
 //
 // status and state
 //
@@ -14,3 +14,23 @@ func OptLog(log logger.Log) JobManagerOption {
 func OptTracer(tracer Tracer) JobManagerOption {
 	return func(jm *JobManager) { jm.Tracer = tracer }
 }
+
+// OptLocker sets the job manager job locker, used by jobs with `JobConfig.DistributedLock` set.
+func OptLocker(locker JobLocker) JobManagerOption {
+	return func(jm *JobManager) { jm.Locker = locker }
+}
+
+// OptHistoryStore sets the job manager history store, which records the invocations of every job.
+func OptHistoryStore(store JobHistoryStore) JobManagerOption {
+	return func(jm *JobManager) { jm.HistoryStore = store }
+}
+
+// OptLastRunStore sets the job manager last run store, used by jobs with `JobConfig.CatchUpPolicy` set.
+func OptLastRunStore(store JobLastRunStore) JobManagerOption {
+	return func(jm *JobManager) { jm.LastRunStore = store }
+}
+
+// OptMaxConcurrency sets the maximum number of invocations of the job manager's jobs that can run concurrently.
+func OptMaxConcurrency(max int) JobManagerOption {
+	return func(jm *JobManager) { jm.ConcurrencyLimiter = NewConcurrencyLimiter(max) }
+}
@@ -37,15 +37,28 @@ type JobScheduler struct {
 	JobSchedule  Schedule
 	JobLifecycle JobLifecycle
 
-	Tracer Tracer
-	Log    logger.Log
+	Tracer             Tracer
+	Log                logger.Log
+	JobLocker          JobLocker
+	HistoryStore       JobHistoryStore
+	LastRunStore       JobLastRunStore
+	ConcurrencyLimiter *ConcurrencyLimiter
 
 	NextRuntime time.Time
 
-	currentLock sync.Mutex
-	current     *JobInvocation
-	lastLock    sync.Mutex
-	last        *JobInvocation
+	nextRuntimeLock sync.Mutex
+	replaceLock     sync.Mutex
+	currentLock     sync.Mutex
+	current         []runningInvocation
+	reserved        int
+	lastLock        sync.Mutex
+	last            *JobInvocation
+}
+
+// runningInvocation is a running invocation and the channel closed when it is done.
+type runningInvocation struct {
+	ji   *JobInvocation
+	done chan struct{}
 }
 
 // Name returns the job name.
@@ -100,6 +113,21 @@ func (js *JobScheduler) Labels() map[string]string {
 	return output
 }
 
+// GetNextRuntime returns the next time the job is scheduled to run, or a zero time if it isn't.
+// Unlike reading `NextRuntime`, it is safe to call while the scheduler is running.
+func (js *JobScheduler) GetNextRuntime() time.Time {
+	js.nextRuntimeLock.Lock()
+	defer js.nextRuntimeLock.Unlock()
+	return js.NextRuntime
+}
+
+// setNextRuntime sets the next runtime; the run loop reads it without the lock as it is the only writer.
+func (js *JobScheduler) setNextRuntime(nextRuntime time.Time) {
+	js.nextRuntimeLock.Lock()
+	defer js.nextRuntimeLock.Unlock()
+	js.NextRuntime = nextRuntime
+}
+
 // State returns the job scheduler state.
 func (js *JobScheduler) State() JobSchedulerState {
 	if js.Latch.IsStarted() {
@@ -131,7 +159,7 @@ func (js *JobScheduler) Stop() error {
 	ctx := js.withLogContext(context.Background())
 	js.Latch.Stopping()
 
-	if current := js.Current(); current != nil {
+	if !js.IsIdle() {
 		gracePeriod := js.Config().ShutdownGracePeriodOrDefault()
 		if gracePeriod > 0 {
 			var cancel func()
@@ -140,19 +168,21 @@ func (js *JobScheduler) Stop() error {
 			js.waitCurrentComplete(ctx)
 		}
 	}
-	if current := js.Current(); current != nil && current.Status == JobInvocationStatusRunning {
-		current.Cancel()
-	}
+	js.cancelRunning()
 
 	<-js.Latch.NotifyStopped()
 	js.Latch.Reset()
-	js.NextRuntime = Zero
+	js.setNextRuntime(Zero)
 	return nil
 }
 
 // OnLoad triggers the on load even on the job lifecycle handler.
+// If the scheduler has a history store, the last invocation is restored from it.
 func (js *JobScheduler) OnLoad(ctx context.Context) error {
 	ctx = js.withLogContext(ctx)
+	if js.HistoryStore != nil && js.Last() == nil {
+		js.restoreLast(ctx)
+	}
 	if js.Lifecycle().OnLoad != nil {
 		if err := js.Lifecycle().OnLoad(ctx); err != nil {
 			return err
@@ -206,7 +236,7 @@ func (js *JobScheduler) Disable() {
 
 // Cancel stops all running invocations.
 func (js *JobScheduler) Cancel() error {
-	if js.Current() == nil {
+	if js.IsIdle() {
 		logger.MaybeDebugfContext(js.withLogContext(context.Background()), js.Log, "cannot cancel; job is not runnning")
 		return nil
 	}
@@ -216,9 +246,7 @@ func (js *JobScheduler) Cancel() error {
 		defer cancel()
 		js.waitCurrentComplete(ctx)
 	}
-	if current := js.Current(); current != nil && current.Status == JobInvocationStatusRunning {
-		current.Cancel()
-	} else {
+	if len(js.cancelRunning()) == 0 {
 		logger.MaybeDebugfContext(js.withLogContext(context.Background()), js.Log, "cannot cancel; job is not runnning")
 	}
 	return nil
@@ -241,8 +269,10 @@ func (js *JobScheduler) RunLoop() {
 
 	js.debugf(ctx, "RunLoop: entered running state")
 
+	js.catchUp(ctx)
+
 	if js.JobSchedule != nil {
-		js.NextRuntime = js.JobSchedule.Next(js.NextRuntime)
+		js.setNextRuntime(js.JobSchedule.Next(js.NextRuntime))
 		js.debugf(ctx, "RunLoop: setting next runtime `%s`", js.NextRuntime.Format(time.RFC3339Nano))
 	}
 
@@ -264,20 +294,14 @@ func (js *JobScheduler) RunLoop() {
 		runAt := time.After(js.NextRuntime.UTC().Sub(Now()))
 		select {
 		case <-runAt:
-			if js.CanBeScheduled() {
-				if _, _, err := js.RunAsync(); err != nil {
-					js.error(ctx, err)
-				}
-			} else {
-				js.debugf(ctx, "RunLoop: job cannot be scheduled; already running")
-			}
+			js.runScheduled(ctx, js.scheduledRuntime(js.NextRuntime))
 
 			// set up the next runtime.
 			if js.JobSchedule != nil {
-				js.NextRuntime = js.JobSchedule.Next(js.NextRuntime)
+				js.setNextRuntime(js.JobSchedule.Next(js.NextRuntime))
 				js.debugf(ctx, "RunLoop: setting next runtime `%s`", js.NextRuntime.Format(time.RFC3339Nano))
 			} else {
-				js.NextRuntime = Zero
+				js.setNextRuntime(Zero)
 				js.debugf(ctx, "RunLoop: setting next runtime to zero")
 			}
 
@@ -298,49 +322,83 @@ func (js *JobScheduler) RunAsync() (*JobInvocation, <-chan struct{}, error) {
 }
 
 // RunAsyncContext starts a job invocation with a given context.
+//
+// If the job is already running, what happens depends on the job's concurrency policy; it either returns
+// an error with class `ErrJobAlreadyRunning`, starts another invocation, or cancels the running invocations
+// and waits for them to complete before starting. If the scheduler's concurrency limiter has reached its
+// limit it returns an error with class `ErrJobConcurrencyLimit`. If the context is done while waiting for
+// replaced invocations to complete it returns an error with class `ErrJobCancelled`.
 func (js *JobScheduler) RunAsyncContext(ctx context.Context) (*JobInvocation, <-chan struct{}, error) {
-	if !js.IsIdle() {
+	unlockReplace := func() {}
+	if js.Config().ConcurrencyPolicyOrDefault() == ConcurrencyPolicyReplace {
+		// replacing is serialized until the new invocation has started (or failed to start),
+		// so concurrent triggers each replace the one before them and only one invocation runs.
+		js.replaceLock.Lock()
+		unlockReplace = js.replaceLock.Unlock
+		if err := js.replaceRunning(ctx); err != nil {
+			unlockReplace()
+			return nil, nil, err
+		}
+	} else if !js.tryReserve() {
 		return nil, nil, ex.New(ErrJobAlreadyRunning, ex.OptMessagef("job: %s", js.Name()))
 	}
+	if js.ConcurrencyLimiter != nil && !js.ConcurrencyLimiter.TryAcquire() {
+		js.unreserve()
+		unlockReplace()
+		return nil, nil, ex.New(ErrJobConcurrencyLimit, ex.OptMessagef("job: %s", js.Name()))
+	}
+	lease, err := js.lock(ctx)
+	if err != nil {
+		js.releaseConcurrencyLimiter()
+		js.unreserve()
+		unlockReplace()
+		return nil, nil, err
+	}
 
 	ctx, ji := js.createInvocation(ctx)
 	done := make(chan struct{})
-	js.SetCurrent(ji)
+	js.addCurrent(ji, done)
+	unlockReplace()
 
-	var err error
 	var tracer TraceFinisher
 	go func() {
 		defer func() {
 			if err != nil && IsJobCancelled(err) {
-				js.onJobCancelled(ctx) // the job was cancelled, either manually or by a timeout
+				js.onJobCancelled(ctx, ji) // the job was cancelled, either manually or by a timeout
 			} else if err != nil {
-				js.onJobError(ctx, err) // the job completed with an error
+				js.onJobError(ctx, ji, err) // the job completed with an error
 			} else {
-				js.onJobSuccess(ctx) // the job completed without error
+				js.onJobSuccess(ctx, ji) // the job completed without error
 			}
-			js.onJobComplete(ctx) // always signal that the job finished
+			js.onJobComplete(ctx, ji)       // always signal that the job finished
+			js.keepOutput(ji)               // keep the output captured during the invocation
+			js.saveHistory(js.snapshot(ji)) // record the completed invocation
 
 			if tracer != nil {
 				tracer.Finish(ctx, err) // call the trace finisher if one was started
 			}
 			ji.Cancel() // if the job was created with a timeout, end the timeout
+			if lease != nil {
+				js.releaseLease(lease) // release the distributed lock lease if one was acquired
+			}
 
-			close(done)              // signal callers the job is done
-			js.assignCurrentToLast() // rotate in the current to the last result
+			js.releaseConcurrencyLimiter() // release the invocation's place in the concurrency limit
+			js.assignCurrentToLast(ji)     // rotate in the current to the last result
+			close(done)                    // signal callers the job is done
 		}()
 
+		if lease != nil {
+			renewDone := make(chan struct{})
+			defer close(renewDone)
+			go js.renewLease(ctx, ji, lease, renewDone) // renew the lease while the job runs
+		}
 		if js.Tracer != nil {
 			ctx, tracer = js.Tracer.Start(ctx, js.Name())
 		}
-		js.onJobBegin(ctx) // signal the job is starting
+		js.onJobBegin(ctx, ji)          // signal the job is starting
+		js.saveHistory(js.snapshot(ji)) // record the running invocation
 
-		select {
-		case <-ctx.Done(): // if the timeout or cancel is triggered
-			err = ErrJobCancelled // set the error to a known error
-			return
-		case err = <-js.safeBackgroundExec(ctx): // run the job in a background routine and catch pancis
-			return
-		}
+		err = js.execute(ctx, ji) // run the job, retrying failed attempts per the retry policy
 	}()
 	return ji, done, nil
 }
@@ -369,14 +427,16 @@ func (js *JobScheduler) RunContext(ctx context.Context) {
 //
 
 // CanBeScheduled returns if a job will be triggered automatically
-// and isn't already in flight and set to be serial.
+// and another invocation can start according to its concurrency policy.
 func (js *JobScheduler) CanBeScheduled() bool {
-	return !js.Disabled() && js.IsIdle()
+	return !js.Disabled() && (js.Config().ConcurrencyPolicyOrDefault() == ConcurrencyPolicyReplace || js.canRunConcurrently())
 }
 
 // IsIdle returns if the job is not currently running.
 func (js *JobScheduler) IsIdle() (isIdle bool) {
-	isIdle = js.Current() == nil
+	js.currentLock.Lock()
+	isIdle = len(js.current) == 0
+	js.currentLock.Unlock()
 	return
 }
 
@@ -384,20 +444,35 @@ func (js *JobScheduler) IsIdle() (isIdle bool) {
 // utility functions
 //
 
-// Current returns the current job invocation.
+// Current returns the current job invocation, or the most recently started
+// invocation if the job's concurrency policy allows more than one.
 func (js *JobScheduler) Current() (current *JobInvocation) {
 	js.currentLock.Lock()
-	if js.current != nil {
-		current = js.current.Clone()
+	if len(js.current) > 0 {
+		current = js.current[len(js.current)-1].ji.Clone()
+	}
+	js.currentLock.Unlock()
+	return
+}
+
+// Running returns the running job invocations, in the order they started.
+func (js *JobScheduler) Running() (running []*JobInvocation) {
+	js.currentLock.Lock()
+	for _, ri := range js.current {
+		running = append(running, ri.ji.Clone())
 	}
 	js.currentLock.Unlock()
 	return
 }
 
 // SetCurrent sets the current invocation, it is useful for tests etc.
+// It replaces any running invocations, and a nil invocation clears them.
 func (js *JobScheduler) SetCurrent(ji *JobInvocation) {
 	js.currentLock.Lock()
-	js.current = ji
+	js.current = nil
+	if ji != nil {
+		js.current = []runningInvocation{{ji: ji}}
+	}
 	js.currentLock.Unlock()
 }
 
@@ -411,6 +486,16 @@ func (js *JobScheduler) Last() (last *JobInvocation) {
 	return
 }
 
+// History returns the invocations of the job from the history store that match a query.
+// The job name of the query is set to the job's name, and if there is no history store it returns nothing.
+func (js *JobScheduler) History(ctx context.Context, query JobHistoryQuery) ([]*JobInvocation, error) {
+	if js.HistoryStore == nil {
+		return nil, nil
+	}
+	query.JobName = js.Name()
+	return js.HistoryStore.Query(ctx, query)
+}
+
 // SetLast sets the last invocation, it is useful for tests etc.
 func (js *JobScheduler) SetLast(ji *JobInvocation) {
 	js.lastLock.Lock()
@@ -418,29 +503,374 @@ func (js *JobScheduler) SetLast(ji *JobInvocation) {
 	js.lastLock.Unlock()
 }
 
-func (js *JobScheduler) assignCurrentToLast() {
+// addCurrent adds a running invocation in the slot reserved for it with `tryReserve` or `replaceRunning`.
+func (js *JobScheduler) addCurrent(ji *JobInvocation, done chan struct{}) {
+	js.currentLock.Lock()
+	js.reserved--
+	js.current = append(js.current, runningInvocation{ji: ji, done: done})
+	js.currentLock.Unlock()
+}
+
+// tryReserve reserves a slot for an invocation if another invocation can start alongside the running
+// and reserved invocations according to the job's concurrency policy.
+// The check and the reservation happen under the same lock, so concurrent triggers can't both pass the check.
+func (js *JobScheduler) tryReserve() bool {
+	config := js.Config()
+	js.currentLock.Lock()
+	defer js.currentLock.Unlock()
+	if !js.canRunConcurrentlyUnsafe(config) {
+		return false
+	}
+	js.reserved++
+	return true
+}
+
+// unreserve releases a slot reserved for an invocation that did not start.
+func (js *JobScheduler) unreserve() {
+	js.currentLock.Lock()
+	js.reserved--
+	js.currentLock.Unlock()
+}
+
+func (js *JobScheduler) assignCurrentToLast(ji *JobInvocation) {
 	js.lastLock.Lock()
 	js.currentLock.Lock()
-	js.last = js.current
-	js.current = nil
+	js.last = ji
+	for index, ri := range js.current {
+		if ri.ji == ji {
+			js.current = append(js.current[:index:index], js.current[index+1:]...)
+			break
+		}
+	}
 	js.currentLock.Unlock()
 	js.lastLock.Unlock()
 }
 
+// keepOutput sets the output of an invocation to the output captured while it ran.
+func (js *JobScheduler) keepOutput(ji *JobInvocation) {
+	if ji.output == nil {
+		return
+	}
+	js.currentLock.Lock()
+	ji.Output = ji.output.String()
+	js.currentLock.Unlock()
+}
+
+// snapshot returns a copy of a running invocation.
+func (js *JobScheduler) snapshot(ji *JobInvocation) *JobInvocation {
+	js.currentLock.Lock()
+	defer js.currentLock.Unlock()
+	return ji.Clone()
+}
+
+// canRunConcurrently returns if another invocation can start alongside the running invocations
+// according to the job's concurrency policy (without replacing them).
+func (js *JobScheduler) canRunConcurrently() bool {
+	config := js.Config()
+	js.currentLock.Lock()
+	defer js.currentLock.Unlock()
+	return js.canRunConcurrentlyUnsafe(config)
+}
+
+func (js *JobScheduler) canRunConcurrentlyUnsafe(config JobConfig) bool {
+	count := len(js.current) + js.reserved
+	if config.ConcurrencyPolicyOrDefault() != ConcurrencyPolicyAllow {
+		return count == 0
+	}
+	if config.MaxConcurrency <= 0 {
+		return true
+	}
+	return count < config.MaxConcurrency
+}
+
+// cancelRunning cancels the current invocations that haven't completed, returning their ids.
+func (js *JobScheduler) cancelRunning() (cancelled []string) {
+	js.currentLock.Lock()
+	defer js.currentLock.Unlock()
+	for _, ri := range js.current {
+		if ri.ji.Cancel != nil && (ri.ji.Status == JobInvocationStatusIdle || ri.ji.Status == JobInvocationStatusRunning) {
+			ri.ji.Cancel()
+			cancelled = append(cancelled, ri.ji.ID)
+		}
+	}
+	return
+}
+
+// replaceReservedWait is how long replacing waits for a reserved slot to start running before checking again.
+const replaceReservedWait = 10 * time.Millisecond
+
+// replaceRunning cancels the current invocations, waits for them to complete, and reserves a slot
+// for a new invocation once no invocations are running or reserved.
+// It must be called while holding the replace lock, and returns an error if the context is done first.
+func (js *JobScheduler) replaceRunning(ctx context.Context) error {
+	for {
+		js.currentLock.Lock()
+		if len(js.current) == 0 && js.reserved == 0 {
+			js.reserved++
+			js.currentLock.Unlock()
+			return nil
+		}
+		running := append([]runningInvocation(nil), js.current...)
+		js.currentLock.Unlock()
+
+		for _, id := range js.cancelRunning() {
+			js.onJobReplaced(js.withLogContext(ctx), id)
+		}
+		for _, ri := range running {
+			if ri.done == nil {
+				continue
+			}
+			select {
+			case <-ri.done:
+			case <-ctx.Done():
+				return js.replaceCancelled(ctx)
+			}
+		}
+		if len(running) > 0 {
+			continue
+		}
+
+		// a slot can be reserved but not yet running if the concurrency policy was changed while
+		// another trigger was starting an invocation, so wait a moment before checking again.
+		wait := time.NewTimer(replaceReservedWait)
+		select {
+		case <-wait.C:
+		case <-ctx.Done():
+			wait.Stop()
+			return js.replaceCancelled(ctx)
+		}
+	}
+}
+
+func (js *JobScheduler) replaceCancelled(ctx context.Context) error {
+	return ex.New(ErrJobCancelled, ex.OptMessagef("job: %s; cancelled replacing the running invocations", js.Name()), ex.OptInner(ctx.Err()))
+}
+
+func (js *JobScheduler) releaseConcurrencyLimiter() {
+	if js.ConcurrencyLimiter != nil {
+		js.ConcurrencyLimiter.Release()
+	}
+}
+
 func (js *JobScheduler) createInvocation(ctx context.Context) (context.Context, *JobInvocation) {
 	ji := NewJobInvocation(js.Name())
 	ji.Parameters = MergeJobParameterValues(js.Config().ParameterValues, GetJobParameterValues(ctx))
+	if !js.Config().ShouldSkipOutputCaptureOrDefault() {
+		ji.output = newJobInvocationOutput(js.Config().OutputMaxBytesOrDefault())
+	}
 	ctx = js.withInvocationLogContext(ctx, ji)
 	ctx, ji.Cancel = js.withTimeoutOrCancel(ctx, js.Config().TimeoutOrDefault())
 	ctx = WithJobInvocation(ctx, ji)
+	ctx = withJobInvocationLock(ctx, &js.currentLock)
 	ctx = WithJobParameterValues(ctx, ji.Parameters)
 	return ctx, ji
 }
 
+// lock acquires a lease from the job locker if the job is configured to use one.
+func (js *JobScheduler) lock(ctx context.Context) (JobLease, error) {
+	if js.JobLocker == nil || !js.Config().DistributedLockOrDefault() {
+		return nil, nil
+	}
+	runtime := getJobRuntime(ctx)
+	if runtime.IsZero() {
+		runtime = Now()
+	}
+	lease, err := js.JobLocker.Lock(ctx, js.Name(), runtime, js.Config().DistributedLockTTLOrDefault())
+	if err != nil {
+		return nil, ex.New(err)
+	}
+	return lease, nil
+}
+
+// renewLease renews a lease until done is closed, cancelling the invocation if the lease cannot be renewed.
+func (js *JobScheduler) renewLease(ctx context.Context, ji *JobInvocation, lease JobLease, done <-chan struct{}) {
+	ttl := js.Config().DistributedLockTTLOrDefault()
+	ticker := time.NewTicker(ttl / 3)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-done:
+			return
+		case <-ticker.C:
+			if err := lease.Renew(ctx, ttl); err != nil {
+				js.error(ctx, err)
+				ji.Cancel()
+				return
+			}
+		}
+	}
+}
+
+func (js *JobScheduler) releaseLease(lease JobLease) {
+	ctx := js.withLogContext(context.Background())
+	if err := lease.Release(ctx); err != nil {
+		js.error(ctx, err)
+	}
+}
+
+// saveHistory saves an invocation to the history store, culling the job's history when it has completed.
+func (js *JobScheduler) saveHistory(ji *JobInvocation) {
+	if js.HistoryStore == nil {
+		return
+	}
+	ctx := js.withLogContext(context.Background())
+	if err := js.HistoryStore.Save(ctx, ji); err != nil {
+		js.error(ctx, err)
+		return
+	}
+	if ji.Status == JobInvocationStatusRunning {
+		return
+	}
+	if err := js.HistoryStore.Cull(ctx, js.Name(), js.Config().HistoryRetention()); err != nil {
+		js.error(ctx, err)
+	}
+}
+
+// runScheduled starts an invocation scheduled for a given time, returning a channel that is closed when
+// it completes, or nil if it didn't start.
+func (js *JobScheduler) runScheduled(ctx context.Context, runtime time.Time) <-chan struct{} {
+	js.setLastRun(ctx, runtime)
+	if js.Disabled() {
+		js.debugf(ctx, "RunLoop: job cannot be scheduled; disabled")
+		return nil
+	}
+	_, done, err := js.RunAsyncContext(withJobRuntime(context.Background(), runtime))
+	if IsJobLocked(err) {
+		js.debugf(ctx, "RunLoop: job cannot be scheduled; locked elsewhere")
+	} else if IsJobAlreadyRunning(err) || IsJobConcurrencyLimit(err) {
+		js.onJobSkipped(ctx, err)
+	} else if err != nil {
+		js.error(ctx, err)
+	}
+	return done
+}
+
+// scheduledRuntime returns the time a run was scheduled for without any jitter.
+func (js *JobScheduler) scheduledRuntime(runtime time.Time) time.Time {
+	if typed, ok := js.JobSchedule.(*JitterSchedule); ok {
+		return typed.Scheduled(runtime)
+	}
+	return runtime
+}
+
+// catchUp runs the runs missed since the last run recorded in the last run store, according to the catch-up policy.
+// Each run waits for the previous one to complete.
+func (js *JobScheduler) catchUp(ctx context.Context) {
+	policy := js.Config().CatchUpPolicyOrDefault()
+	if js.LastRunStore == nil || js.JobSchedule == nil || policy == CatchUpPolicyNone {
+		return
+	}
+
+	lastRun, err := js.LastRunStore.LastRun(ctx, js.Name())
+	if err != nil {
+		js.error(ctx, err)
+		return
+	}
+	now := Now()
+	if lastRun.IsZero() {
+		// there is nothing to catch up the first time the job is scheduled, but a
+		// run missed before the next scheduled time should be caught up later.
+		js.setLastRun(ctx, now)
+		return
+	}
+
+	maxRuns := 1
+	if policy == CatchUpPolicyEach {
+		maxRuns = js.Config().CatchUpMaxRunsOrDefault()
+	}
+	missed := missedRuns(catchUpSchedule(js.JobSchedule), lastRun, now, maxRuns)
+	for _, runtime := range missed {
+		js.debugf(ctx, "RunLoop: catching up missed run `%s`", runtime.Format(time.RFC3339Nano))
+		done := js.runScheduled(ctx, runtime)
+		if done == nil {
+			continue
+		}
+		select {
+		case <-done:
+		case <-js.Latch.NotifyStopping():
+			return
+		}
+	}
+}
+
+// catchUpSchedule returns the schedule missed runs are found with; it removes jitter, and
+// skips immediate runs (which would be consumed by finding missed runs).
+func catchUpSchedule(schedule Schedule) Schedule {
+	switch typed := schedule.(type) {
+	case *JitterSchedule:
+		return catchUpSchedule(typed.Schedule)
+	case *ImmediateSchedule:
+		return catchUpSchedule(typed.then)
+	default:
+		return schedule
+	}
+}
+
+// missedRuns returns up to the most recent max times of a schedule after the last run and before now, oldest first.
+func missedRuns(schedule Schedule, lastRun, now time.Time, max int) (missed []time.Time) {
+	if schedule == nil {
+		return nil
+	}
+	for next := schedule.Next(lastRun); !next.IsZero() && next.Before(now); next = schedule.Next(next) {
+		if len(missed) > 0 && !next.After(missed[len(missed)-1]) {
+			break
+		}
+		missed = append(missed, next)
+		if len(missed) > max {
+			missed = missed[1:]
+		}
+	}
+	return
+}
+
+// setLastRun records the last time the job was scheduled to run in the last run store, if it has a catch-up policy.
+func (js *JobScheduler) setLastRun(ctx context.Context, runtime time.Time) {
+	if js.LastRunStore == nil || js.Config().CatchUpPolicyOrDefault() == CatchUpPolicyNone {
+		return
+	}
+	if err := js.LastRunStore.SetLastRun(ctx, js.Name(), runtime); err != nil {
+		js.error(ctx, err)
+	}
+}
+
+// restoreLast sets the last invocation to the most recently started completed invocation in the history store.
+func (js *JobScheduler) restoreLast(ctx context.Context) {
+	ctx, cancel := context.WithTimeout(ctx, DefaultHistoryRestoreTimeout)
+	defer cancel()
+
+	history, err := js.History(ctx, JobHistoryQuery{
+		Statuses: []JobInvocationStatus{
+			JobInvocationStatusSuccess,
+			JobInvocationStatusErrored,
+			JobInvocationStatusCancelled,
+		},
+		Limit: 1,
+	})
+	if err != nil {
+		js.error(ctx, err)
+		return
+	}
+	if len(history) > 0 {
+		js.SetLast(history[0])
+	}
+}
+
+// hasRunning returns if any of the current invocations have the running status.
+func (js *JobScheduler) hasRunning() bool {
+	js.currentLock.Lock()
+	defer js.currentLock.Unlock()
+	for _, ri := range js.current {
+		if ri.ji.Status == JobInvocationStatusRunning {
+			return true
+		}
+	}
+	return false
+}
+
 func (js *JobScheduler) waitCurrentComplete(ctx context.Context) {
 	deadlinePoll := time.Tick(100 * time.Millisecond)
 	for {
-		if js.Current().Status != JobInvocationStatusRunning {
+		if !js.hasRunning() {
 			return
 		}
 		select {
@@ -453,6 +883,36 @@ func (js *JobScheduler) waitCurrentComplete(ctx context.Context) {
 	}
 }
 
+// execute runs attempts of the job until one succeeds, is cancelled, or
+// the retry policy says it shouldn't be retried, returning the error of the last attempt.
+func (js *JobScheduler) execute(ctx context.Context, ji *JobInvocation) error {
+	policy := js.Config().RetryPolicy
+	for attempt := 1; ; attempt++ {
+		js.onAttemptBegin(ji)
+
+		var err error
+		select {
+		case <-ctx.Done(): // if the timeout or cancel is triggered
+			err = ErrJobCancelled // set the error to a known error
+		case err = <-js.safeBackgroundExec(ctx): // run the job in a background routine and catch pancis
+		}
+
+		js.onAttemptComplete(ji, err)
+		if ctx.Err() != nil || !policy.ShouldRetry(attempt, err) {
+			return err
+		}
+		js.onJobRetry(ctx, ji, attempt, err)
+
+		delay := time.NewTimer(policy.Delay(attempt))
+		select {
+		case <-ctx.Done():
+			delay.Stop()
+			return ErrJobCancelled
+		case <-delay.C:
+		}
+	}
+}
+
 func (js *JobScheduler) safeBackgroundExec(ctx context.Context) chan error {
 	errors := make(chan error, 2)
 	go func() {
@@ -475,7 +935,7 @@ func (js *JobScheduler) withTimeoutOrCancel(ctx context.Context, timeout time.Du
 
 // job lifecycle hooks
 
-func (js *JobScheduler) onJobBegin(ctx context.Context) {
+func (js *JobScheduler) onJobBegin(ctx context.Context, ji *JobInvocation) {
 	defer func() {
 		if r := recover(); r != nil {
 			js.error(ctx, ex.New(r, ex.OptMessagef("panic recovery in onJobBegin")))
@@ -483,9 +943,9 @@ func (js *JobScheduler) onJobBegin(ctx context.Context) {
 	}()
 
 	js.currentLock.Lock()
-	js.current.Started = time.Now().UTC()
-	js.current.Status = JobInvocationStatusRunning
-	id := js.current.ID
+	ji.Started = time.Now().UTC()
+	ji.Status = JobInvocationStatusRunning
+	id := ji.ID
 	js.currentLock.Unlock()
 
 	if lifecycle := js.Lifecycle(); lifecycle.OnBegin != nil {
@@ -496,7 +956,58 @@ func (js *JobScheduler) onJobBegin(ctx context.Context) {
 	}
 }
 
-func (js *JobScheduler) onJobComplete(ctx context.Context) {
+func (js *JobScheduler) onAttemptBegin(ji *JobInvocation) {
+	js.currentLock.Lock()
+	ji.Attempts = append(ji.Attempts, JobInvocationAttempt{Started: time.Now().UTC()})
+	js.currentLock.Unlock()
+}
+
+func (js *JobScheduler) onAttemptComplete(ji *JobInvocation, err error) {
+	js.currentLock.Lock()
+	attempt := &ji.Attempts[len(ji.Attempts)-1]
+	attempt.Complete = time.Now().UTC()
+	attempt.Err = err
+	js.currentLock.Unlock()
+}
+
+func (js *JobScheduler) onJobRetry(ctx context.Context, ji *JobInvocation, attempt int, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			js.error(ctx, ex.New(r, ex.OptMessagef("panic recovery in onJobRetry")))
+		}
+	}()
+
+	js.currentLock.Lock()
+	id := ji.ID
+	elapsed := ji.Attempts[attempt-1].Elapsed()
+	js.currentLock.Unlock()
+
+	if lifecycle := js.Lifecycle(); lifecycle.OnRetry != nil {
+		lifecycle.OnRetry(ctx)
+	}
+	if js.Log != nil && !js.Config().ShouldSkipLoggerListenersOrDefault() {
+		js.logTrigger(ctx, NewEvent(FlagRetry, js.Name(),
+			OptEventJobInvocation(id),
+			OptEventErr(err),
+			OptEventAttempt(attempt),
+			OptEventElapsed(elapsed),
+		))
+	}
+}
+
+func (js *JobScheduler) onJobSkipped(ctx context.Context, err error) {
+	if js.Log != nil && !js.Config().ShouldSkipLoggerListenersOrDefault() {
+		js.logTrigger(ctx, NewEvent(FlagSkipped, js.Name(), OptEventErr(err)))
+	}
+}
+
+func (js *JobScheduler) onJobReplaced(ctx context.Context, id string) {
+	if js.Log != nil && !js.Config().ShouldSkipLoggerListenersOrDefault() {
+		js.logTrigger(ctx, NewEvent(FlagReplaced, js.Name(), OptEventJobInvocation(id)))
+	}
+}
+
+func (js *JobScheduler) onJobComplete(ctx context.Context, ji *JobInvocation) {
 	defer func() {
 		if r := recover(); r != nil {
 			js.error(ctx, ex.New(r, ex.OptMessagef("panic recovery in onJobComplete")))
@@ -504,9 +1015,9 @@ func (js *JobScheduler) onJobComplete(ctx context.Context) {
 	}()
 
 	js.currentLock.Lock()
-	js.current.Complete = time.Now().UTC()
-	id := js.current.ID
-	elapsed := js.current.Elapsed()
+	ji.Complete = time.Now().UTC()
+	id := ji.ID
+	elapsed := ji.Elapsed()
 	js.currentLock.Unlock()
 
 	if lifecycle := js.Lifecycle(); lifecycle.OnComplete != nil {
@@ -517,7 +1028,7 @@ func (js *JobScheduler) onJobComplete(ctx context.Context) {
 	}
 }
 
-func (js *JobScheduler) onJobCancelled(ctx context.Context) {
+func (js *JobScheduler) onJobCancelled(ctx context.Context, ji *JobInvocation) {
 	defer func() {
 		if r := recover(); r != nil {
 			js.error(ctx, ex.New(r, ex.OptMessagef("panic recovery in onJobCanceled")))
@@ -525,9 +1036,10 @@ func (js *JobScheduler) onJobCancelled(ctx context.Context) {
 	}()
 
 	js.currentLock.Lock()
-	js.current.Status = JobInvocationStatusCancelled
-	id := js.current.ID
-	elapsed := js.current.Elapsed()
+	ji.Status = JobInvocationStatusCancelled
+	ji.cancelChildren(time.Now().UTC())
+	id := ji.ID
+	elapsed := ji.Elapsed()
 	js.currentLock.Unlock()
 
 	if lifecycle := js.Lifecycle(); lifecycle.OnCancellation != nil {
@@ -538,7 +1050,7 @@ func (js *JobScheduler) onJobCancelled(ctx context.Context) {
 	}
 }
 
-func (js *JobScheduler) onJobSuccess(ctx context.Context) {
+func (js *JobScheduler) onJobSuccess(ctx context.Context, ji *JobInvocation) {
 	defer func() {
 		if r := recover(); r != nil {
 			js.error(ctx, ex.New(r, ex.OptMessagef("panic recovery in onJobSuccess")))
@@ -546,9 +1058,9 @@ func (js *JobScheduler) onJobSuccess(ctx context.Context) {
 	}()
 
 	js.currentLock.Lock()
-	js.current.Status = JobInvocationStatusSuccess
-	id := js.current.ID
-	elapsed := js.current.Elapsed()
+	ji.Status = JobInvocationStatusSuccess
+	id := ji.ID
+	elapsed := ji.Elapsed()
 	js.currentLock.Unlock()
 
 	if lifecycle := js.Lifecycle(); lifecycle.OnSuccess != nil {
@@ -568,7 +1080,7 @@ func (js *JobScheduler) onJobSuccess(ctx context.Context) {
 	}
 }
 
-func (js *JobScheduler) onJobError(ctx context.Context, err error) {
+func (js *JobScheduler) onJobError(ctx context.Context, ji *JobInvocation, err error) {
 	defer func() {
 		if r := recover(); r != nil {
 			js.error(ctx, ex.New(r, ex.OptMessagef("panic recovery in onJobError")))
@@ -576,10 +1088,10 @@ func (js *JobScheduler) onJobError(ctx context.Context, err error) {
 	}()
 
 	js.currentLock.Lock()
-	js.current.Status = JobInvocationStatusErrored
-	js.current.Err = err
-	id := js.current.ID
-	elapsed := js.current.Elapsed()
+	ji.Status = JobInvocationStatusErrored
+	ji.Err = err
+	id := ji.ID
+	elapsed := ji.Elapsed()
 	js.currentLock.Unlock()
 
 	//
@@ -625,6 +1137,10 @@ func (js *JobScheduler) withInvocationLogContext(parent context.Context, ji *Job
 	if js.Config().ShouldSkipLoggerOutputOrDefault() {
 		parent = logger.WithSkipWrite(parent)
 	}
+	if ji.output != nil {
+		parent = withJobInvocationOutput(parent, ji.output)
+		parent = logger.WithCapture(parent, ji.output, jobInvocationOutputFormatter)
+	}
 	return parent
 }
 
@@ -14,3 +14,23 @@ func OptJobSchedulerTracer(tracer Tracer) JobSchedulerOption {
 func OptJobSchedulerLog(log logger.Log) JobSchedulerOption {
 	return func(js *JobScheduler) { js.Log = log }
 }
+
+// OptJobSchedulerLocker sets the job scheduler job locker.
+func OptJobSchedulerLocker(locker JobLocker) JobSchedulerOption {
+	return func(js *JobScheduler) { js.JobLocker = locker }
+}
+
+// OptJobSchedulerHistoryStore sets the job scheduler history store.
+func OptJobSchedulerHistoryStore(store JobHistoryStore) JobSchedulerOption {
+	return func(js *JobScheduler) { js.HistoryStore = store }
+}
+
+// OptJobSchedulerLastRunStore sets the job scheduler last run store.
+func OptJobSchedulerLastRunStore(store JobLastRunStore) JobSchedulerOption {
+	return func(js *JobScheduler) { js.LastRunStore = store }
+}
+
+// OptJobSchedulerConcurrencyLimiter sets the job scheduler concurrency limiter.
+func OptJobSchedulerConcurrencyLimiter(limiter *ConcurrencyLimiter) JobSchedulerOption {
+	return func(js *JobScheduler) { js.ConcurrencyLimiter = limiter }
+}
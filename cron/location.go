@@ -0,0 +1,61 @@
+package cron
+
+import "time"
+
+// nextInLocation returns the next runtime after a given time for a schedule that computes wall clock times
+// (e.g. "every day at 9am") in a given location.
+//
+// The wall clock time of `after` in the location is passed to `next` as a UTC time, so the schedule
+// can compute the next wall clock time without daylight saving time transitions, and the result is
+// converted back to the location.
+//
+// Wall clock times that are skipped by a transition (e.g. 2:30am when clocks move from 2am to 3am) run
+// later by the length of the transition (i.e. at 3:30am), and wall clock times that are repeated by a
+// transition (e.g. 1:30am when clocks move from 2am back to 1am) only run once, at the first occurrence.
+func nextInLocation(after time.Time, location *time.Location, next func(time.Time) time.Time) time.Time {
+	if after.IsZero() {
+		after = Now()
+	}
+	if location == nil {
+		location = time.UTC
+	}
+
+	wall := wallClock(after.In(location))
+	for {
+		nextWall := next(wall)
+		if nextWall.IsZero() {
+			return Zero
+		}
+		result := fromWallClock(nextWall, location)
+		// if the result is not after the given time, the wall clock time was repeated by a transition
+		// and it has already occurred; keep going until we find a wall clock time that hasn't.
+		if result.After(after) || !nextWall.After(wall) {
+			return result
+		}
+		wall = nextWall
+	}
+}
+
+// wallClock returns the wall clock time of a time as a UTC time.
+func wallClock(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), t.Hour(), t.Minute(), t.Second(), t.Nanosecond(), time.UTC)
+}
+
+// fromWallClock returns the time in a location for a wall clock time returned by `wallClock`.
+func fromWallClock(wall time.Time, location *time.Location) time.Time {
+	t := time.Date(wall.Year(), wall.Month(), wall.Day(), wall.Hour(), wall.Minute(), wall.Second(), wall.Nanosecond(), location)
+	// time.Date normalizes a wall clock time that is skipped by a transition to before the transition,
+	// so we move it to after the transition instead.
+	if shift := wallClock(t).Sub(wall); shift < 0 {
+		t = t.Add(-shift)
+	}
+	return t
+}
+
+// formatLocation returns a suffix for schedule string representations with a location.
+func formatLocation(location *time.Location) string {
+	if location == nil {
+		return ""
+	}
+	return " in " + location.String()
+}
@@ -0,0 +1,152 @@
+package cron
+
+import (
+	"testing"
+	"time"
+
+	"github.com/blend/go-sdk/assert"
+	"github.com/blend/go-sdk/ex"
+)
+
+func mustLoadLocation(t *testing.T, name string) *time.Location {
+	location, err := time.LoadLocation(name)
+	if err != nil {
+		t.Skipf("time zone data unavailable: %v", err)
+	}
+	return location
+}
+
+func TestParseStringLocation(t *testing.T) {
+	assert := assert.New(t)
+
+	ny := mustLoadLocation(t, "America/New_York")
+
+	schedule, err := ParseString("CRON_TZ=America/New_York 0 0 9 * * * *")
+	assert.Nil(err)
+	assert.Equal(ny, schedule.(*StringSchedule).Location)
+	assert.Equal("CRON_TZ=America/New_York 0 0 9 * * * *", schedule.(*StringSchedule).String())
+
+	// 9am in new york is 14:00 UTC before the spring transition, and 13:00 UTC after it.
+	next := schedule.Next(time.Date(2021, 03, 13, 15, 0, 0, 0, time.UTC))
+	assert.Equal(time.Date(2021, 03, 14, 13, 0, 0, 0, time.UTC), next.UTC())
+	assert.Equal(9, next.Hour())
+	assert.Equal(time.Date(2021, 03, 15, 13, 0, 0, 0, time.UTC), schedule.Next(next).UTC())
+
+	schedule, err = ParseString("TZ=America/New_York @daily")
+	assert.Nil(err)
+	assert.Equal(time.Date(2021, 11, 8, 5, 0, 0, 0, time.UTC), schedule.Next(time.Date(2021, 11, 7, 4, 0, 0, 0, time.UTC)).UTC())
+
+	for _, input := range []string{"CRON_TZ=Not/AZone 0 0 9 * * *", "CRON_TZ= 0 0 9 * * *", "TZ=America/New_York"} {
+		_, err = ParseString(input)
+		assert.NotNil(err, input)
+		assert.True(ex.Is(err, ErrStringScheduleInvalid), input)
+	}
+}
+
+func TestParseStringLocationFromAfter(t *testing.T) {
+	assert := assert.New(t)
+
+	ny := mustLoadLocation(t, "America/New_York")
+
+	schedule, err := ParseString("0 0 9 * * * *")
+	assert.Nil(err)
+	next := schedule.Next(time.Date(2021, 03, 13, 10, 0, 0, 0, ny))
+	assert.Equal(time.Date(2021, 03, 14, 13, 0, 0, 0, time.UTC), next.UTC())
+}
+
+func TestStringScheduleLocationSkippedTime(t *testing.T) {
+	assert := assert.New(t)
+
+	mustLoadLocation(t, "America/New_York")
+
+	// 2:30am doesn't happen on 2021-03-14 in new york; it runs at 3:30am EDT instead.
+	schedule, err := ParseString("CRON_TZ=America/New_York 0 30 2 * * * *")
+	assert.Nil(err)
+	next := schedule.Next(time.Date(2021, 03, 13, 7, 30, 0, 0, time.UTC))
+	assert.Equal(time.Date(2021, 03, 14, 7, 30, 0, 0, time.UTC), next.UTC())
+	assert.Equal(3, next.Hour())
+	assert.Equal(30, next.Minute())
+
+	next = schedule.Next(next)
+	assert.Equal(time.Date(2021, 03, 15, 6, 30, 0, 0, time.UTC), next.UTC())
+
+	// every hour at half past; 2:30am is skipped and 3:30am runs once.
+	schedule, err = ParseString("CRON_TZ=America/New_York 0 30 * * * * *")
+	assert.Nil(err)
+	next = schedule.Next(time.Date(2021, 03, 14, 6, 30, 0, 0, time.UTC)) // 1:30am EST
+	assert.Equal(time.Date(2021, 03, 14, 7, 30, 0, 0, time.UTC), next.UTC())
+	next = schedule.Next(next)
+	assert.Equal(time.Date(2021, 03, 14, 8, 30, 0, 0, time.UTC), next.UTC()) // 4:30am EDT
+}
+
+func TestStringScheduleLocationRepeatedTime(t *testing.T) {
+	assert := assert.New(t)
+
+	mustLoadLocation(t, "America/New_York")
+
+	// 1:30am happens twice on 2021-11-07 in new york; it only runs at the first (EDT) occurrence.
+	schedule, err := ParseString("CRON_TZ=America/New_York 0 30 1 * * * *")
+	assert.Nil(err)
+	next := schedule.Next(time.Date(2021, 11, 6, 5, 30, 0, 0, time.UTC))
+	assert.Equal(time.Date(2021, 11, 7, 5, 30, 0, 0, time.UTC), next.UTC())
+	next = schedule.Next(next)
+	assert.Equal(time.Date(2021, 11, 8, 6, 30, 0, 0, time.UTC), next.UTC())
+
+	// from within the second (EST) occurrence of the hour, the run has already happened.
+	next = schedule.Next(time.Date(2021, 11, 7, 6, 10, 0, 0, time.UTC))
+	assert.Equal(time.Date(2021, 11, 8, 6, 30, 0, 0, time.UTC), next.UTC())
+
+	// every hour at half past; the repeated 1:30am runs once.
+	schedule, err = ParseString("CRON_TZ=America/New_York 0 30 * * * * *")
+	assert.Nil(err)
+	next = schedule.Next(time.Date(2021, 11, 7, 4, 30, 0, 0, time.UTC)) // 12:30am EDT
+	assert.Equal(time.Date(2021, 11, 7, 5, 30, 0, 0, time.UTC), next.UTC())
+	next = schedule.Next(next)
+	assert.Equal(time.Date(2021, 11, 7, 7, 30, 0, 0, time.UTC), next.UTC()) // 2:30am EST
+
+	// every minute doesn't repeat the hour either.
+	schedule, err = ParseString("CRON_TZ=America/New_York * * * * *")
+	assert.Nil(err)
+	next = schedule.Next(time.Date(2021, 11, 7, 6, 10, 0, 0, time.UTC)) // 1:10am EST
+	assert.Equal(time.Date(2021, 11, 7, 7, 0, 0, 0, time.UTC), next.UTC())
+}
+
+func TestDailyAtLocation(t *testing.T) {
+	assert := assert.New(t)
+
+	ny := mustLoadLocation(t, "America/New_York")
+
+	schedule := DailyAt(9, 0, 0, ny)
+	next := schedule.Next(time.Date(2021, 03, 13, 15, 0, 0, 0, time.UTC))
+	assert.Equal(time.Date(2021, 03, 14, 13, 0, 0, 0, time.UTC), next.UTC())
+	next = schedule.Next(time.Date(2021, 11, 6, 14, 0, 0, 0, time.UTC))
+	assert.Equal(time.Date(2021, 11, 7, 14, 0, 0, 0, time.UTC), next.UTC())
+	assert.Contains(schedule.(*DailySchedule).String(), "America/New_York")
+
+	// 2:30am is skipped on the sunday of the spring transition.
+	schedule = WeeklyAt(2, 30, 0, ny, time.Sunday)
+	next = schedule.Next(time.Date(2021, 03, 10, 0, 0, 0, 0, time.UTC))
+	assert.Equal(time.Date(2021, 03, 14, 7, 30, 0, 0, time.UTC), next.UTC())
+
+	next = WeekdaysAt(9, 0, 0, ny).Next(time.Date(2021, 03, 12, 15, 0, 0, 0, time.UTC)) // friday 10am EST
+	assert.Equal(time.Date(2021, 03, 15, 13, 0, 0, 0, time.UTC), next.UTC())
+
+	next = WeekendsAt(9, 0, 0, ny).Next(time.Date(2021, 03, 12, 15, 0, 0, 0, time.UTC))
+	assert.Equal(time.Date(2021, 03, 13, 14, 0, 0, 0, time.UTC), next.UTC())
+
+	// a nil location is utc.
+	next = DailyAt(9, 0, 0, nil).Next(time.Date(2021, 03, 13, 15, 0, 0, 0, time.UTC))
+	assert.Equal(time.Date(2021, 03, 14, 9, 0, 0, 0, time.UTC), next)
+}
+
+func TestEveryHourAtLocation(t *testing.T) {
+	assert := assert.New(t)
+
+	kolkata := mustLoadLocation(t, "Asia/Kolkata")
+
+	next := EveryHourAt(0, 0, kolkata).Next(time.Date(2021, 01, 01, 0, 0, 0, 0, time.UTC)) // 5:30am IST
+	assert.Equal(time.Date(2021, 01, 01, 0, 30, 0, 0, time.UTC), next.UTC())
+
+	next = EveryHourAt(0, 0, nil).Next(time.Date(2021, 01, 01, 0, 0, 1, 0, time.UTC))
+	assert.Equal(time.Date(2021, 01, 01, 1, 0, 0, 0, time.UTC), next)
+}
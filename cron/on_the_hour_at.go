@@ -20,31 +20,36 @@ func EveryHourAtUTC(minute, second int) Schedule {
 	return OnTheHourAtUTCSchedule{Minute: minute, Second: second}
 }
 
+// EveryHourAt returns a schedule that fires every hour at a given minute in a given location.
+// The location only changes the schedule for locations with offsets that are not whole hours.
+// If the location is nil, UTC is used.
+func EveryHourAt(minute, second int, location *time.Location) Schedule {
+	return OnTheHourAtUTCSchedule{Minute: minute, Second: second, Location: location}
+}
+
 // OnTheHourAtUTCSchedule is a schedule that fires every hour on the given minute.
+// If Location is set, the minute is the minute of the hour in that location, otherwise it is in UTC.
 type OnTheHourAtUTCSchedule struct {
-	Minute int
-	Second int
+	Minute   int
+	Second   int
+	Location *time.Location
 }
 
 // String returns a string representation of the schedule.
 func (o OnTheHourAtUTCSchedule) String() string {
-	return fmt.Sprintf("on the hour at %v:%v", o.Minute, o.Second)
+	return fmt.Sprintf("on the hour at %v:%v%s", o.Minute, o.Second, formatLocation(o.Location))
 }
 
 // Next implements the chronometer Schedule api.
 func (o OnTheHourAtUTCSchedule) Next(after time.Time) time.Time {
-	var returnValue time.Time
-	now := Now()
-	if after.IsZero() {
-		returnValue = time.Date(now.Year(), now.Month(), now.Day(), now.Hour(), o.Minute, o.Second, 0, time.UTC)
-		if returnValue.Before(now) {
-			returnValue = returnValue.Add(time.Hour)
-		}
-	} else {
-		returnValue = time.Date(after.Year(), after.Month(), after.Day(), after.Hour(), o.Minute, o.Second, 0, time.UTC)
-		if returnValue.Before(after) {
-			returnValue = returnValue.Add(time.Hour)
-		}
+	return nextInLocation(after, o.Location, o.next)
+}
+
+// next returns the next wall clock time after a given wall clock time.
+func (o OnTheHourAtUTCSchedule) next(after time.Time) time.Time {
+	returnValue := time.Date(after.Year(), after.Month(), after.Day(), after.Hour(), o.Minute, o.Second, 0, time.UTC)
+	if returnValue.Before(after) {
+		returnValue = returnValue.Add(time.Hour)
 	}
 	return returnValue
 }
@@ -0,0 +1,74 @@
+package cron
+
+import (
+	"math/rand"
+	"time"
+)
+
+// RetryPolicy retries the job when an invocation returns an error, within the same invocation.
+//
+// Lifecycle hooks like `OnError` and `OnBroken` only fire once retries are exhausted, and each attempt
+// is recorded in `JobInvocation.Attempts`. Cancelled invocations are not retried.
+type RetryPolicy struct {
+	// MaxAttempts is the maximum number of times the job is run for an invocation, including the first attempt.
+	// If it is unset (or 1) failed invocations are not retried.
+	MaxAttempts int `json:"maxAttempts" yaml:"maxAttempts"`
+	// Backoff is the delay before the first retry; it doubles with each retry.
+	Backoff time.Duration `json:"backoff" yaml:"backoff"`
+	// MaxBackoff is the maximum delay between retries.
+	MaxBackoff time.Duration `json:"maxBackoff" yaml:"maxBackoff"`
+	// RetryOn returns if an error should be retried; if it is unset, all errors are retried.
+	RetryOn func(error) bool `json:"-" yaml:"-"`
+}
+
+// MaxAttemptsOrDefault returns a value or a default.
+func (rp RetryPolicy) MaxAttemptsOrDefault() int {
+	if rp.MaxAttempts > 0 {
+		return rp.MaxAttempts
+	}
+	return DefaultRetryMaxAttempts
+}
+
+// BackoffOrDefault returns a value or a default.
+func (rp RetryPolicy) BackoffOrDefault() time.Duration {
+	if rp.Backoff > 0 {
+		return rp.Backoff
+	}
+	return DefaultRetryBackoff
+}
+
+// MaxBackoffOrDefault returns a value or a default.
+func (rp RetryPolicy) MaxBackoffOrDefault() time.Duration {
+	if rp.MaxBackoff > 0 {
+		return rp.MaxBackoff
+	}
+	return DefaultRetryMaxBackoff
+}
+
+// ShouldRetry returns if an attempt (starting at 1) that returned an error should be retried.
+func (rp RetryPolicy) ShouldRetry(attempt int, err error) bool {
+	if err == nil || IsJobCancelled(err) || attempt >= rp.MaxAttemptsOrDefault() {
+		return false
+	}
+	if rp.RetryOn != nil {
+		return rp.RetryOn(err)
+	}
+	return true
+}
+
+// Delay returns the delay before retrying a given attempt (starting at 1), with jitter.
+//
+// The backoff doubles with each attempt up to the max backoff, and the delay is a random duration
+// between half of the backoff and the backoff so that retries from many jobs are spread out.
+func (rp RetryPolicy) Delay(attempt int) time.Duration {
+	backoff := rp.BackoffOrDefault()
+	max := rp.MaxBackoffOrDefault()
+	for x := 1; x < attempt && backoff < max; x++ {
+		backoff = backoff * 2
+	}
+	if backoff > max {
+		backoff = max
+	}
+	half := int64(backoff / 2)
+	return time.Duration(half + rand.Int63n(half+1))
+}
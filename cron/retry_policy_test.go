@@ -0,0 +1,165 @@
+package cron
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"testing"
+	"time"
+
+	"github.com/blend/go-sdk/assert"
+	"github.com/blend/go-sdk/ex"
+	"github.com/blend/go-sdk/logger"
+)
+
+func TestRetryPolicyShouldRetry(t *testing.T) {
+	assert := assert.New(t)
+
+	assert.False(RetryPolicy{}.ShouldRetry(1, fmt.Errorf("test")))
+
+	policy := RetryPolicy{MaxAttempts: 3}
+	assert.False(policy.ShouldRetry(1, nil))
+	assert.True(policy.ShouldRetry(1, fmt.Errorf("test")))
+	assert.True(policy.ShouldRetry(2, fmt.Errorf("test")))
+	assert.False(policy.ShouldRetry(3, fmt.Errorf("test")))
+	assert.False(policy.ShouldRetry(1, ex.New(ErrJobCancelled)))
+
+	policy.RetryOn = func(err error) bool { return !ex.Is(err, ErrJobNotFound) }
+	assert.True(policy.ShouldRetry(1, fmt.Errorf("test")))
+	assert.False(policy.ShouldRetry(1, ex.New(ErrJobNotFound)))
+}
+
+func TestRetryPolicyDelay(t *testing.T) {
+	assert := assert.New(t)
+
+	policy := RetryPolicy{Backoff: time.Second, MaxBackoff: 5 * time.Second}
+	for x := 0; x < 100; x++ {
+		delay := policy.Delay(1)
+		assert.True(delay >= 500*time.Millisecond && delay <= time.Second, delay.String())
+		delay = policy.Delay(2)
+		assert.True(delay >= time.Second && delay <= 2*time.Second, delay.String())
+		delay = policy.Delay(10)
+		assert.True(delay >= 2500*time.Millisecond && delay <= 5*time.Second, delay.String())
+	}
+
+	delay := RetryPolicy{}.Delay(1)
+	assert.True(delay >= DefaultRetryBackoff/2 && delay <= DefaultRetryBackoff, delay.String())
+}
+
+func TestJobSchedulerRetry(t *testing.T) {
+	assert := assert.New(t)
+
+	var attempts, retries, errors, broken int
+	retryEvents := make(chan Event, 2)
+	log := logger.All(logger.OptOutput(ioutil.Discard))
+	defer log.Close()
+	log.Listen(FlagRetry, "test", NewEventListener(func(_ context.Context, e Event) { retryEvents <- e }))
+	js := NewJobScheduler(NewJob(
+		OptJobName("retry"),
+		OptJobRetryPolicy(RetryPolicy{MaxAttempts: 3, Backoff: time.Millisecond}),
+		OptJobAction(func(_ context.Context) error {
+			attempts++
+			if attempts < 3 {
+				return fmt.Errorf("attempt %d", attempts)
+			}
+			return nil
+		}),
+		OptJobOnRetry(func(_ context.Context) { retries++ }),
+		OptJobOnError(func(_ context.Context) { errors++ }),
+		OptJobOnBroken(func(_ context.Context) { broken++ }),
+	), OptJobSchedulerLog(log))
+	js.SetLast(&JobInvocation{Status: JobInvocationStatusSuccess})
+
+	js.Run()
+
+	last := js.Last()
+	assert.Equal(JobInvocationStatusSuccess, last.Status)
+	assert.Nil(last.Err)
+	assert.Equal(3, attempts)
+	assert.Equal(2, retries)
+	assert.Zero(errors)
+	assert.Zero(broken)
+	assert.Len(last.Attempts, 3)
+	assert.Equal("attempt 1", last.Attempts[0].Err.Error())
+	assert.Equal("attempt 2", last.Attempts[1].Err.Error())
+	assert.Nil(last.Attempts[2].Err)
+	assert.False(last.Attempts[2].Complete.Before(last.Attempts[2].Started))
+
+	e := <-retryEvents
+	assert.Equal(1, e.Attempt)
+	assert.Equal(last.ID, e.JobInvocation)
+	e = <-retryEvents
+	assert.Equal(2, e.Attempt)
+}
+
+func TestJobSchedulerRetryExhausted(t *testing.T) {
+	assert := assert.New(t)
+
+	var attempts, errors, broken int
+	js := NewJobScheduler(NewJob(
+		OptJobName("retry"),
+		OptJobRetryPolicy(RetryPolicy{MaxAttempts: 2, Backoff: time.Millisecond}),
+		OptJobAction(func(_ context.Context) error {
+			attempts++
+			return fmt.Errorf("attempt %d", attempts)
+		}),
+		OptJobOnError(func(_ context.Context) { errors++ }),
+		OptJobOnBroken(func(_ context.Context) { broken++ }),
+	))
+	js.SetLast(&JobInvocation{Status: JobInvocationStatusSuccess})
+
+	js.Run()
+
+	last := js.Last()
+	assert.Equal(JobInvocationStatusErrored, last.Status)
+	assert.Equal("attempt 2", last.Err.Error())
+	assert.Equal(2, attempts)
+	assert.Len(last.Attempts, 2)
+	assert.Equal(1, errors)
+	assert.Equal(1, broken)
+}
+
+func TestJobSchedulerRetryOn(t *testing.T) {
+	assert := assert.New(t)
+
+	var attempts int
+	js := NewJobScheduler(NewJob(
+		OptJobName("retry"),
+		OptJobRetryPolicy(RetryPolicy{
+			MaxAttempts: 3,
+			Backoff:     time.Millisecond,
+			RetryOn:     func(err error) bool { return err.Error() != "permanent" },
+		}),
+		OptJobAction(func(_ context.Context) error {
+			attempts++
+			return fmt.Errorf("permanent")
+		}),
+	))
+	js.Run()
+	assert.Equal(1, attempts)
+	assert.Len(js.Last().Attempts, 1)
+	assert.Equal(JobInvocationStatusErrored, js.Last().Status)
+}
+
+func TestJobSchedulerRetryCancelled(t *testing.T) {
+	assert := assert.New(t)
+
+	retrying := make(chan struct{})
+	js := NewJobScheduler(NewJob(
+		OptJobName("retry"),
+		OptJobRetryPolicy(RetryPolicy{MaxAttempts: 3, Backoff: time.Hour}),
+		OptJobAction(func(_ context.Context) error {
+			return fmt.Errorf("failed")
+		}),
+		OptJobOnRetry(func(_ context.Context) { close(retrying) }),
+	))
+	ji, done, err := js.RunAsync()
+	assert.Nil(err)
+	<-retrying
+
+	// the invocation is cancelled while waiting to retry.
+	ji.Cancel()
+	<-done
+	assert.Equal(JobInvocationStatusCancelled, js.Last().Status)
+	assert.Len(js.Last().Attempts, 1)
+}
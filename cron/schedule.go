@@ -10,3 +10,21 @@ type Schedule interface {
 	// the job hasn't run yet. If time.Time{} is returned by the schedule it is inferred that the job should not run again.
 	Next(time.Time) time.Time
 }
+
+// NextRuns returns the next (up to) count times a schedule runs after a given time,
+// e.g. to show users when a job will run, stopping early if the schedule stops returning later times.
+//
+// Schedules that keep state between calls to `Next` (e.g. `Immediately` or `Jitter`) should not be passed
+// a schedule that is in use by a job, as the returned times would advance it.
+func NextRuns(schedule Schedule, after time.Time, count int) []time.Time {
+	var output []time.Time
+	for len(output) < count {
+		next := schedule.Next(after)
+		if next.IsZero() || !next.After(after) {
+			break
+		}
+		output = append(output, next)
+		after = next
+	}
+	return output
+}
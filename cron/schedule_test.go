@@ -101,3 +101,21 @@ func TestOnceAt(t *testing.T) {
 	result = s.Next(after)
 	assert.True(result.IsZero())
 }
+
+func TestNextRuns(t *testing.T) {
+	assert := assert.New(t)
+
+	schedule, err := ParseString("0 0 12 ? * 5L")
+	assert.Nil(err)
+	runs := NextRuns(schedule, time.Date(2021, 01, 01, 0, 0, 0, 0, time.UTC), 3)
+	assert.Equal([]time.Time{
+		time.Date(2021, 01, 29, 12, 0, 0, 0, time.UTC),
+		time.Date(2021, 02, 26, 12, 0, 0, 0, time.UTC),
+		time.Date(2021, 03, 26, 12, 0, 0, 0, time.UTC),
+	}, runs)
+
+	// schedules that stop running return fewer times.
+	at := time.Date(2021, 01, 02, 0, 0, 0, 0, time.UTC)
+	assert.Equal([]time.Time{at}, NextRuns(OnceAtUTC(at), time.Date(2021, 01, 01, 0, 0, 0, 0, time.UTC), 3))
+	assert.Empty(NextRuns(Every(time.Hour), time.Now(), 0))
+}
@@ -22,12 +22,22 @@ import (
 	Seconds        No           0-59              * / , -
 	Minutes        Yes          0-59              * / , -
 	Hours          Yes          0-23              * / , -
-	Day of month   Yes          1-31              * / , - L W
+	Day of month   Yes          1-31              * / , - ? L W
 	Month          Yes          1-12 or JAN-DEC   * / , -
-	Day of week    Yes          0-6 or SUN-SAT    * / , - L #
+	Day of week    Yes          0-6 or SUN-SAT    * / , - ? L #
 	Year           No           1970–2099         * / , -
 */
 /*
+The day of month and day of week fields support modifiers for days that depend on the month:
+	L in the day of month field is the last day of the month
+	LW in the day of month field is the last weekday (Monday to Friday) of the month
+	15W in the day of month field is the weekday nearest to the 15th, in the same month
+	5L in the day of week field is the last Friday of the month
+	1#2 in the day of week field is the second Monday of the month
+A ? can be used in either field instead of a *.
+If both fields are set, a day must match both of them.
+*/
+/*
 You can also use shorthands for the cron string:
 	@yearly is equivalent to "0 0 0 1 1 * *"
 	@monthly is equivalent to "0 0 0 1 * * *"
@@ -36,7 +46,20 @@ You can also use shorthands for the cron string:
 	@hourly is equivalent to "0 0 * * * * *"
 	@every xyz will parse the `xyz` value as a duration and return an every schedule for that
 */
+/*
+The string can be prefixed with a location for the schedule, which should be an IANA time zone name:
+	CRON_TZ=America/New_York 0 0 9 * * * *
+	TZ=Europe/London @daily
+Otherwise the schedule is computed in the location of the time passed to `Next`.
+See `StringSchedule.Location` for how daylight saving time transitions are handled.
+*/
 func ParseString(cronString string) (Schedule, error) {
+	original := cronString
+	location, cronString, err := parseLocationPrefix(cronString)
+	if err != nil {
+		return nil, err
+	}
+
 	// escape shorthands.
 	if shorthand, ok := StringScheduleShorthands[strings.TrimSpace(cronString)]; ok {
 		cronString = shorthand
@@ -77,7 +100,7 @@ func ParseString(cronString string) (Schedule, error) {
 		return nil, ex.New(ErrStringScheduleInvalid, ex.OptInner(err), ex.OptMessage("hours invalid"))
 	}
 
-	days, err := parsePart(parts[3], parseInt, between(1, 32))
+	days, err := parseDaysOfMonth(parts[3])
 	if err != nil {
 		return nil, ex.New(ErrStringScheduleInvalid, ex.OptInner(err), ex.OptMessage("days invalid"))
 	}
@@ -87,7 +110,7 @@ func ParseString(cronString string) (Schedule, error) {
 		return nil, ex.New(ErrStringScheduleInvalid, ex.OptInner(err), ex.OptMessage("months invalid"))
 	}
 
-	daysOfWeek, err := parsePart(parts[5], parseDayOfWeek, between(0, 7))
+	daysOfWeek, err := parseDaysOfWeek(parts[5])
 	if err != nil {
 		return nil, ex.New(ErrStringScheduleInvalid, ex.OptInner(err), ex.OptMessage("days of week invalid"))
 	}
@@ -98,14 +121,20 @@ func ParseString(cronString string) (Schedule, error) {
 	}
 
 	schedule := &StringSchedule{
-		Original:    cronString,
-		Seconds:     seconds,
-		Minutes:     minutes,
-		Hours:       hours,
-		DaysOfMonth: days,
-		Months:      months,
-		DaysOfWeek:  daysOfWeek,
-		Years:       years,
+		Original:           original,
+		Location:           location,
+		Seconds:            seconds,
+		Minutes:            minutes,
+		Hours:              hours,
+		DaysOfMonth:        days.Days,
+		LastDayOfMonth:     days.Last,
+		LastWeekdayOfMonth: days.LastWeekday,
+		NearestWeekdays:    days.NearestWeekdays,
+		Months:             months,
+		DaysOfWeek:         daysOfWeek.Days,
+		LastDaysOfWeek:     daysOfWeek.Last,
+		NthDaysOfWeek:      daysOfWeek.Nth,
+		Years:              years,
 	}
 	return schedule, nil
 }
@@ -116,6 +145,13 @@ const (
 	ErrStringScheduleComponents      ex.Class = "cron: must have at least (5) components space delimited; ex: '0 0 * * * * *'"
 	ErrStringScheduleValueOutOfRange ex.Class = "cron: string schedule part out of range"
 	ErrStringScheduleInvalidRange    ex.Class = "cron: range (from-to) invalid"
+	ErrStringScheduleInvalidLocation ex.Class = "cron: location invalid"
+)
+
+// String schedule location prefixes.
+const (
+	StringScheduleLocationPrefix    = "CRON_TZ="
+	StringScheduleLocationPrefixAlt = "TZ="
 )
 
 // String schedule shorthands labels
@@ -134,6 +170,7 @@ var (
 		StringScheduleShorthandAnnually: "0 0 0 1 1 * *",
 		StringScheduleShorthandYearly:   "0 0 0 1 1 * *",
 		StringScheduleShorthandMonthly:  "0 0 0 1 * * *",
+		StringScheduleShorthandWeekly:   "0 0 0 * * 0 *",
 		StringScheduleShorthandDaily:    "0 0 0 * * * *",
 		StringScheduleShorthandHourly:   "0 0 * * * * *",
 	}
@@ -146,8 +183,14 @@ var (
 )
 
 // StringSchedule is a schedule generated from a cron string.
+//
+// If Location is set, the schedule is computed in that location, otherwise it is computed in the location of the time passed to `Next`.
+// Times that are skipped by a daylight saving time transition (e.g. 2:30am when clocks move from 2am to 3am) run
+// later by the length of the transition (i.e. at 3:30am), and times that are repeated by a transition
+// (e.g. 1:30am when clocks move from 2am back to 1am) only run once, at the first occurrence.
 type StringSchedule struct {
 	Original string
+	Location *time.Location
 
 	Seconds     []int
 	Minutes     []int
@@ -156,6 +199,23 @@ type StringSchedule struct {
 	Months      []int
 	DaysOfWeek  []int
 	Years       []int
+
+	// LastDayOfMonth is set by `L` in the day of month field, and matches the last day of each month.
+	LastDayOfMonth bool
+	// LastWeekdayOfMonth is set by `LW` in the day of month field, and matches the last weekday of each month.
+	LastWeekdayOfMonth bool
+	// NearestWeekdays are days of the month set with `W`, e.g. `15W`, that match the weekday nearest to the day in the same month.
+	NearestWeekdays []int
+	// LastDaysOfWeek are days of the week set with `L`, e.g. `5L`, that match the last of the day in each month.
+	LastDaysOfWeek []int
+	// NthDaysOfWeek are days of the week set with `#`, e.g. `1#2`, that match the nth of the day in each month.
+	NthDaysOfWeek []NthDayOfWeek
+}
+
+// NthDayOfWeek is the nth of a day of the week in a month, e.g. the second Monday.
+type NthDayOfWeek struct {
+	DayOfWeek int
+	N         int
 }
 
 // String returns the original string schedule.
@@ -170,9 +230,9 @@ func (ss *StringSchedule) FullString() string {
 		csvOfInts(ss.Seconds, "*"),
 		csvOfInts(ss.Minutes, "*"),
 		csvOfInts(ss.Hours, "*"),
-		csvOfInts(ss.DaysOfMonth, "*"),
+		ss.daysOfMonthString(),
 		csvOfInts(ss.Months, "*"),
-		csvOfInts(ss.DaysOfWeek, "*"),
+		ss.daysOfWeekString(),
 		csvOfInts(ss.Years, "*"),
 	}
 	return strings.Join(fields, " ")
@@ -180,19 +240,39 @@ func (ss *StringSchedule) FullString() string {
 
 // Next implements cron.Schedule.
 func (ss *StringSchedule) Next(after time.Time) time.Time {
-	working := after
-	if after.IsZero() {
-		working = Now()
+	location := ss.Location
+	if location == nil && !after.IsZero() {
+		location = after.Location()
 	}
+	return nextInLocation(after, location, ss.next)
+}
+
+// next returns the next wall clock time after a given wall clock time.
+func (ss *StringSchedule) next(after time.Time) time.Time {
+	if ss.hasDayModifiers() || ss.hasBothDayFields() || ss.hasMonthDependentDays() {
+		return ss.nextMatchingDay(after)
+	}
+
+	working := after
 	original := working
 
 	if len(ss.Years) > 0 {
+		var didSet bool
 		for _, year := range ss.Years {
-			if year >= working.Year() {
+			if year == working.Year() {
+				didSet = true
+				break
+			}
+			if year > working.Year() {
 				working = advanceYearTo(working, year)
+				didSet = true
 				break
 			}
 		}
+		// the schedule has no years left.
+		if !didSet {
+			return Zero
+		}
 	}
 
 	if len(ss.Months) > 0 {
@@ -330,7 +410,7 @@ func (ss *StringSchedule) Next(after time.Time) time.Time {
 		}
 		if !didSet {
 			working = advanceMinute(working)
-			for _, second := range ss.Hours {
+			for _, second := range ss.Seconds {
 				if second >= working.Second() {
 					working = advanceSecondTo(working, second)
 					break
@@ -339,9 +419,45 @@ func (ss *StringSchedule) Next(after time.Time) time.Time {
 		}
 	}
 
+	// the other fields can move the time past the end of a year of the schedule,
+	// in which case we start again from the next year of the schedule.
+	if len(ss.Years) > 0 && !containsInt(ss.Years, working.Year()) {
+		for _, year := range ss.Years {
+			if year > working.Year() {
+				return ss.next(advanceYearTo(working, year).Add(-time.Nanosecond))
+			}
+		}
+		return Zero
+	}
 	return working
 }
 
+// parseLocationPrefix parses and removes a location prefix from a cron string, if it has one.
+func parseLocationPrefix(cronString string) (*time.Location, string, error) {
+	cronString = strings.TrimSpace(cronString)
+	var name string
+	if strings.HasPrefix(cronString, StringScheduleLocationPrefix) {
+		name = strings.TrimPrefix(cronString, StringScheduleLocationPrefix)
+	} else if strings.HasPrefix(cronString, StringScheduleLocationPrefixAlt) {
+		name = strings.TrimPrefix(cronString, StringScheduleLocationPrefixAlt)
+	} else {
+		return nil, cronString, nil
+	}
+
+	var rest string
+	if index := strings.IndexAny(name, " \t"); index >= 0 {
+		name, rest = name[:index], strings.TrimSpace(name[index:])
+	}
+	if name == "" {
+		return nil, "", ex.New(ErrStringScheduleInvalid, ex.OptInner(ErrStringScheduleInvalidLocation), ex.OptMessagef("provided string; %s", cronString))
+	}
+	location, err := time.LoadLocation(name)
+	if err != nil {
+		return nil, "", ex.New(ErrStringScheduleInvalid, ex.OptInner(ex.New(ErrStringScheduleInvalidLocation, ex.OptInner(err))), ex.OptMessagef("location; %s", name))
+	}
+	return location, rest, nil
+}
+
 func parsePart(values string, parser func(string) (int, error), validator func(int) bool) ([]int, error) {
 	if values == string(cronSpecialStar) {
 		return nil, nil
@@ -359,7 +475,7 @@ func parsePart(values string, parser func(string) (int, error), validator func(i
 	for x := 0; x < len(components); x++ {
 		component = components[x]
 		if strings.Contains(component, string(cronSpecialDash)) {
-			rangeValues, err := parseRange(values, parser, validator)
+			rangeValues, err := parseRange(component, parser, validator)
 			if err != nil {
 				return nil, err
 			}
@@ -474,6 +590,15 @@ func between(min, max int) func(int) bool {
 	}
 }
 
+func containsInt(values []int, value int) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
 func mapKeysToArray(values map[int]bool) []int {
 	output := make([]int, len(values))
 	var index int
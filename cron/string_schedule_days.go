@@ -0,0 +1,297 @@
+package cron
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/blend/go-sdk/ex"
+)
+
+// daysOfMonthPart is a parsed day of month field.
+type daysOfMonthPart struct {
+	Days            []int
+	Last            bool
+	LastWeekday     bool
+	NearestWeekdays []int
+}
+
+// daysOfWeekPart is a parsed day of week field.
+type daysOfWeekPart struct {
+	Days []int
+	Last []int
+	Nth  []NthDayOfWeek
+}
+
+// parseDaysOfMonth parses the day of month field, including the `L`, `LW` and `W` modifiers.
+func parseDaysOfMonth(values string) (output daysOfMonthPart, err error) {
+	if values == string(cronSpecialQuestion) {
+		return
+	}
+
+	var rest []string
+	for _, component := range strings.Split(values, string(cronSpecialComma)) {
+		switch {
+		case component == string(cronSpecialLast):
+			output.Last = true
+		case component == string(cronSpecialLast)+string(cronSpecialWeekday):
+			output.LastWeekday = true
+		case strings.HasSuffix(component, string(cronSpecialWeekday)):
+			var day int
+			day, err = parseInt(strings.TrimSuffix(component, string(cronSpecialWeekday)))
+			if err != nil {
+				err = ex.New(err)
+				return
+			}
+			if !between(1, 32)(day) {
+				err = ex.New(ErrStringScheduleValueOutOfRange, ex.OptMessagef("nearest weekday out of range (1-31): %s", component))
+				return
+			}
+			output.NearestWeekdays = append(output.NearestWeekdays, day)
+		default:
+			rest = append(rest, component)
+		}
+	}
+	sort.Ints(output.NearestWeekdays)
+	if len(rest) > 0 {
+		output.Days, err = parsePart(strings.Join(rest, string(cronSpecialComma)), parseInt, between(1, 32))
+	}
+	return
+}
+
+// parseDaysOfWeek parses the day of week field, including the `L` and `#` modifiers.
+func parseDaysOfWeek(values string) (output daysOfWeekPart, err error) {
+	if values == string(cronSpecialQuestion) {
+		return
+	}
+
+	var rest []string
+	for _, component := range strings.Split(values, string(cronSpecialComma)) {
+		switch {
+		case len(component) > 1 && strings.HasSuffix(component, string(cronSpecialLast)):
+			var dow int
+			dow, err = parseDayOfWeek(strings.TrimSuffix(component, string(cronSpecialLast)))
+			if err != nil {
+				return
+			}
+			output.Last = append(output.Last, dow)
+		case strings.Contains(component, string(cronSpecialDayOfMonth)):
+			parts := strings.Split(component, string(cronSpecialDayOfMonth))
+			if len(parts) != 2 {
+				err = ex.New(ErrStringScheduleValueOutOfRange, ex.OptMessagef("invalid nth day of week: %s", component))
+				return
+			}
+			var nth NthDayOfWeek
+			if nth.DayOfWeek, err = parseDayOfWeek(parts[0]); err != nil {
+				return
+			}
+			if nth.N, err = parseInt(parts[1]); err != nil {
+				err = ex.New(err)
+				return
+			}
+			if !between(1, 6)(nth.N) {
+				err = ex.New(ErrStringScheduleValueOutOfRange, ex.OptMessagef("nth day of week out of range (1-5): %s", component))
+				return
+			}
+			output.Nth = append(output.Nth, nth)
+		default:
+			rest = append(rest, component)
+		}
+	}
+	sort.Ints(output.Last)
+	if len(rest) > 0 {
+		output.Days, err = parsePart(strings.Join(rest, string(cronSpecialComma)), parseDayOfWeek, between(0, 7))
+	}
+	return
+}
+
+// hasDayModifiers returns if the schedule has any days that depend on the month.
+func (ss *StringSchedule) hasDayModifiers() bool {
+	return ss.LastDayOfMonth || ss.LastWeekdayOfMonth || len(ss.NearestWeekdays) > 0 || len(ss.LastDaysOfWeek) > 0 || len(ss.NthDaysOfWeek) > 0
+}
+
+// hasBothDayFields returns if the schedule sets both the day of month and day of week fields, which a day must both match.
+func (ss *StringSchedule) hasBothDayFields() bool {
+	return len(ss.DaysOfMonth) > 0 && len(ss.DaysOfWeek) > 0
+}
+
+// hasMonthDependentDays returns if the schedule has days of the month that not every month has.
+func (ss *StringSchedule) hasMonthDependentDays() bool {
+	for _, day := range ss.DaysOfMonth {
+		if day > 28 {
+			return true
+		}
+	}
+	return false
+}
+
+// nextMatchingDay returns the next wall clock time after a given wall clock time by checking each day in turn,
+// which is used for schedules whose days can't be found field by field, i.e. with day modifiers, with both day
+// fields set, or with days that not every month has.
+func (ss *StringSchedule) nextMatchingDay(after time.Time) time.Time {
+	for day := advanceDayBy(after, 0); day.Year() < 2100; day = advanceDay(day) {
+		if !ss.matchesDay(day) {
+			continue
+		}
+		if next := ss.nextTimeOfDay(day, after); !next.IsZero() {
+			return next
+		}
+	}
+	return Zero
+}
+
+// matchesDay returns if the schedule runs on a given day.
+func (ss *StringSchedule) matchesDay(day time.Time) bool {
+	if len(ss.Years) > 0 && !containsInt(ss.Years, day.Year()) {
+		return false
+	}
+	if len(ss.Months) > 0 && !containsInt(ss.Months, int(day.Month())) {
+		return false
+	}
+	return ss.matchesDayOfMonth(day) && ss.matchesDayOfWeek(day)
+}
+
+// matchesDayOfMonth returns if a day matches the day of month field.
+func (ss *StringSchedule) matchesDayOfMonth(day time.Time) bool {
+	if len(ss.DaysOfMonth) == 0 && !ss.LastDayOfMonth && !ss.LastWeekdayOfMonth && len(ss.NearestWeekdays) == 0 {
+		return true
+	}
+	if containsInt(ss.DaysOfMonth, day.Day()) {
+		return true
+	}
+	last := lastDayOfMonth(day)
+	if ss.LastDayOfMonth && day.Day() == last.Day() {
+		return true
+	}
+	if ss.LastWeekdayOfMonth && day.Day() == nearestWeekday(last).Day() {
+		return true
+	}
+	for _, nearest := range ss.NearestWeekdays {
+		if nearest > last.Day() {
+			continue
+		}
+		if day.Day() == nearestWeekday(advanceDayTo(day, nearest)).Day() {
+			return true
+		}
+	}
+	return false
+}
+
+// matchesDayOfWeek returns if a day matches the day of week field.
+func (ss *StringSchedule) matchesDayOfWeek(day time.Time) bool {
+	if len(ss.DaysOfWeek) == 0 && len(ss.LastDaysOfWeek) == 0 && len(ss.NthDaysOfWeek) == 0 {
+		return true
+	}
+	dow := int(day.Weekday())
+	if containsInt(ss.DaysOfWeek, dow) {
+		return true
+	}
+	if containsInt(ss.LastDaysOfWeek, dow) && day.AddDate(0, 0, 7).Month() != day.Month() {
+		return true
+	}
+	for _, nth := range ss.NthDaysOfWeek {
+		if nth.DayOfWeek == dow && (day.Day()-1)/7+1 == nth.N {
+			return true
+		}
+	}
+	return false
+}
+
+// nextTimeOfDay returns the first time of the schedule on a given day that is after a given time, or a zero time.
+func (ss *StringSchedule) nextTimeOfDay(day, after time.Time) time.Time {
+	sameDay := day.Equal(advanceDayBy(after, 0))
+	for _, hour := range fieldValues(ss.Hours, 0, 23) {
+		if sameDay && hour < after.Hour() {
+			continue
+		}
+		for _, minute := range fieldValues(ss.Minutes, 0, 59) {
+			for _, second := range fieldValues(ss.Seconds, 0, 59) {
+				if t := time.Date(day.Year(), day.Month(), day.Day(), hour, minute, second, 0, day.Location()); t.After(after) {
+					return t
+				}
+			}
+		}
+	}
+	return Zero
+}
+
+// daysOfMonthString returns the day of month field as expanded, with its modifiers.
+func (ss *StringSchedule) daysOfMonthString() string {
+	var values []string
+	if len(ss.DaysOfMonth) > 0 {
+		values = append(values, csvOfInts(ss.DaysOfMonth, ""))
+	}
+	if ss.LastDayOfMonth {
+		values = append(values, string(cronSpecialLast))
+	}
+	if ss.LastWeekdayOfMonth {
+		values = append(values, string(cronSpecialLast)+string(cronSpecialWeekday))
+	}
+	for _, day := range ss.NearestWeekdays {
+		values = append(values, strconv.Itoa(day)+string(cronSpecialWeekday))
+	}
+	if len(values) == 0 {
+		return string(cronSpecialStar)
+	}
+	return strings.Join(values, string(cronSpecialComma))
+}
+
+// daysOfWeekString returns the day of week field as expanded, with its modifiers.
+func (ss *StringSchedule) daysOfWeekString() string {
+	var values []string
+	if len(ss.DaysOfWeek) > 0 {
+		values = append(values, csvOfInts(ss.DaysOfWeek, ""))
+	}
+	for _, dow := range ss.LastDaysOfWeek {
+		values = append(values, strconv.Itoa(dow)+string(cronSpecialLast))
+	}
+	for _, nth := range ss.NthDaysOfWeek {
+		values = append(values, fmt.Sprintf("%d%c%d", nth.DayOfWeek, cronSpecialDayOfMonth, nth.N))
+	}
+	if len(values) == 0 {
+		return string(cronSpecialStar)
+	}
+	return strings.Join(values, string(cronSpecialComma))
+}
+
+// lastDayOfMonth returns the last day of the month of a given day.
+func lastDayOfMonth(day time.Time) time.Time {
+	return advanceMonth(day).AddDate(0, 0, -1)
+}
+
+// nearestWeekday returns the weekday (Monday to Friday) nearest to a given day, without leaving its month.
+func nearestWeekday(day time.Time) time.Time {
+	switch day.Weekday() {
+	case time.Saturday:
+		if day.Day() == 1 {
+			return day.AddDate(0, 0, 2)
+		}
+		return day.AddDate(0, 0, -1)
+	case time.Sunday:
+		if next := day.AddDate(0, 0, 1); next.Month() == day.Month() {
+			return next
+		}
+		return day.AddDate(0, 0, -2)
+	default:
+		return day
+	}
+}
+
+// fieldValues returns the values of a field between a min and a max (inclusive), or all of them if the field is unset.
+func fieldValues(values []int, min, max int) []int {
+	var output []int
+	if len(values) == 0 {
+		for x := min; x <= max; x++ {
+			output = append(output, x)
+		}
+		return output
+	}
+	for _, value := range values {
+		if value >= min && value <= max {
+			output = append(output, value)
+		}
+	}
+	return output
+}
@@ -0,0 +1,208 @@
+package cron
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// DescribeString parses a cron formatted string and returns a human readable description of when it runs,
+// e.g. "At 09:00, on Monday through Friday" for "0 0 9 * * MON-FRI".
+// It returns an error if the string is invalid; see `ParseString` for the format.
+func DescribeString(cronString string) (string, error) {
+	schedule, err := ParseString(cronString)
+	if err != nil {
+		return "", err
+	}
+	if typed, ok := schedule.(*StringSchedule); ok {
+		return typed.Description(), nil
+	}
+	return capitalize(fmt.Sprint(schedule)), nil
+}
+
+// Description returns a human readable description of when the schedule runs.
+func (ss *StringSchedule) Description() string {
+	phrases := []string{
+		ss.describeTime(),
+	}
+	if daysOfMonth := ss.describeDaysOfMonth(); daysOfMonth != "" {
+		phrases = append(phrases, daysOfMonth)
+	}
+	if daysOfWeek := ss.describeDaysOfWeek(); daysOfWeek != "" {
+		phrases = append(phrases, daysOfWeek)
+	}
+	if months := fieldValues(ss.Months, 1, 12); len(ss.Months) > 0 && len(months) > 0 {
+		phrases = append(phrases, "in "+describeValues(months, "", "", monthName))
+	}
+	if len(ss.Years) > 0 {
+		phrases = append(phrases, "in "+describeValues(ss.Years, "", "", strconv.Itoa))
+	}
+	description := capitalize(strings.Join(phrases, ", "))
+	if ss.Location != nil {
+		description = description + " (" + ss.Location.String() + ")"
+	}
+	return description
+}
+
+// describeTime describes the seconds, minutes and hours fields.
+func (ss *StringSchedule) describeTime() string {
+	seconds, secondsStep := describedField(ss.Seconds, 0, 59)
+	minutes, minutesStep := describedField(ss.Minutes, 0, 59)
+	hours, hoursStep := describedField(ss.Hours, 0, 23)
+
+	// a few times a day, e.g. "at 09:00 and 17:00".
+	if len(seconds) == 1 && len(minutes) == 1 && len(hours) > 0 && len(hours) <= 4 && hoursStep == 0 {
+		times := make([]string, 0, len(hours))
+		for _, hour := range hours {
+			times = append(times, clockTime(hour, minutes[0], seconds[0]))
+		}
+		return "at " + joinAnd(times)
+	}
+
+	var phrases []string
+	switch {
+	case seconds == nil:
+		phrases = append(phrases, "every second")
+	case secondsStep > 0:
+		phrases = append(phrases, fmt.Sprintf("every %d seconds", secondsStep))
+	case len(seconds) == 1 && seconds[0] == 0:
+	default:
+		phrases = append(phrases, "at "+describeValues(seconds, "second", "seconds", strconv.Itoa))
+	}
+
+	switch {
+	case minutes == nil:
+		if len(phrases) == 0 {
+			phrases = append(phrases, "every minute")
+		}
+	case minutesStep > 0:
+		phrases = append(phrases, fmt.Sprintf("every %d minutes", minutesStep))
+	case len(minutes) == 1 && minutes[0] == 0 && len(phrases) == 0:
+		if hoursStep == 0 {
+			phrases = append(phrases, "every hour")
+		}
+	default:
+		phrases = append(phrases, "at "+describeValues(minutes, "minute", "minutes", strconv.Itoa)+" past the hour")
+	}
+
+	switch {
+	case hours == nil:
+	case hoursStep > 0:
+		phrases = append(phrases, fmt.Sprintf("every %d hours", hoursStep))
+	default:
+		phrases = append(phrases, "during "+describeValues(hours, "hour", "hours", strconv.Itoa))
+	}
+	return strings.Join(phrases, ", ")
+}
+
+// describeDaysOfMonth describes the day of month field, including its modifiers.
+func (ss *StringSchedule) describeDaysOfMonth() string {
+	var phrases []string
+	if days := fieldValues(ss.DaysOfMonth, 1, 31); len(ss.DaysOfMonth) > 0 && len(days) > 0 {
+		phrases = append(phrases, "on "+describeValues(days, "day", "days", strconv.Itoa)+" of the month")
+	}
+	if ss.LastDayOfMonth {
+		phrases = append(phrases, "on the last day of the month")
+	}
+	if ss.LastWeekdayOfMonth {
+		phrases = append(phrases, "on the last weekday of the month")
+	}
+	for _, day := range ss.NearestWeekdays {
+		phrases = append(phrases, fmt.Sprintf("on the weekday nearest day %d of the month", day))
+	}
+	return strings.Join(phrases, " or ")
+}
+
+// describeDaysOfWeek describes the day of week field, including its modifiers.
+func (ss *StringSchedule) describeDaysOfWeek() string {
+	var phrases []string
+	if len(ss.DaysOfWeek) > 0 {
+		phrases = append(phrases, "on "+describeValues(ss.DaysOfWeek, "", "", dayOfWeekName))
+	}
+	for _, dow := range ss.LastDaysOfWeek {
+		phrases = append(phrases, "on the last "+dayOfWeekName(dow)+" of the month")
+	}
+	for _, nth := range ss.NthDaysOfWeek {
+		phrases = append(phrases, "on the "+ordinals[nth.N]+" "+dayOfWeekName(nth.DayOfWeek)+" of the month")
+	}
+	return strings.Join(phrases, " or ")
+}
+
+// describedField returns the values of a field between a min and a max (inclusive) and the step between them
+// if they are every nth value (e.g. from `*/15`), or nil values if every value is set.
+func describedField(values []int, min, max int) ([]int, int) {
+	if len(values) == 0 {
+		return nil, 0
+	}
+	values = fieldValues(values, min, max)
+	if len(values) < 2 || values[0] != min {
+		return values, 0
+	}
+	step := values[1] - values[0]
+	for x := 2; x < len(values); x++ {
+		if values[x]-values[x-1] != step {
+			return values, 0
+		}
+	}
+	if values[len(values)-1]+step <= max {
+		return values, 0
+	}
+	if step == 1 {
+		return nil, 0
+	}
+	return values, step
+}
+
+// describeValues describes a list of values, e.g. "hour 9", "hours 9 and 17" or "hours 9 through 17".
+func describeValues(values []int, singular, plural string, format func(int) string) string {
+	var unit, description string
+	switch {
+	case len(values) == 1:
+		unit, description = singular, format(values[0])
+	case len(values) > 2 && values[len(values)-1]-values[0] == len(values)-1:
+		unit, description = plural, format(values[0])+" through "+format(values[len(values)-1])
+	default:
+		formatted := make([]string, 0, len(values))
+		for _, value := range values {
+			formatted = append(formatted, format(value))
+		}
+		unit, description = plural, joinAnd(formatted)
+	}
+	if unit == "" {
+		return description
+	}
+	return unit + " " + description
+}
+
+// joinAnd joins values as an english list, e.g. "a, b and c".
+func joinAnd(values []string) string {
+	if len(values) < 2 {
+		return strings.Join(values, "")
+	}
+	return strings.Join(values[:len(values)-1], ", ") + " and " + values[len(values)-1]
+}
+
+func clockTime(hour, minute, second int) string {
+	if second == 0 {
+		return fmt.Sprintf("%02d:%02d", hour, minute)
+	}
+	return fmt.Sprintf("%02d:%02d:%02d", hour, minute, second)
+}
+
+func monthName(month int) string {
+	return time.Month(month).String()
+}
+
+func dayOfWeekName(dow int) string {
+	return time.Weekday(dow).String()
+}
+
+func capitalize(value string) string {
+	if value == "" {
+		return value
+	}
+	return strings.ToUpper(value[:1]) + value[1:]
+}
+
+var ordinals = []string{"", "first", "second", "third", "fourth", "fifth"}
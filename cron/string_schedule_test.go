@@ -81,3 +81,100 @@ func TestMapKeysToArray(t *testing.T) {
 	assert.Empty(mapKeysToArray(nil))
 	assert.Empty(mapKeysToArray(map[int]bool{}))
 }
+
+func TestParseStringFixes(t *testing.T) {
+	assert := assert.New(t)
+
+	testCases := []stringScheduleTestCase{
+		{Input: "0 0 9 1-2,15 * *", After: time.Date(2021, 01, 02, 12, 0, 0, 0, time.UTC), Expected: time.Date(2021, 01, 15, 9, 0, 0, 0, time.UTC)},      // a range in a list
+		{Input: "0 0 9 * * MON-WED,FRI", After: time.Date(2021, 01, 07, 0, 0, 0, 0, time.UTC), Expected: time.Date(2021, 01, 8, 9, 0, 0, 0, time.UTC)},   // a range in a list
+		{Input: "10,20 * * * * *", After: time.Date(2021, 01, 01, 12, 0, 30, 0, time.UTC), Expected: time.Date(2021, 01, 01, 12, 1, 10, 0, time.UTC)},    // seconds roll over to the next minute
+		{Input: "0 0 0 * * * 2021", After: time.Date(2021, 06, 01, 12, 0, 0, 0, time.UTC), Expected: time.Date(2021, 06, 02, 0, 0, 0, 0, time.UTC)},      // a year of the schedule
+		{Input: "0 0 0 1 1 * 2020", After: time.Date(2021, 01, 01, 0, 0, 0, 0, time.UTC), Expected: Zero},                                                // no years left
+		{Input: "0 0 0 * * * 2021,2023", After: time.Date(2021, 12, 31, 12, 0, 0, 0, time.UTC), Expected: time.Date(2023, 01, 01, 0, 0, 0, 0, time.UTC)}, // skip a year
+		{Input: "0 0 0 * * * 2021", After: time.Date(2021, 12, 31, 12, 0, 0, 0, time.UTC), Expected: Zero},                                               // past the last year
+		{Input: "@weekly", After: time.Date(2019, 01, 02, 12, 3, 4, 5, time.UTC), Expected: time.Date(2019, 01, 06, 0, 0, 0, 0, time.UTC)},               // weekly shorthand
+	}
+	for _, tc := range testCases {
+		parsed, err := ParseString(tc.Input)
+		assert.Nil(err, tc.Input)
+		next := parsed.Next(tc.After)
+		assert.Equal(tc.Expected, next, fmt.Sprintf("%s\n%v vs. %v", tc.Input, tc.Expected.Format(time.RFC3339), next.Format(time.RFC3339)))
+	}
+}
+
+func TestParseStringDayModifiers(t *testing.T) {
+	assert := assert.New(t)
+
+	testCases := []stringScheduleTestCase{
+		{Input: "0 0 12 L * *", After: time.Date(2021, 02, 10, 0, 0, 0, 0, time.UTC), Expected: time.Date(2021, 02, 28, 12, 0, 0, 0, time.UTC)},      // last day of the month
+		{Input: "0 0 12 L * *", After: time.Date(2021, 02, 28, 12, 0, 0, 0, time.UTC), Expected: time.Date(2021, 03, 31, 12, 0, 0, 0, time.UTC)},     // last day of the next month
+		{Input: "0 0 12 L 2 * 2024", After: time.Date(2021, 02, 10, 0, 0, 0, 0, time.UTC), Expected: time.Date(2024, 02, 29, 12, 0, 0, 0, time.UTC)}, // leap day
+		{Input: "0 0 12 LW * *", After: time.Date(2021, 07, 01, 0, 0, 0, 0, time.UTC), Expected: time.Date(2021, 07, 30, 12, 0, 0, 0, time.UTC)},     // the 31st is a saturday
+		{Input: "0 0 12 15W * *", After: time.Date(2021, 05, 01, 0, 0, 0, 0, time.UTC), Expected: time.Date(2021, 05, 14, 12, 0, 0, 0, time.UTC)},    // the 15th is a saturday
+		{Input: "0 0 12 1W * *", After: time.Date(2021, 04, 15, 0, 0, 0, 0, time.UTC), Expected: time.Date(2021, 05, 03, 12, 0, 0, 0, time.UTC)},     // the 1st is a saturday, stay in the month
+		{Input: "0 0 12 ? * 5L", After: time.Date(2021, 01, 01, 0, 0, 0, 0, time.UTC), Expected: time.Date(2021, 01, 29, 12, 0, 0, 0, time.UTC)},     // last friday
+		{Input: "0 0 12 ? * 1#2", After: time.Date(2021, 01, 01, 0, 0, 0, 0, time.UTC), Expected: time.Date(2021, 01, 11, 12, 0, 0, 0, time.UTC)},    // second monday
+		{Input: "0 0 12 ? * MON#1", After: time.Date(2021, 01, 04, 12, 0, 0, 0, time.UTC), Expected: time.Date(2021, 02, 01, 12, 0, 0, 0, time.UTC)}, // first monday, after it ran
+		{Input: "0 0 12 32W * *", ExpectedErr: ErrStringScheduleInvalid},
+		{Input: "0 0 12 XW * *", ExpectedErr: ErrStringScheduleInvalid},
+		{Input: "0 0 9 1-7 * MON", After: time.Date(2021, 01, 05, 0, 0, 0, 0, time.UTC), Expected: time.Date(2021, 02, 01, 9, 0, 0, 0, time.UTC)}, // both day fields, the first monday in the month
+		{Input: "0 0 0 29 2 *", After: time.Date(2021, 01, 01, 0, 0, 0, 0, time.UTC), Expected: time.Date(2024, 02, 29, 0, 0, 0, 0, time.UTC)},    // only in leap years
+		{Input: "0 0 0 31 * *", After: time.Date(2021, 04, 01, 0, 0, 0, 0, time.UTC), Expected: time.Date(2021, 05, 31, 0, 0, 0, 0, time.UTC)},    // skip months without the day
+		{Input: "0 0 12 * * 1#6", ExpectedErr: ErrStringScheduleInvalid},
+		{Input: "0 0 12 * * 1#", ExpectedErr: ErrStringScheduleInvalid},
+		{Input: "0 0 12 * * 8L", ExpectedErr: ErrStringScheduleInvalid},
+		{Input: "0 0 12 * * L", ExpectedErr: ErrStringScheduleInvalid},
+	}
+
+	for _, tc := range testCases {
+		parsed, err := ParseString(tc.Input)
+		if tc.ExpectedErr != nil {
+			assert.NotNil(err, tc.Input)
+			assert.True(ex.Is(err, tc.ExpectedErr), tc.Input)
+		} else {
+			assert.Nil(err, tc.Input)
+			next := parsed.Next(tc.After)
+			assert.Equal(tc.Expected, next, fmt.Sprintf("%s\n%v vs. %v", tc.Input, tc.Expected.Format(time.RFC3339), next.Format(time.RFC3339)))
+		}
+	}
+
+	parsed, err := ParseString("0 0 12 1,L,LW,15W * 1,5L,1#2")
+	assert.Nil(err)
+	assert.Equal("0 0 12 1,L,LW,15W * 1,5L,1#2 *", parsed.(*StringSchedule).FullString())
+}
+
+func TestStringScheduleDescription(t *testing.T) {
+	assert := assert.New(t)
+
+	testCases := [][2]string{
+		{"* * * * * *", "Every second"},
+		{"*/10 * * * * *", "Every 10 seconds"},
+		{"0 * * * * *", "Every minute"},
+		{"0 */15 * * * *", "Every 15 minutes"},
+		{"0 0 * * * *", "Every hour"},
+		{"0 30 * * * *", "At minute 30 past the hour"},
+		{"0 0 */6 * * *", "Every 6 hours"},
+		{"0 */5 9-17 * * *", "Every 5 minutes, during hours 9 through 17"},
+		{"0 0 9 * * MON-FRI", "At 09:00, on Monday through Friday"},
+		{"30 15 9,17 * * *", "At 09:15:30 and 17:15:30"},
+		{"0 0 0 1,15 * *", "At 00:00, on days 1 and 15 of the month"},
+		{"0 0 12 L * *", "At 12:00, on the last day of the month"},
+		{"0 0 12 LW * *", "At 12:00, on the last weekday of the month"},
+		{"0 0 12 15W * *", "At 12:00, on the weekday nearest day 15 of the month"},
+		{"0 0 12 ? * 5L", "At 12:00, on the last Friday of the month"},
+		{"0 0 12 ? * 1#2", "At 12:00, on the second Monday of the month"},
+		{"0 0 0 1 JAN,JUL * 2030", "At 00:00, on day 1 of the month, in January and July, in 2030"},
+		{"@weekly", "At 00:00, on Sunday"},
+		{"CRON_TZ=UTC 0 0 9 * * *", "At 09:00 (UTC)"},
+		{"@every 90m", "Every 1h30m0s"},
+	}
+	for _, tc := range testCases {
+		description, err := DescribeString(tc[0])
+		assert.Nil(err, tc[0])
+		assert.Equal(tc[1], description, tc[0])
+	}
+
+	_, err := DescribeString("0 0 12 * * 1#6")
+	assert.True(ex.Is(err, ErrStringScheduleInvalid))
+}
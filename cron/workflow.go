@@ -0,0 +1,536 @@
+package cron
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/blend/go-sdk/ex"
+	"github.com/blend/go-sdk/logger"
+)
+
+// Interface assertions.
+var (
+	_ Job               = (*Workflow)(nil)
+	_ ScheduleProvider  = (*Workflow)(nil)
+	_ ConfigProvider    = (*Workflow)(nil)
+	_ LifecycleProvider = (*Workflow)(nil)
+)
+
+// NewWorkflow returns a new workflow.
+func NewWorkflow(name string, options ...WorkflowOption) *Workflow {
+	wf := Workflow{
+		WorkflowName: name,
+	}
+	for _, option := range options {
+		option(&wf)
+	}
+	return &wf
+}
+
+// WorkflowOption is a workflow option.
+type WorkflowOption func(*Workflow)
+
+// OptWorkflowStep adds a step to the workflow that runs a job once the steps it depends on have succeeded.
+// Steps are named by their job's name.
+func OptWorkflowStep(job Job, dependsOn ...string) WorkflowOption {
+	return func(wf *Workflow) { wf.Steps = append(wf.Steps, WorkflowStep{Job: job, DependsOn: dependsOn}) }
+}
+
+// OptWorkflowSchedule sets the workflow schedule.
+func OptWorkflowSchedule(schedule Schedule) WorkflowOption {
+	return func(wf *Workflow) { wf.WorkflowSchedule = schedule }
+}
+
+// OptWorkflowConfig sets the workflow config.
+func OptWorkflowConfig(config JobConfig) WorkflowOption {
+	return func(wf *Workflow) { wf.WorkflowConfig = config }
+}
+
+// OptWorkflowLifecycle sets the workflow lifecycle hooks.
+func OptWorkflowLifecycle(lifecycle JobLifecycle) WorkflowOption {
+	return func(wf *Workflow) { wf.WorkflowLifecycle = lifecycle }
+}
+
+/*
+Workflow is a job that runs other jobs (steps) as a directed acyclic graph.
+
+Each step runs once all of the steps it depends on have succeeded, and steps that don't depend on each other
+run in parallel. If a step fails, the steps that depend on it (directly or not) are skipped, the steps that
+don't are still run, and the workflow returns an error with class `ErrWorkflowFailed`.
+
+A workflow is loaded into a job manager and scheduled like any other job, and each step is recorded as a child
+invocation in `JobInvocation.Children` of the workflow's invocation.
+
+Parameters are passed between steps with `JobParameters`; each step is given a copy of the workflow's parameters
+merged with the parameters of the steps it depends on (in the order they're listed), and any values a step sets
+in the parameters it gets from `GetJobParameterValues(ctx)` are passed on to the steps that depend on it.
+
+A step's job can set a `JobConfig` (by implementing `ConfigProvider`) with a timeout and a retry policy for the step;
+each attempt of the step is recorded in its child invocation. Other config fields, and the step's lifecycle hooks, are not used.
+
+A failed invocation can be partially re-run, reusing the steps that succeeded, with `WithWorkflowRerun`.
+*/
+type Workflow struct {
+	WorkflowName      string
+	WorkflowSchedule  Schedule
+	WorkflowConfig    JobConfig
+	WorkflowLifecycle JobLifecycle
+	Steps             []WorkflowStep
+}
+
+// WorkflowStep is a step in a workflow.
+type WorkflowStep struct {
+	Job       Job
+	DependsOn []string
+}
+
+// Name returns the step name.
+func (ws WorkflowStep) Name() string {
+	return ws.Job.Name()
+}
+
+// Config returns the step's job config, if its job provides one.
+func (ws WorkflowStep) Config() JobConfig {
+	if typed, ok := ws.Job.(ConfigProvider); ok {
+		return typed.Config()
+	}
+	return JobConfig{}
+}
+
+// Name returns the workflow name.
+func (wf *Workflow) Name() string {
+	return wf.WorkflowName
+}
+
+// Schedule returns the workflow schedule.
+func (wf *Workflow) Schedule() Schedule {
+	return wf.WorkflowSchedule
+}
+
+// Config returns the workflow config.
+func (wf *Workflow) Config() JobConfig {
+	return wf.WorkflowConfig
+}
+
+// Lifecycle returns the workflow lifecycle hooks.
+// The workflow is validated when it is loaded into a job manager.
+func (wf *Workflow) Lifecycle() JobLifecycle {
+	lifecycle := wf.WorkflowLifecycle
+	onLoad := lifecycle.OnLoad
+	lifecycle.OnLoad = func(ctx context.Context) error {
+		if err := wf.Validate(); err != nil {
+			return err
+		}
+		if onLoad != nil {
+			return onLoad(ctx)
+		}
+		return nil
+	}
+	return lifecycle
+}
+
+// Validate returns an error if steps have the same name, depend on steps that don't exist, or depend on each other in a cycle.
+func (wf *Workflow) Validate() error {
+	steps := make(map[string]WorkflowStep)
+	for _, step := range wf.Steps {
+		if _, ok := steps[step.Name()]; ok {
+			return ex.New(ErrWorkflowInvalid, ex.OptMessagef("workflow: %s; duplicate step: %s", wf.Name(), step.Name()))
+		}
+		steps[step.Name()] = step
+	}
+	for _, step := range wf.Steps {
+		for _, dependency := range step.DependsOn {
+			if _, ok := steps[dependency]; !ok {
+				return ex.New(ErrWorkflowInvalid, ex.OptMessagef("workflow: %s; step: %s; unknown dependency: %s", wf.Name(), step.Name(), dependency))
+			}
+		}
+	}
+
+	// visit the steps depth first; a step that is visited again before its dependencies are done is in a cycle.
+	const (
+		visiting = 1
+		visited  = 2
+	)
+	state := make(map[string]int)
+	var visit func(string) error
+	visit = func(name string) error {
+		switch state[name] {
+		case visiting:
+			return ex.New(ErrWorkflowInvalid, ex.OptMessagef("workflow: %s; dependency cycle at step: %s", wf.Name(), name))
+		case visited:
+			return nil
+		}
+		state[name] = visiting
+		for _, dependency := range steps[name].DependsOn {
+			if err := visit(dependency); err != nil {
+				return err
+			}
+		}
+		state[name] = visited
+		return nil
+	}
+	for _, step := range wf.Steps {
+		if err := visit(step.Name()); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Execute runs the workflow steps.
+func (wf *Workflow) Execute(ctx context.Context) error {
+	if err := wf.Validate(); err != nil {
+		return err
+	}
+	return newWorkflowRun(ctx, wf).execute()
+}
+
+type contextKeyWorkflowRerun struct{}
+
+type workflowRerun struct {
+	Previous *JobInvocation
+	Steps    []string
+}
+
+// WithWorkflowRerun returns a context that partially re-runs a workflow invocation.
+//
+// The steps that succeeded in the previous invocation are reused (along with the parameters they passed on)
+// instead of being run again, except for any given steps and the steps that depend on them, which are always run.
+// The previous invocation's parameters are not reused; they can be passed with `WithJobParameterValues`.
+func WithWorkflowRerun(ctx context.Context, previous *JobInvocation, steps ...string) context.Context {
+	return context.WithValue(ctx, contextKeyWorkflowRerun{}, workflowRerun{Previous: previous, Steps: steps})
+}
+
+func getWorkflowRerun(ctx context.Context) (rerun workflowRerun, ok bool) {
+	rerun, ok = ctx.Value(contextKeyWorkflowRerun{}).(workflowRerun)
+	return
+}
+
+func newWorkflowRun(ctx context.Context, wf *Workflow) *workflowRun {
+	wr := &workflowRun{
+		ctx:        ctx,
+		wf:         wf,
+		parameters: GetJobParameterValues(ctx),
+		reusable:   make(map[string]*JobInvocation),
+		children:   make(map[string]*JobInvocation),
+		outputs:    make(map[string]JobParameters),
+	}
+	if rerun, ok := getWorkflowRerun(ctx); ok && rerun.Previous != nil {
+		forced := wf.dependents(rerun.Steps)
+		for _, child := range rerun.Previous.Children {
+			if child.Status == JobInvocationStatusSuccess && !forced[child.JobName] {
+				wr.reusable[child.JobName] = child
+			}
+		}
+	}
+	return wr
+}
+
+// dependents returns the given steps and all the steps that depend on them.
+func (wf *Workflow) dependents(steps []string) map[string]bool {
+	output := make(map[string]bool)
+	for _, step := range steps {
+		output[step] = true
+	}
+	for changed := true; changed; {
+		changed = false
+		for _, step := range wf.Steps {
+			if output[step.Name()] {
+				continue
+			}
+			for _, dependency := range step.DependsOn {
+				if output[dependency] {
+					output[step.Name()] = true
+					changed = true
+					break
+				}
+			}
+		}
+	}
+	return output
+}
+
+// workflowRun is the state of a workflow invocation.
+type workflowRun struct {
+	ctx        context.Context
+	wf         *Workflow
+	parameters JobParameters
+	reusable   map[string]*JobInvocation
+
+	mu       sync.Mutex
+	children map[string]*JobInvocation
+	outputs  map[string]JobParameters
+	finished bool
+}
+
+type workflowStepResult struct {
+	Step       string
+	Parameters JobParameters
+	Err        error
+}
+
+func (wr *workflowRun) execute() error {
+	results := make(chan workflowStepResult, len(wr.wf.Steps))
+	pending := append([]WorkflowStep(nil), wr.wf.Steps...)
+	var running int
+	for {
+		// start or skip the pending steps whose dependencies are complete; reusing or skipping
+		// a step completes it, so keep going until no more steps are complete.
+		for changed := true; changed; {
+			changed = false
+			var remaining []WorkflowStep
+			for _, step := range pending {
+				switch ready, failed := wr.dependenciesComplete(step); {
+				case failed:
+					wr.skip(step)
+					changed = true
+				case !ready:
+					remaining = append(remaining, step)
+				case wr.reuse(step):
+					changed = true
+				default:
+					running++
+					go wr.run(step, results)
+				}
+			}
+			pending = remaining
+		}
+		if running == 0 {
+			break
+		}
+
+		select {
+		case result := <-results:
+			running--
+			wr.complete(result)
+		case <-wr.ctx.Done():
+			wr.cancel()
+			return ex.New(ErrJobCancelled)
+		}
+	}
+
+	wr.mu.Lock()
+	defer wr.mu.Unlock()
+	wr.finished = true
+
+	var failed []string
+	var firstErr error
+	for _, step := range wr.wf.Steps {
+		if child := wr.children[step.Name()]; child.Status == JobInvocationStatusErrored {
+			failed = append(failed, step.Name())
+			if firstErr == nil {
+				firstErr = child.Err
+			}
+		}
+	}
+	if len(failed) > 0 {
+		return ex.New(ErrWorkflowFailed, ex.OptMessagef("workflow: %s; failed steps: %s", wr.wf.Name(), strings.Join(failed, ", ")), ex.OptInner(firstErr))
+	}
+	return nil
+}
+
+// dependenciesComplete returns if all the dependencies of a step have succeeded, or if any have not.
+func (wr *workflowRun) dependenciesComplete(step WorkflowStep) (ready, failed bool) {
+	wr.mu.Lock()
+	defer wr.mu.Unlock()
+	ready = true
+	for _, dependency := range step.DependsOn {
+		child, ok := wr.children[dependency]
+		if !ok || child.Status == JobInvocationStatusRunning {
+			ready = false
+			continue
+		}
+		if child.Status != JobInvocationStatusSuccess {
+			return false, true
+		}
+	}
+	return
+}
+
+// inputs returns the parameters for a step from the workflow and the steps it depends on.
+func (wr *workflowRun) inputs(step WorkflowStep) JobParameters {
+	wr.mu.Lock()
+	defer wr.mu.Unlock()
+	values := []JobParameters{wr.parameters}
+	for _, dependency := range step.DependsOn {
+		values = append(values, wr.outputs[dependency])
+	}
+	return MergeJobParameterValues(values...)
+}
+
+func (wr *workflowRun) run(step WorkflowStep, results chan<- workflowStepResult) {
+	parameters := wr.inputs(step)
+	child := &JobInvocation{
+		ID:         NewJobInvocationID(),
+		JobName:    step.Name(),
+		Started:    time.Now().UTC(),
+		Status:     JobInvocationStatusRunning,
+		Parameters: MergeJobParameterValues(parameters),
+	}
+	wr.mu.Lock()
+	wr.children[step.Name()] = child
+	wr.publish()
+	wr.mu.Unlock()
+
+	// the step's invocation is guarded by the workflow run's lock, e.g. if the step is a workflow itself.
+	ctx := WithJobInvocation(wr.ctx, child)
+	ctx = withJobInvocationLock(ctx, &wr.mu)
+	ctx = logger.WithPath(ctx, append(logger.GetPath(ctx), step.Name())...)
+
+	var cancel context.CancelFunc
+	if timeout := step.Config().TimeoutOrDefault(); timeout > 0 {
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+	} else {
+		ctx, cancel = context.WithCancel(ctx)
+	}
+	defer cancel()
+
+	result := workflowStepResult{Step: step.Name(), Parameters: parameters}
+	defer func() {
+		if r := recover(); r != nil {
+			result.Err = ex.New(r)
+		}
+		results <- result
+	}()
+	if outputs, err := wr.executeStep(ctx, step, child, parameters); err != nil {
+		result.Err = err
+	} else {
+		result.Parameters = outputs
+	}
+}
+
+// executeStep runs attempts of a step until one succeeds, times out or is cancelled, or
+// the step's retry policy says it shouldn't be retried, returning the error of the last attempt.
+//
+// Each attempt is given its own copy of the parameters, and the parameters of an attempt that succeeds are returned;
+// the parameters of an attempt that times out aren't, as its job may still be running and writing to them.
+func (wr *workflowRun) executeStep(ctx context.Context, step WorkflowStep, child *JobInvocation, parameters JobParameters) (JobParameters, error) {
+	policy := step.Config().RetryPolicy
+	for attempt := 1; ; attempt++ {
+		wr.mu.Lock()
+		child.Attempts = append(child.Attempts, JobInvocationAttempt{Started: time.Now().UTC()})
+		wr.mu.Unlock()
+
+		attemptParameters := MergeJobParameterValues(parameters)
+		var err error
+		select {
+		case <-ctx.Done():
+			err = ErrJobCancelled
+		case err = <-wr.safeBackgroundExec(WithJobParameterValues(ctx, attemptParameters), step):
+		}
+
+		wr.mu.Lock()
+		child.Attempts[len(child.Attempts)-1].Complete = time.Now().UTC()
+		child.Attempts[len(child.Attempts)-1].Err = err
+		wr.mu.Unlock()
+
+		if err == nil {
+			return attemptParameters, nil
+		}
+		if ctx.Err() != nil || !policy.ShouldRetry(attempt, err) {
+			return nil, err
+		}
+
+		delay := time.NewTimer(policy.Delay(attempt))
+		select {
+		case <-ctx.Done():
+			delay.Stop()
+			return nil, ErrJobCancelled
+		case <-delay.C:
+		}
+	}
+}
+
+func (wr *workflowRun) safeBackgroundExec(ctx context.Context, step WorkflowStep) chan error {
+	errors := make(chan error, 2)
+	go func() {
+		defer func() {
+			if r := recover(); r != nil {
+				errors <- ex.New(r)
+			}
+		}()
+		errors <- step.Job.Execute(ctx)
+	}()
+	return errors
+}
+
+func (wr *workflowRun) complete(result workflowStepResult) {
+	wr.mu.Lock()
+	defer wr.mu.Unlock()
+
+	child := wr.children[result.Step]
+	child.Complete = time.Now().UTC()
+	// the step's parameters are shared with the steps that depend on it, so the invocation gets its own copy.
+	child.Parameters = MergeJobParameterValues(result.Parameters)
+	if result.Err != nil {
+		child.Status = JobInvocationStatusErrored
+		child.Err = result.Err
+	} else {
+		child.Status = JobInvocationStatusSuccess
+		wr.outputs[result.Step] = result.Parameters
+	}
+	wr.publish()
+}
+
+// reuse completes a step with its result from a previous invocation if it can be reused.
+func (wr *workflowRun) reuse(step WorkflowStep) bool {
+	previous, ok := wr.reusable[step.Name()]
+	if !ok {
+		return false
+	}
+	wr.mu.Lock()
+	defer wr.mu.Unlock()
+	wr.children[step.Name()] = previous.Clone()
+	wr.outputs[step.Name()] = previous.Parameters
+	wr.publish()
+	return true
+}
+
+func (wr *workflowRun) skip(step WorkflowStep) {
+	wr.mu.Lock()
+	defer wr.mu.Unlock()
+	wr.children[step.Name()] = &JobInvocation{
+		ID:      NewJobInvocationID(),
+		JobName: step.Name(),
+		Status:  JobInvocationStatusSkipped,
+	}
+	wr.publish()
+}
+
+// cancel marks the running steps as cancelled, and stops updating the workflow invocation.
+func (wr *workflowRun) cancel() {
+	wr.mu.Lock()
+	defer wr.mu.Unlock()
+	now := time.Now().UTC()
+	for _, child := range wr.children {
+		if child.Status == JobInvocationStatusRunning {
+			child.Status = JobInvocationStatusCancelled
+			child.Err = ex.New(ErrJobCancelled)
+			child.Complete = now
+		}
+	}
+	wr.publish()
+	wr.finished = true
+}
+
+// publish sets the children of the workflow invocation to copies of the steps' invocations, in step order.
+// It must be called with the lock held.
+func (wr *workflowRun) publish() {
+	if wr.finished {
+		return
+	}
+	var children []*JobInvocation
+	for _, step := range wr.wf.Steps {
+		if child, ok := wr.children[step.Name()]; ok {
+			children = append(children, child.Clone())
+		}
+	}
+	updateJobInvocation(wr.ctx, func(ji *JobInvocation) {
+		// the workflow invocation may have already been completed, e.g. if it was cancelled.
+		if ji.Status == JobInvocationStatusRunning {
+			ji.Children = children
+		}
+	})
+}
@@ -0,0 +1,319 @@
+package cron
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/blend/go-sdk/assert"
+	"github.com/blend/go-sdk/ex"
+)
+
+type workflowTestSteps struct {
+	sync.Mutex
+	runs map[string]int
+}
+
+func (wts *workflowTestSteps) step(name string, action func(JobParameters) error) Job {
+	return NewJob(OptJobName(name), OptJobAction(func(ctx context.Context) error {
+		wts.Lock()
+		wts.runs[name]++
+		wts.Unlock()
+		if action != nil {
+			return action(GetJobParameterValues(ctx))
+		}
+		return nil
+	}))
+}
+
+func (wts *workflowTestSteps) count(name string) int {
+	wts.Lock()
+	defer wts.Unlock()
+	return wts.runs[name]
+}
+
+func newWorkflowTestSteps() *workflowTestSteps {
+	return &workflowTestSteps{runs: make(map[string]int)}
+}
+
+func TestWorkflowValidate(t *testing.T) {
+	assert := assert.New(t)
+
+	steps := newWorkflowTestSteps()
+	valid := NewWorkflow("valid",
+		OptWorkflowStep(steps.step("a", nil)),
+		OptWorkflowStep(steps.step("b", nil), "a"),
+		OptWorkflowStep(steps.step("c", nil), "a"),
+		OptWorkflowStep(steps.step("d", nil), "b", "c"),
+	)
+	assert.Nil(valid.Validate())
+
+	duplicate := NewWorkflow("duplicate",
+		OptWorkflowStep(steps.step("a", nil)),
+		OptWorkflowStep(steps.step("a", nil)),
+	)
+	assert.True(ex.Is(duplicate.Validate(), ErrWorkflowInvalid))
+
+	unknown := NewWorkflow("unknown",
+		OptWorkflowStep(steps.step("a", nil), "b"),
+	)
+	assert.True(ex.Is(unknown.Validate(), ErrWorkflowInvalid))
+
+	cycle := NewWorkflow("cycle",
+		OptWorkflowStep(steps.step("a", nil), "c"),
+		OptWorkflowStep(steps.step("b", nil), "a"),
+		OptWorkflowStep(steps.step("c", nil), "b"),
+	)
+	assert.True(ex.Is(cycle.Validate(), ErrWorkflowInvalid))
+	assert.True(ex.Is(cycle.Execute(context.Background()), ErrWorkflowInvalid))
+
+	jm := New()
+	assert.True(ex.Is(jm.LoadJobs(cycle), ErrWorkflowInvalid))
+	assert.False(jm.HasJob("cycle"))
+}
+
+func TestWorkflowParameters(t *testing.T) {
+	assert := assert.New(t)
+
+	steps := newWorkflowTestSteps()
+	var inputs JobParameters
+	wf := NewWorkflow("parameters",
+		OptWorkflowStep(steps.step("extract", func(p JobParameters) error {
+			p["rows"] = p["source"] + "-rows"
+			return nil
+		})),
+		OptWorkflowStep(steps.step("left", func(p JobParameters) error {
+			p["left"] = p["rows"] + "-left"
+			return nil
+		}), "extract"),
+		OptWorkflowStep(steps.step("right", func(p JobParameters) error {
+			p["right"] = p["rows"] + "-right"
+			return nil
+		}), "extract"),
+		OptWorkflowStep(steps.step("load", func(p JobParameters) error {
+			inputs = p
+			return nil
+		}), "left", "right"),
+	)
+
+	jm := New()
+	assert.Nil(jm.LoadJobs(wf))
+	ji, done, err := jm.RunJobContext(WithJobParameterValues(context.Background(), JobParameters{"source": "db"}), "parameters")
+	assert.Nil(err)
+	<-done
+
+	js, err := jm.Job("parameters")
+	assert.Nil(err)
+	last := js.Last()
+	assert.Equal(ji.ID, last.ID)
+	assert.Equal(JobInvocationStatusSuccess, last.Status)
+	assert.Equal("db", inputs["source"])
+	assert.Equal("db-rows", inputs["rows"])
+	assert.Equal("db-rows-left", inputs["left"])
+	assert.Equal("db-rows-right", inputs["right"])
+
+	assert.Len(last.Children, 4)
+	for index, name := range []string{"extract", "left", "right", "load"} {
+		assert.Equal(name, last.Children[index].JobName)
+		assert.Equal(JobInvocationStatusSuccess, last.Children[index].Status)
+		assert.False(last.Children[index].Complete.Before(last.Children[index].Started))
+	}
+	assert.Equal("db-rows", last.Children[0].Parameters["rows"])
+	assert.Empty(last.Children[0].Parameters["left"])
+}
+
+func TestWorkflowFailure(t *testing.T) {
+	assert := assert.New(t)
+
+	steps := newWorkflowTestSteps()
+	wf := NewWorkflow("failure",
+		OptWorkflowStep(steps.step("a", nil)),
+		OptWorkflowStep(steps.step("b", func(_ JobParameters) error { return fmt.Errorf("b failed") }), "a"),
+		OptWorkflowStep(steps.step("c", nil), "b"),
+		OptWorkflowStep(steps.step("d", nil), "c"),
+		OptWorkflowStep(steps.step("e", nil), "a"),
+		OptWorkflowStep(steps.step("f", func(_ JobParameters) error { panic("f panicked") })),
+	)
+	js := NewJobScheduler(wf)
+	js.Run()
+
+	last := js.Last()
+	assert.Equal(JobInvocationStatusErrored, last.Status)
+	assert.True(ex.Is(last.Err, ErrWorkflowFailed))
+	assert.Equal("b failed", ex.ErrInner(last.Err).Error())
+	assert.Equal(1, steps.count("a"))
+	assert.Equal(1, steps.count("b"))
+	assert.Zero(steps.count("c"))
+	assert.Zero(steps.count("d"))
+	assert.Equal(1, steps.count("e"))
+
+	assert.Len(last.Children, 6)
+	statuses := make(map[string]JobInvocationStatus)
+	for _, child := range last.Children {
+		statuses[child.JobName] = child.Status
+	}
+	assert.Equal(JobInvocationStatusSuccess, statuses["a"])
+	assert.Equal(JobInvocationStatusErrored, statuses["b"])
+	assert.Equal(JobInvocationStatusSkipped, statuses["c"])
+	assert.Equal(JobInvocationStatusSkipped, statuses["d"])
+	assert.Equal(JobInvocationStatusSuccess, statuses["e"])
+	assert.Equal(JobInvocationStatusErrored, statuses["f"])
+}
+
+func TestWorkflowStepConfig(t *testing.T) {
+	assert := assert.New(t)
+
+	var attempts int
+	flaky := NewJob(OptJobName("flaky"),
+		OptJobRetryPolicy(RetryPolicy{MaxAttempts: 3, Backoff: time.Millisecond}),
+		OptJobAction(func(_ context.Context) error {
+			attempts++
+			if attempts < 2 {
+				return fmt.Errorf("flaky failed")
+			}
+			return nil
+		}),
+	)
+	slow := NewJob(OptJobName("slow"),
+		OptJobTimeout(10*time.Millisecond),
+		OptJobAction(func(ctx context.Context) error {
+			<-ctx.Done()
+			return ctx.Err()
+		}),
+	)
+	wf := NewWorkflow("step-config",
+		OptWorkflowStep(flaky),
+		OptWorkflowStep(slow),
+	)
+	js := NewJobScheduler(wf)
+	js.Run()
+
+	last := js.Last()
+	assert.Equal(JobInvocationStatusErrored, last.Status)
+	assert.Equal(2, attempts)
+	assert.Len(last.Children, 2)
+
+	assert.Equal(JobInvocationStatusSuccess, last.Children[0].Status)
+	assert.Len(last.Children[0].Attempts, 2)
+	assert.Equal("flaky failed", last.Children[0].Attempts[0].Err.Error())
+	assert.Nil(last.Children[0].Attempts[1].Err)
+
+	assert.Equal(JobInvocationStatusErrored, last.Children[1].Status)
+	assert.True(IsJobCancelled(last.Children[1].Err))
+	assert.Len(last.Children[1].Attempts, 1)
+}
+
+func TestWorkflowStepTimeoutParameters(t *testing.T) {
+	assert := assert.New(t)
+
+	stop := make(chan struct{})
+	stopped := make(chan struct{})
+	slow := NewJob(OptJobName("slow"),
+		OptJobTimeout(5*time.Millisecond),
+		OptJobAction(func(ctx context.Context) error {
+			// ignore the timeout, and keep writing to the parameters.
+			defer close(stopped)
+			parameters := GetJobParameterValues(ctx)
+			for x := 0; ; x++ {
+				select {
+				case <-stop:
+					return nil
+				default:
+					parameters["count"] = fmt.Sprint(x)
+					time.Sleep(100 * time.Microsecond)
+				}
+			}
+		}),
+	)
+	wf := NewWorkflow("timeout-parameters", OptWorkflowStep(slow))
+	js := NewJobScheduler(wf)
+	js.Run()
+	last := js.Last()
+	close(stop)
+	<-stopped
+
+	assert.Len(last.Children, 1)
+	assert.Equal(JobInvocationStatusErrored, last.Children[0].Status)
+	_, ok := last.Children[0].Parameters["count"]
+	assert.False(ok, "the parameters of a step that timed out should not be kept")
+}
+
+func TestWorkflowRerun(t *testing.T) {
+	assert := assert.New(t)
+
+	steps := newWorkflowTestSteps()
+	var fail bool
+	var loaded string
+	wf := NewWorkflow("rerun",
+		OptWorkflowStep(steps.step("extract", func(p JobParameters) error {
+			p["rows"] = fmt.Sprintf("rows-%d", steps.count("extract"))
+			return nil
+		})),
+		OptWorkflowStep(steps.step("transform", func(p JobParameters) error {
+			if fail {
+				return fmt.Errorf("transform failed")
+			}
+			return nil
+		}), "extract"),
+		OptWorkflowStep(steps.step("load", func(p JobParameters) error {
+			loaded = p["rows"]
+			return nil
+		}), "transform"),
+	)
+	js := NewJobScheduler(wf)
+
+	fail = true
+	js.Run()
+	failed := js.Last()
+	assert.Equal(JobInvocationStatusErrored, failed.Status)
+	assert.Equal(1, steps.count("extract"))
+	assert.Zero(steps.count("load"))
+
+	// the failed step and its dependents are run, and the step that succeeded is reused.
+	fail = false
+	js.RunContext(WithWorkflowRerun(context.Background(), failed))
+	rerun := js.Last()
+	assert.Equal(JobInvocationStatusSuccess, rerun.Status)
+	assert.Equal(1, steps.count("extract"))
+	assert.Equal(2, steps.count("transform"))
+	assert.Equal(1, steps.count("load"))
+	assert.Equal("rows-1", loaded)
+	assert.Equal(failed.Children[0].ID, rerun.Children[0].ID)
+
+	// steps can be forced to run again with their dependents.
+	js.RunContext(WithWorkflowRerun(context.Background(), rerun, "extract"))
+	assert.Equal(JobInvocationStatusSuccess, js.Last().Status)
+	assert.Equal(2, steps.count("extract"))
+	assert.Equal(3, steps.count("transform"))
+	assert.Equal(2, steps.count("load"))
+	assert.Equal("rows-2", loaded)
+}
+
+func TestWorkflowCancel(t *testing.T) {
+	assert := assert.New(t)
+
+	steps := newWorkflowTestSteps()
+	blocking, started := newBlockingJob("blocking")
+	wf := NewWorkflow("cancel",
+		OptWorkflowStep(steps.step("a", nil)),
+		OptWorkflowStep(blocking, "a"),
+		OptWorkflowStep(steps.step("c", nil), "blocking"),
+	)
+	js := NewJobScheduler(wf)
+	ji, done, err := js.RunAsync()
+	assert.Nil(err)
+	<-started
+
+	ji.Cancel()
+	<-done
+	last := js.Last()
+	assert.Equal(JobInvocationStatusCancelled, last.Status)
+	assert.Zero(steps.count("c"))
+	assert.Len(last.Children, 2)
+	assert.Equal(JobInvocationStatusSuccess, last.Children[0].Status)
+	assert.Equal("blocking", last.Children[1].JobName)
+	assert.Equal(JobInvocationStatusCancelled, last.Children[1].Status)
+	assert.True(ex.Is(last.Children[1].Err, ErrJobCancelled))
+}
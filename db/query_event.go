@@ -22,6 +22,7 @@ var (
 	_ logger.Event        = (*QueryEvent)(nil)
 	_ logger.TextWritable = (*QueryEvent)(nil)
 	_ logger.JSONWritable = (*QueryEvent)(nil)
+	_ logger.Redactable   = (*QueryEvent)(nil)
 )
 
 // NewQueryEvent creates a new query event.
@@ -142,3 +143,11 @@ func (e QueryEvent) Decompose() map[string]interface{} {
 		"elapsed":  timeutil.Milliseconds(e.Elapsed),
 	}
 }
+
+// Redact implements logger.Redactable.
+// It redacts values in the query body and the error message.
+func (e QueryEvent) Redact(r *logger.Redactor) logger.Event {
+	e.Body = r.String(e.Body)
+	e.Err = r.Error(e.Err)
+	return e
+}
@@ -58,3 +58,20 @@ func TestQueryEventListener(t *testing.T) {
 	ml(context.Background(), qe)
 	assert.True(didCall)
 }
+
+func TestQueryEventRedact(t *testing.T) {
+	assert := assert.New(t)
+
+	redactor, err := logger.NewRedactor(logger.RedactionConfig{
+		Patterns: []string{`password = '[^']*'`},
+	})
+	assert.Nil(err)
+
+	qe := NewQueryEvent("UPDATE users SET password = 'hunter2' WHERE id = 1", time.Millisecond,
+		OptQueryErr(fmt.Errorf("failed: password = 'hunter2'")),
+	)
+	redacted, ok := qe.Redact(redactor).(QueryEvent)
+	assert.True(ok)
+	assert.Equal("UPDATE users SET [REDACTED] WHERE id = 1", redacted.Body)
+	assert.Equal("failed: [REDACTED]", redacted.Err.Error())
+}
@@ -0,0 +1,34 @@
+package logger
+
+import (
+	"context"
+	"io"
+)
+
+// Capture is an extra output that events triggered with a context are written to,
+// e.g. to keep the events of a single unit of work apart from the rest of the log.
+type Capture struct {
+	Output    io.Writer
+	Formatter WriteFormatter
+}
+
+type captureKey struct{}
+
+// WithCapture returns a new context that events triggered with it (or with a context derived from it)
+// are also written to a given output with a given formatter.
+//
+// Events are captured if they would be written to the logger output, after the logger's sampler and redactor are applied,
+// but regardless of `WithSkipWrite`. A capture replaces any capture already on the context.
+func WithCapture(ctx context.Context, output io.Writer, formatter WriteFormatter) context.Context {
+	return context.WithValue(ctx, captureKey{}, &Capture{Output: output, Formatter: formatter})
+}
+
+// GetCapture gets a capture off a context.
+func GetCapture(ctx context.Context) *Capture {
+	if raw := ctx.Value(captureKey{}); raw != nil {
+		if typed, ok := raw.(*Capture); ok {
+			return typed
+		}
+	}
+	return nil
+}
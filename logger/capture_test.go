@@ -0,0 +1,33 @@
+package logger
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/blend/go-sdk/assert"
+)
+
+func TestCapture(t *testing.T) {
+	assert := assert.New(t)
+
+	output := new(bytes.Buffer)
+	log := MustNew(OptAll(), OptOutput(output), OptText(OptTextNoColor(), OptTextHideTimestamp()))
+	defer log.Close()
+
+	captured := new(bytes.Buffer)
+	ctx := WithCapture(context.Background(), captured, NewTextOutputFormatter(OptTextNoColor(), OptTextHideTimestamp()))
+	assert.NotNil(GetCapture(ctx))
+	assert.Nil(GetCapture(context.Background()))
+
+	log.WithContext(ctx).Infof("captured")
+	log.WithContext(WithSkipWrite(ctx)).Infof("captured and skipped")
+	log.Infof("not captured")
+	log.Flags.Disable(Debug)
+	log.WithContext(ctx).Debugf("disabled")
+
+	assert.Equal("[info] captured\n[info] captured and skipped\n", captured.String())
+	assert.True(strings.Contains(output.String(), "not captured"))
+	assert.False(strings.Contains(output.String(), "captured and skipped"))
+}
@@ -2,7 +2,9 @@ package logger
 
 import (
 	"context"
+	"io"
 	"strings"
+	"time"
 
 	"github.com/blend/go-sdk/configutil"
 )
@@ -13,6 +15,14 @@ type Config struct {
 	Format string     `json:"format,omitempty" yaml:"format,omitempty" env:"LOG_FORMAT"`
 	Text   TextConfig `json:"text,omitempty" yaml:"text,omitempty"`
 	JSON   JSONConfig `json:"json,omitempty" yaml:"json,omitempty"`
+	File   FileConfig `json:"file,omitempty" yaml:"file,omitempty"`
+
+	Sampling  SamplingConfig  `json:"sampling,omitempty" yaml:"sampling,omitempty"`
+	Redaction RedactionConfig `json:"redaction,omitempty" yaml:"redaction,omitempty"`
+
+	RecentEvents RecentEventsConfig `json:"recentEvents,omitempty" yaml:"recentEvents,omitempty"`
+
+	Overrides []FlagOverrideConfig `json:"overrides,omitempty" yaml:"overrides,omitempty"`
 }
 
 // Resolve resolves the config.
@@ -45,11 +55,23 @@ func (c Config) Formatter() WriteFormatter {
 		return NewJSONOutputFormatter(OptJSONConfig(c.JSON))
 	case FormatText:
 		return NewTextOutputFormatter(OptTextConfig(c.Text))
+	case FormatLogfmt:
+		return NewLogfmtOutputFormatter(OptLogfmtConfig(c.Text))
 	default:
 		return NewTextOutputFormatter(OptTextConfig(c.Text))
 	}
 }
 
+// Output returns the configured output writer.
+// It returns nil if no file path is configured, in which case the logger default output should be used.
+// Loggers configured with `OptConfig` close the output when they are closed.
+func (c Config) Output() (io.Writer, error) {
+	if c.File.Path == "" {
+		return nil, nil
+	}
+	return NewRotatingFileWriter(c.File.Path, OptRotatingFileConfig(c.File))
+}
+
 // TextConfig is the config for a text formatter.
 type TextConfig struct {
 	HideTimestamp bool   `json:"hideTimestamp,omitempty" yaml:"hideTimestamp,omitempty" env:"LOG_HIDE_TIMESTAMP"`
@@ -85,3 +107,87 @@ func (jc JSONConfig) PrettyIndentOrDefault() string {
 	}
 	return "  "
 }
+
+// FileConfig is the config for a rotating file output.
+type FileConfig struct {
+	Path           string        `json:"path,omitempty" yaml:"path,omitempty" env:"LOG_FILE_PATH"`
+	MaxSizeBytes   int64         `json:"maxSizeBytes,omitempty" yaml:"maxSizeBytes,omitempty" env:"LOG_FILE_MAX_SIZE_BYTES"`
+	Rotation       string        `json:"rotation,omitempty" yaml:"rotation,omitempty" env:"LOG_FILE_ROTATION"`
+	MaxBackups     int           `json:"maxBackups,omitempty" yaml:"maxBackups,omitempty" env:"LOG_FILE_MAX_BACKUPS"`
+	MaxAge         time.Duration `json:"maxAge,omitempty" yaml:"maxAge,omitempty" env:"LOG_FILE_MAX_AGE"`
+	Compress       bool          `json:"compress,omitempty" yaml:"compress,omitempty" env:"LOG_FILE_COMPRESS"`
+	ReopenOnSignal bool          `json:"reopenOnSignal,omitempty" yaml:"reopenOnSignal,omitempty" env:"LOG_FILE_REOPEN_ON_SIGNAL"`
+}
+
+// SamplingConfig is the config for event sampling.
+type SamplingConfig struct {
+	Rules           []SamplingRule `json:"rules,omitempty" yaml:"rules,omitempty"`
+	SummaryInterval time.Duration  `json:"summaryInterval,omitempty" yaml:"summaryInterval,omitempty" env:"LOG_SAMPLING_SUMMARY_INTERVAL"`
+}
+
+// SummaryIntervalOrDefault returns the summary interval or a default.
+func (sc SamplingConfig) SummaryIntervalOrDefault() time.Duration {
+	if sc.SummaryInterval > 0 {
+		return sc.SummaryInterval
+	}
+	return DefaultSamplingSummaryInterval
+}
+
+// RedactionConfig is the config for redacting sensitive values.
+type RedactionConfig struct {
+	Enabled     bool     `json:"enabled,omitempty" yaml:"enabled,omitempty" env:"LOG_REDACT"`
+	Keys        []string `json:"keys,omitempty" yaml:"keys,omitempty" env:"LOG_REDACT_KEYS,csv"`
+	Globs       []string `json:"globs,omitempty" yaml:"globs,omitempty" env:"LOG_REDACT_GLOBS,csv"`
+	Builtins    []string `json:"builtins,omitempty" yaml:"builtins,omitempty" env:"LOG_REDACT_BUILTINS,csv"`
+	Patterns    []string `json:"patterns,omitempty" yaml:"patterns,omitempty"`
+	Replacement string   `json:"replacement,omitempty" yaml:"replacement,omitempty" env:"LOG_REDACT_REPLACEMENT"`
+}
+
+// IsEnabled returns if redaction is enabled, either explicitly or by setting any keys or patterns.
+func (rc RedactionConfig) IsEnabled() bool {
+	return rc.Enabled || len(rc.Keys) > 0 || len(rc.Globs) > 0 || len(rc.Builtins) > 0 || len(rc.Patterns) > 0
+}
+
+// KeysOrDefault returns the redacted keys or a default.
+func (rc RedactionConfig) KeysOrDefault() []string {
+	if len(rc.Keys) > 0 {
+		return rc.Keys
+	}
+	return DefaultRedactKeys
+}
+
+// ReplacementOrDefault returns the replacement or a default.
+func (rc RedactionConfig) ReplacementOrDefault() string {
+	if rc.Replacement != "" {
+		return rc.Replacement
+	}
+	return DefaultRedactReplacement
+}
+
+// RecentEventsConfig is the config for keeping recent events in memory.
+type RecentEventsConfig struct {
+	// Enabled keeps recent events in memory.
+	Enabled bool `json:"enabled,omitempty" yaml:"enabled,omitempty" env:"LOG_RECENT_EVENTS"`
+	// Size is the number of events kept per flag.
+	Size int `json:"size,omitempty" yaml:"size,omitempty" env:"LOG_RECENT_EVENTS_SIZE"`
+	// Flags are the flags to keep events for, whether or not they are enabled; if unset, events for all flags are kept.
+	Flags []string `json:"flags,omitempty" yaml:"flags,omitempty" env:"LOG_RECENT_EVENTS_FLAGS,csv"`
+}
+
+// SizeOrDefault returns the size or a default.
+func (rc RecentEventsConfig) SizeOrDefault() int {
+	if rc.Size > 0 {
+		return rc.Size
+	}
+	return DefaultRecentEventsSize
+}
+
+// FlagOverrideConfig is the config for a flag override.
+type FlagOverrideConfig struct {
+	// Path is a slash separated scope path prefix, e.g. `cron/my-job`.
+	Path string `json:"path,omitempty" yaml:"path,omitempty"`
+	// Labels are label values events must have.
+	Labels map[string]string `json:"labels,omitempty" yaml:"labels,omitempty"`
+	// Flags are the flags to enable, or disable with a `-` prefix, for matching events.
+	Flags []string `json:"flags,omitempty" yaml:"flags,omitempty"`
+}
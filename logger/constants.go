@@ -13,12 +13,16 @@ const (
 	Info     = "info"
 
 	Audit = "audit"
+
+	// FlagSampling is the flag for sampling summary events.
+	FlagSampling = "sampling"
 )
 
 // Output Formats
 const (
-	FormatJSON = "json"
-	FormatText = "text"
+	FormatJSON   = "json"
+	FormatText   = "text"
+	FormatLogfmt = "logfmt"
 )
 
 // Default flags
@@ -26,6 +30,8 @@ var (
 	DefaultFlags         = []string{Info, Error, Fatal}
 	DefaultListenerName  = "default"
 	DefaultRecoverPanics = true
+	// DefaultShutdownTimeout is the default time drainers are given to drain when the logger is closed.
+	DefaultShutdownTimeout = 10 * time.Second
 )
 
 // Environment Variable Names
@@ -72,6 +78,9 @@ const (
 	FieldElapsed     = "elapsed"
 	FieldLabels      = "labels"
 	FieldAnnotations = "annotations"
+	FieldDropped     = "dropped"
+	FieldTraceID     = "trace_id"
+	FieldSpanID      = "span_id"
 )
 
 // JSON Formatter defaults
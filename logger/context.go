@@ -100,3 +100,25 @@ func GetAnnotations(ctx context.Context) Annotations {
 	}
 	return nil
 }
+
+type traceKey struct{}
+
+type traceIDs struct {
+	TraceID string
+	SpanID  string
+}
+
+// WithTrace returns a new context with a given trace and span id.
+func WithTrace(ctx context.Context, traceID, spanID string) context.Context {
+	return context.WithValue(ctx, traceKey{}, traceIDs{TraceID: traceID, SpanID: spanID})
+}
+
+// GetTrace gets the trace and span id off a context.
+func GetTrace(ctx context.Context) (traceID, spanID string) {
+	if raw := ctx.Value(traceKey{}); raw != nil {
+		if typed, ok := raw.(traceIDs); ok {
+			return typed.TraceID, typed.SpanID
+		}
+	}
+	return "", ""
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"encoding/json"
 	"io"
+	"net/http"
 
 	"github.com/blend/go-sdk/ex"
 )
@@ -13,6 +14,7 @@ var (
 	_ Event        = (*ErrorEvent)(nil)
 	_ TextWritable = (*ErrorEvent)(nil)
 	_ JSONWritable = (*ErrorEvent)(nil)
+	_ Redactable   = (*ErrorEvent)(nil)
 )
 
 // NewErrorEvent returns a new error event.
@@ -82,3 +84,13 @@ func (ee ErrorEvent) Decompose() map[string]interface{} {
 		"err": ee.Err.Error(),
 	}
 }
+
+// Redact implements Redactable.
+// It redacts the error message, and the request headers and url if the state is a request.
+func (ee ErrorEvent) Redact(r *Redactor) Event {
+	ee.Err = r.Error(ee.Err)
+	if req, ok := ee.State.(*http.Request); ok {
+		ee.State = r.Request(req)
+	}
+	return ee
+}
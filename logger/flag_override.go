@@ -0,0 +1,65 @@
+package logger
+
+import (
+	"context"
+	"strings"
+)
+
+// NewFlagOverride returns a new flag override from a config.
+func NewFlagOverride(cfg FlagOverrideConfig) FlagOverride {
+	override := FlagOverride{
+		Flags: NewFlags(cfg.Flags...),
+	}
+	if path := strings.Trim(cfg.Path, "/"); path != "" {
+		override.Path = strings.Split(path, "/")
+	}
+	if len(cfg.Labels) > 0 {
+		override.Labels = Labels(cfg.Labels)
+	}
+	return override
+}
+
+// FlagOverride enables or disables flags for events triggered with a given scope path prefix and / or labels.
+//
+// Flags are parsed the same as logger flags, so `debug` enables the debug flag for matching events,
+// and `-info` disables the info flag for matching events. Flags the override does not mention
+// fall through to the logger flags.
+type FlagOverride struct {
+	Path   []string
+	Labels Labels
+	Flags  *Flags
+}
+
+// Matches returns if the override applies to a given context.
+// The context must have a scope path that starts with the override path, and all of the override labels.
+func (fo FlagOverride) Matches(ctx context.Context) bool {
+	if len(fo.Path) > 0 {
+		path := GetPath(ctx)
+		if len(path) < len(fo.Path) {
+			return false
+		}
+		for index := range fo.Path {
+			if path[index] != fo.Path[index] {
+				return false
+			}
+		}
+	}
+	if len(fo.Labels) > 0 {
+		labels := GetLabels(ctx)
+		for key, value := range fo.Labels {
+			if actual, ok := labels[key]; !ok || actual != value {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// Lookup returns if a flag is enabled by the override for a given context,
+// and if the override applies to the context and flag at all.
+func (fo FlagOverride) Lookup(ctx context.Context, flag string) (enabled, ok bool) {
+	if fo.Flags == nil || !fo.Matches(ctx) {
+		return false, false
+	}
+	return fo.Flags.Lookup(flag)
+}
@@ -0,0 +1,123 @@
+package logger
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	"github.com/blend/go-sdk/assert"
+)
+
+func TestFlagOverrideMatches(t *testing.T) {
+	assert := assert.New(t)
+
+	override := NewFlagOverride(FlagOverrideConfig{Path: "/cron/my-job/", Flags: []string{Debug}})
+	assert.Equal([]string{"cron", "my-job"}, override.Path)
+	assert.True(override.Matches(WithPath(context.Background(), "cron", "my-job")))
+	assert.True(override.Matches(WithPath(context.Background(), "cron", "my-job", "invocation")))
+	assert.False(override.Matches(WithPath(context.Background(), "cron")))
+	assert.False(override.Matches(WithPath(context.Background(), "cron", "other-job")))
+	assert.False(override.Matches(context.Background()))
+
+	override = NewFlagOverride(FlagOverrideConfig{Labels: map[string]string{"team": "payments"}, Flags: []string{Debug}})
+	assert.True(override.Matches(WithLabels(context.Background(), Labels{"team": "payments", "env": "prod"})))
+	assert.False(override.Matches(WithLabels(context.Background(), Labels{"team": "search"})))
+	assert.False(override.Matches(context.Background()))
+
+	enabled, ok := override.Lookup(WithLabel(context.Background(), "team", "payments"), Debug)
+	assert.True(ok)
+	assert.True(enabled)
+	_, ok = override.Lookup(WithLabel(context.Background(), "team", "payments"), Info)
+	assert.False(ok)
+	_, ok = override.Lookup(context.Background(), Debug)
+	assert.False(ok)
+}
+
+func TestLoggerFlagOverrides(t *testing.T) {
+	assert := assert.New(t)
+
+	output := new(bytes.Buffer)
+	log := MustNew(
+		OptOutput(output),
+		OptText(OptTextHideTimestamp(), OptTextNoColor()),
+		OptEnabled(Info),
+		OptFlagOverridesConfig(
+			FlagOverrideConfig{Path: "cron/my-job", Flags: []string{Debug}},
+			FlagOverrideConfig{Labels: map[string]string{"quiet": "true"}, Flags: []string{"-info"}},
+		),
+	)
+	defer log.Close()
+
+	log.Debugf("global debug")
+	log.WithPath("cron", "other-job").Debugf("other job debug")
+	log.WithPath("cron", "my-job").Debugf("my job debug")
+	log.WithPath("cron", "my-job").Infof("my job info")
+	log.WithLabels(Labels{"quiet": "true"}).Infof("quiet info")
+	log.WithPath("cron", "my-job").WithLabels(Labels{"quiet": "true"}).Infof("quiet my job info")
+	log.Write(WithLabels(context.Background(), Labels{"quiet": "true"}), NewMessageEvent(Info, "quiet write"))
+	log.Write(context.Background(), NewMessageEvent(Debug, "plain write"))
+
+	assert.NotContains(output.String(), "global debug")
+	assert.NotContains(output.String(), "other job debug")
+	assert.Contains(output.String(), "my job debug")
+	assert.Contains(output.String(), "my job info")
+	assert.NotContains(output.String(), "quiet info")
+	assert.NotContains(output.String(), "quiet my job info")
+	assert.NotContains(output.String(), "quiet write")
+	assert.Contains(output.String(), "plain write")
+
+	assert.True(log.IsEnabledContext(WithPath(context.Background(), "cron", "my-job"), Debug))
+	assert.False(log.IsEnabledContext(context.Background(), Debug))
+}
+
+func TestFlagsLookup(t *testing.T) {
+	assert := assert.New(t)
+
+	flags := NewFlags(Debug, "-info")
+	enabled, ok := flags.Lookup(Debug)
+	assert.True(ok)
+	assert.True(enabled)
+	enabled, ok = flags.Lookup(Info)
+	assert.True(ok)
+	assert.False(enabled)
+	_, ok = flags.Lookup(Error)
+	assert.False(ok)
+
+	enabled, ok = FlagsAll().Lookup(Error)
+	assert.True(ok)
+	assert.True(enabled)
+	enabled, ok = FlagsNone().Lookup(Error)
+	assert.True(ok)
+	assert.False(enabled)
+}
+
+func TestConfigFlagOverrides(t *testing.T) {
+	assert := assert.New(t)
+
+	log := None()
+	assert.Nil(OptConfig(Config{
+		Overrides: []FlagOverrideConfig{{Path: "api", Flags: []string{Debug}}},
+	})(log))
+	assert.Len(log.Overrides, 1)
+	assert.Equal([]string{"api"}, log.Overrides[0].Path)
+}
+
+func BenchmarkLoggerTriggerNoOverrides(b *testing.B) {
+	log := MustNew(OptOutput(nil), OptEnabled(Info))
+	ctx := WithPath(context.Background(), "api")
+	e := NewMessageEvent(Debug, "debug")
+	b.ResetTimer()
+	for x := 0; x < b.N; x++ {
+		log.Trigger(ctx, e)
+	}
+}
+
+func BenchmarkLoggerTriggerOverrides(b *testing.B) {
+	log := MustNew(OptOutput(nil), OptEnabled(Info), OptFlagOverridesConfig(FlagOverrideConfig{Path: "cron", Flags: []string{Debug}}))
+	ctx := WithPath(context.Background(), "api")
+	e := NewMessageEvent(Debug, "debug")
+	b.ResetTimer()
+	for x := 0; x < b.N; x++ {
+		log.Trigger(ctx, e)
+	}
+}
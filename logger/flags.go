@@ -2,6 +2,7 @@ package logger
 
 import (
 	"strings"
+	"sync"
 )
 
 // NewFlags returns a new flag set from an array of flag values.
@@ -41,7 +42,9 @@ func FlagsAll() *Flags { return &Flags{all: true, flags: make(map[string]bool)}
 func FlagsNone() *Flags { return &Flags{none: true, flags: make(map[string]bool)} }
 
 // Flags is a set of event flags.
+// It is safe to enable and disable flags while the flag set is in use.
 type Flags struct {
+	mu    sync.RWMutex
 	flags map[string]bool
 	all   bool
 	none  bool
@@ -49,6 +52,8 @@ type Flags struct {
 
 // Enable enables an event flag.
 func (efs *Flags) Enable(flags ...string) {
+	efs.mu.Lock()
+	defer efs.mu.Unlock()
 	efs.none = false
 	for _, flag := range flags {
 		efs.flags[strings.ToLower(strings.TrimSpace(flag))] = true
@@ -57,6 +62,8 @@ func (efs *Flags) Enable(flags ...string) {
 
 // Disable disables a flag.
 func (efs *Flags) Disable(flags ...string) {
+	efs.mu.Lock()
+	defer efs.mu.Unlock()
 	for _, flag := range flags {
 		efs.flags[strings.ToLower(strings.TrimSpace(flag))] = false
 	}
@@ -65,18 +72,24 @@ func (efs *Flags) Disable(flags ...string) {
 // SetAll flips the `all` bit on the flag set to true.
 // Note: flags that are explicitly disabled will remain disabled.
 func (efs *Flags) SetAll() {
+	efs.mu.Lock()
+	defer efs.mu.Unlock()
 	efs.all = true
 	efs.none = false
 }
 
 // All returns if the all bit is flipped to true.
 func (efs *Flags) All() bool {
+	efs.mu.RLock()
+	defer efs.mu.RUnlock()
 	return efs.all
 }
 
 // SetNone flips the `none` bit on the flag set to true.
 // It also disables the `all` bit.
 func (efs *Flags) SetNone() {
+	efs.mu.Lock()
+	defer efs.mu.Unlock()
 	efs.all = false
 	efs.flags = make(map[string]bool)
 	efs.none = true
@@ -84,11 +97,15 @@ func (efs *Flags) SetNone() {
 
 // None returns if the none bit is flipped to true.
 func (efs *Flags) None() bool {
+	efs.mu.RLock()
+	defer efs.mu.RUnlock()
 	return efs.none
 }
 
 // IsEnabled checks to see if an event is enabled.
-func (efs Flags) IsEnabled(flag string) bool {
+func (efs *Flags) IsEnabled(flag string) bool {
+	efs.mu.RLock()
+	defer efs.mu.RUnlock()
 	if efs.all {
 		if efs.flags != nil {
 			if enabled, hasEvent := efs.flags[flag]; hasEvent && !enabled {
@@ -107,12 +124,14 @@ func (efs Flags) IsEnabled(flag string) bool {
 }
 
 // String returns a string representation of the flags.
-func (efs Flags) String() string {
+func (efs *Flags) String() string {
 	return strings.Join(efs.Flags(), ", ")
 }
 
 // Flags returns an array of flags.
-func (efs Flags) Flags() []string {
+func (efs *Flags) Flags() []string {
+	efs.mu.RLock()
+	defer efs.mu.RUnlock()
 	if efs.none {
 		return []string{FlagNone}
 	}
@@ -136,7 +155,14 @@ func (efs Flags) Flags() []string {
 }
 
 // MergeWith sets the set from another, with the other taking precedence.
-func (efs Flags) MergeWith(other *Flags) {
+func (efs *Flags) MergeWith(other *Flags) {
+	if other == efs {
+		return
+	}
+	efs.mu.Lock()
+	defer efs.mu.Unlock()
+	other.mu.RLock()
+	defer other.mu.RUnlock()
 	if other.all {
 		efs.all = true
 	}
@@ -147,3 +173,20 @@ func (efs Flags) MergeWith(other *Flags) {
 		efs.flags[key] = value
 	}
 }
+
+// Lookup returns if a flag is enabled, and if the flag set has an opinion on the flag at all,
+// i.e. it is explicitly set, or the `all` or `none` bits are flipped.
+func (efs *Flags) Lookup(flag string) (enabled, ok bool) {
+	efs.mu.RLock()
+	defer efs.mu.RUnlock()
+	if efs.none {
+		return false, true
+	}
+	if enabled, ok = efs.flags[flag]; ok {
+		return
+	}
+	if efs.all {
+		return true, true
+	}
+	return false, false
+}
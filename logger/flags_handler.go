@@ -0,0 +1,328 @@
+package logger
+
+import (
+	"encoding/json"
+	"net/http"
+	"os"
+	"os/signal"
+	"sort"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+)
+
+var (
+	_ http.Handler = (*FlagsHandler)(nil)
+)
+
+// Flags handler request parameters.
+const (
+	FlagsHandlerParamEnable  = "enable"
+	FlagsHandlerParamDisable = "disable"
+	FlagsHandlerParamTTL     = "ttl"
+)
+
+// NewFlagsHandler returns a new flags handler for a logger.
+func NewFlagsHandler(log *Logger, options ...FlagsHandlerOption) *FlagsHandler {
+	fh := &FlagsHandler{
+		Log:     log,
+		reverts: make(map[string]*flagRevert),
+	}
+	for _, option := range options {
+		option(fh)
+	}
+	return fh
+}
+
+// FlagsHandlerOption mutates a flags handler.
+type FlagsHandlerOption func(*FlagsHandler)
+
+// OptFlagsHandlerTTL sets the default time after which changed flags are reverted.
+// It is used if a request does not specify a ttl, and for signals.
+func OptFlagsHandlerTTL(ttl time.Duration) FlagsHandlerOption {
+	return func(fh *FlagsHandler) { fh.TTL = ttl }
+}
+
+// OptFlagsHandlerSignals sets the flags toggled by signals; SIGUSR1 enables them and SIGUSR2 reverts them.
+// Signals are handled once `NotifySignals` is called.
+func OptFlagsHandlerSignals(flags ...string) FlagsHandlerOption {
+	return func(fh *FlagsHandler) {
+		fh.SignalFlags = flags
+		fh.EnableSignal = syscall.SIGUSR1
+		fh.RevertSignal = syscall.SIGUSR2
+	}
+}
+
+/*
+FlagsHandler lists, enables and disables the flags of a running logger.
+
+It is an `http.Handler`; `GET` returns the enabled flags and any pending reverts as json,
+`POST` or `PUT` enable and disable flags with the `enable` and `disable` parameters (which can be
+comma separated or repeated), and `DELETE` reverts any changed flags immediately.
+If a `ttl` parameter (or a default TTL) is set, changed flags are also reverted after it elapses.
+
+It can be mounted in a `web.App` with:
+
+	flags := logger.NewFlagsHandler(log, logger.OptFlagsHandlerTTL(15*time.Minute))
+	app.Handle(http.MethodGet, "/debug/log/flags", web.WrapHandler(flags))
+	app.Handle(http.MethodPost, "/debug/log/flags", web.WrapHandler(flags))
+	app.Handle(http.MethodDelete, "/debug/log/flags", web.WrapHandler(flags))
+
+The handler does not authenticate requests; it should be mounted behind auth middleware or on an internal listener.
+*/
+type FlagsHandler struct {
+	Log *Logger
+	TTL time.Duration
+
+	SignalFlags  []string
+	EnableSignal os.Signal
+	RevertSignal os.Signal
+
+	mu      sync.Mutex
+	reverts map[string]*flagRevert
+	signals chan os.Signal
+	done    chan struct{}
+}
+
+// FlagsHandlerState is the state returned by the flags handler.
+type FlagsHandlerState struct {
+	Flags   []string             `json:"flags"`
+	Reverts map[string]time.Time `json:"reverts,omitempty"`
+}
+
+// flagRevert is the state a changed flag reverts to.
+// Expires and Timer are only set if the flag was changed with a ttl.
+type flagRevert struct {
+	Enabled bool
+	Expires time.Time
+	Timer   *time.Timer
+}
+
+// stop stops the revert timer, if it is set.
+func (fr *flagRevert) stop() {
+	if fr.Timer != nil {
+		fr.Timer.Stop()
+	}
+}
+
+// Enable enables flags, reverting them after the ttl if it is set.
+func (fh *FlagsHandler) Enable(ttl time.Duration, flags ...string) {
+	fh.set(true, ttl, flags...)
+}
+
+// Disable disables flags, reverting them after the ttl if it is set.
+func (fh *FlagsHandler) Disable(ttl time.Duration, flags ...string) {
+	fh.set(false, ttl, flags...)
+}
+
+// Revert reverts changed flags to their original state.
+// If no flags are given, all pending reverts are applied.
+func (fh *FlagsHandler) Revert(flags ...string) {
+	fh.mu.Lock()
+	defer fh.mu.Unlock()
+
+	if len(flags) == 0 {
+		for flag := range fh.reverts {
+			flags = append(flags, flag)
+		}
+	}
+	for _, flag := range flags {
+		fh.revertUnsafe(normalizeFlag(flag))
+	}
+}
+
+// State returns the enabled flags and the time any changed flags with a ttl will revert.
+func (fh *FlagsHandler) State() FlagsHandlerState {
+	fh.mu.Lock()
+	defer fh.mu.Unlock()
+
+	flags := fh.Log.Flags.Flags()
+	sort.Strings(flags)
+	state := FlagsHandlerState{
+		Flags: flags,
+	}
+	for flag, revert := range fh.reverts {
+		if revert.Timer == nil {
+			continue
+		}
+		if state.Reverts == nil {
+			state.Reverts = make(map[string]time.Time)
+		}
+		state.Reverts[flag] = revert.Expires
+	}
+	return state
+}
+
+// NotifySignals starts handling the enable and revert signals, if signal flags are set.
+func (fh *FlagsHandler) NotifySignals() {
+	fh.mu.Lock()
+	defer fh.mu.Unlock()
+
+	if fh.signals != nil || len(fh.SignalFlags) == 0 || fh.EnableSignal == nil || fh.RevertSignal == nil {
+		return
+	}
+	fh.signals = make(chan os.Signal, 1)
+	fh.done = make(chan struct{})
+	signal.Notify(fh.signals, fh.EnableSignal, fh.RevertSignal)
+	go func(signals chan os.Signal, done chan struct{}) {
+		for {
+			select {
+			case <-done:
+				return
+			case sig := <-signals:
+				if sig == fh.EnableSignal {
+					fh.Enable(fh.TTL, fh.SignalFlags...)
+				} else {
+					fh.Revert(fh.SignalFlags...)
+				}
+			}
+		}
+	}(fh.signals, fh.done)
+}
+
+// Close stops handling signals and stops any pending reverts.
+// Flags are left in their current state.
+func (fh *FlagsHandler) Close() error {
+	fh.mu.Lock()
+	defer fh.mu.Unlock()
+
+	if fh.signals != nil {
+		signal.Stop(fh.signals)
+		close(fh.done)
+		fh.signals = nil
+		fh.done = nil
+	}
+	for flag, revert := range fh.reverts {
+		revert.stop()
+		delete(fh.reverts, flag)
+	}
+	return nil
+}
+
+// ServeHTTP implements http.Handler.
+func (fh *FlagsHandler) ServeHTTP(rw http.ResponseWriter, req *http.Request) {
+	switch req.Method {
+	case http.MethodGet, http.MethodHead:
+	case http.MethodPost, http.MethodPut:
+		if err := req.ParseForm(); err != nil {
+			writeJSONError(rw, http.StatusBadRequest, err.Error())
+			return
+		}
+		enable := fh.formFlags(req, FlagsHandlerParamEnable)
+		disable := fh.formFlags(req, FlagsHandlerParamDisable)
+		if len(enable) == 0 && len(disable) == 0 {
+			writeJSONError(rw, http.StatusBadRequest, "no flags to enable or disable")
+			return
+		}
+		ttl := fh.TTL
+		if value := req.Form.Get(FlagsHandlerParamTTL); value != "" {
+			parsed, err := time.ParseDuration(value)
+			if err != nil || parsed < 0 {
+				writeJSONError(rw, http.StatusBadRequest, "invalid ttl: "+value)
+				return
+			}
+			ttl = parsed
+		}
+		fh.Enable(ttl, enable...)
+		fh.Disable(ttl, disable...)
+	case http.MethodDelete:
+		if err := req.ParseForm(); err != nil {
+			writeJSONError(rw, http.StatusBadRequest, err.Error())
+			return
+		}
+		fh.Revert(fh.formFlags(req, FlagsHandlerParamEnable, FlagsHandlerParamDisable)...)
+	default:
+		rw.Header().Set("Allow", strings.Join([]string{http.MethodGet, http.MethodPost, http.MethodPut, http.MethodDelete}, ", "))
+		writeJSONError(rw, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+	writeJSON(rw, http.StatusOK, fh.State())
+}
+
+//
+// internal helpers
+//
+
+func (fh *FlagsHandler) set(enabled bool, ttl time.Duration, flags ...string) {
+	if len(flags) == 0 {
+		return
+	}
+	fh.mu.Lock()
+	defer fh.mu.Unlock()
+
+	for _, flag := range flags {
+		flag = normalizeFlag(flag)
+		// keep the state from before any pending change to revert to.
+		previous := fh.Log.Flags.IsEnabled(flag)
+		if pending, ok := fh.reverts[flag]; ok {
+			pending.stop()
+			previous = pending.Enabled
+		}
+		revert := &flagRevert{Enabled: previous}
+		if ttl > 0 {
+			revert.Expires = time.Now().UTC().Add(ttl)
+			revert.Timer = time.AfterFunc(ttl, fh.revertFunc(flag, revert))
+		}
+		fh.reverts[flag] = revert
+		if enabled {
+			fh.Log.Flags.Enable(flag)
+		} else {
+			fh.Log.Flags.Disable(flag)
+		}
+	}
+}
+
+// revertFunc returns a timer func that reverts a flag if it has not been changed since.
+func (fh *FlagsHandler) revertFunc(flag string, revert *flagRevert) func() {
+	return func() {
+		fh.mu.Lock()
+		defer fh.mu.Unlock()
+		if fh.reverts[flag] == revert {
+			fh.revertUnsafe(flag)
+		}
+	}
+}
+
+func (fh *FlagsHandler) revertUnsafe(flag string) {
+	revert, ok := fh.reverts[flag]
+	if !ok {
+		return
+	}
+	revert.stop()
+	delete(fh.reverts, flag)
+	if revert.Enabled {
+		fh.Log.Flags.Enable(flag)
+	} else {
+		fh.Log.Flags.Disable(flag)
+	}
+}
+
+func (fh *FlagsHandler) formFlags(req *http.Request, keys ...string) (flags []string) {
+	for _, key := range keys {
+		for _, value := range req.Form[key] {
+			for _, flag := range strings.Split(value, ",") {
+				if flag = normalizeFlag(flag); flag != "" {
+					flags = append(flags, flag)
+				}
+			}
+		}
+	}
+	return
+}
+
+// writeJSONError writes an error message as json for the logger http handlers.
+func writeJSONError(rw http.ResponseWriter, statusCode int, message string) {
+	writeJSON(rw, statusCode, map[string]string{"error": message})
+}
+
+// writeJSON writes a json response for the logger http handlers.
+func writeJSON(rw http.ResponseWriter, statusCode int, response interface{}) {
+	rw.Header().Set("Content-Type", "application/json; charset=utf-8")
+	rw.WriteHeader(statusCode)
+	_ = json.NewEncoder(rw).Encode(response)
+}
+
+func normalizeFlag(flag string) string {
+	return strings.ToLower(strings.TrimSpace(flag))
+}
@@ -0,0 +1,161 @@
+package logger
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"strings"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/blend/go-sdk/assert"
+)
+
+func flagsHandlerRequest(fh *FlagsHandler, method string, values url.Values) (*httptest.ResponseRecorder, FlagsHandlerState) {
+	req := httptest.NewRequest(method, "/debug/log/flags", strings.NewReader(values.Encode()))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	res := httptest.NewRecorder()
+	fh.ServeHTTP(res, req)
+
+	var state FlagsHandlerState
+	_ = json.Unmarshal(res.Body.Bytes(), &state)
+	return res, state
+}
+
+func TestFlagsHandler(t *testing.T) {
+	assert := assert.New(t)
+
+	log := MustNew(OptOutput(nil), OptEnabled(Info, Error))
+	fh := NewFlagsHandler(log)
+	defer fh.Close()
+
+	res, state := flagsHandlerRequest(fh, http.MethodGet, nil)
+	assert.Equal(http.StatusOK, res.Code)
+	assert.Equal([]string{Error, Fatal, Info}, state.Flags)
+	assert.Empty(state.Reverts)
+
+	res, state = flagsHandlerRequest(fh, http.MethodPost, url.Values{
+		FlagsHandlerParamEnable:  {"debug,Query"},
+		FlagsHandlerParamDisable: {Info},
+	})
+	assert.Equal(http.StatusOK, res.Code)
+	assert.Equal([]string{"-info", Debug, Error, Fatal, "query"}, state.Flags)
+	assert.Empty(state.Reverts)
+	assert.True(log.Flags.IsEnabled(Debug))
+	assert.True(log.Flags.IsEnabled("query"))
+	assert.False(log.Flags.IsEnabled(Info))
+
+	res, _ = flagsHandlerRequest(fh, http.MethodPost, nil)
+	assert.Equal(http.StatusBadRequest, res.Code)
+
+	res, _ = flagsHandlerRequest(fh, http.MethodPost, url.Values{FlagsHandlerParamEnable: {Info}, FlagsHandlerParamTTL: {"not a duration"}})
+	assert.Equal(http.StatusBadRequest, res.Code)
+	assert.False(log.Flags.IsEnabled(Info))
+
+	res, _ = flagsHandlerRequest(fh, http.MethodPatch, nil)
+	assert.Equal(http.StatusMethodNotAllowed, res.Code)
+	assert.NotEmpty(res.Header().Get("Allow"))
+}
+
+func TestFlagsHandlerTTL(t *testing.T) {
+	assert := assert.New(t)
+
+	log := MustNew(OptOutput(nil), OptEnabled(Info))
+	fh := NewFlagsHandler(log, OptFlagsHandlerTTL(time.Hour))
+	defer fh.Close()
+
+	res, state := flagsHandlerRequest(fh, http.MethodPost, url.Values{
+		FlagsHandlerParamEnable:  {Debug},
+		FlagsHandlerParamDisable: {Info},
+	})
+	assert.Equal(http.StatusOK, res.Code)
+	assert.Len(state.Reverts, 2)
+	assert.True(state.Reverts[Debug].After(time.Now()))
+	assert.True(log.Flags.IsEnabled(Debug))
+	assert.False(log.Flags.IsEnabled(Info))
+
+	// changing a flag again keeps the original state to revert to.
+	fh.Enable(time.Hour, Info)
+	assert.True(log.Flags.IsEnabled(Info))
+
+	res, state = flagsHandlerRequest(fh, http.MethodDelete, nil)
+	assert.Equal(http.StatusOK, res.Code)
+	assert.Empty(state.Reverts)
+	assert.False(log.Flags.IsEnabled(Debug))
+	assert.True(log.Flags.IsEnabled(Info))
+
+	fh.Enable(time.Millisecond, Debug)
+	assert.True(log.Flags.IsEnabled(Debug))
+	deadline := time.Now().Add(5 * time.Second)
+	for log.Flags.IsEnabled(Debug) && time.Now().Before(deadline) {
+		time.Sleep(time.Millisecond)
+	}
+	assert.False(log.Flags.IsEnabled(Debug))
+	assert.Empty(fh.State().Reverts)
+
+	// a change without a ttl cancels a pending revert, but keeps the original state to revert to.
+	fh.Enable(time.Hour, Debug)
+	fh.Enable(0, Debug)
+	assert.Empty(fh.State().Reverts)
+	assert.True(log.Flags.IsEnabled(Debug))
+	fh.Revert(Debug)
+	assert.False(log.Flags.IsEnabled(Debug))
+}
+
+func TestFlagsHandlerSignals(t *testing.T) {
+	assert := assert.New(t)
+
+	log := MustNew(OptOutput(nil), OptEnabled(Info))
+	fh := NewFlagsHandler(log, OptFlagsHandlerTTL(time.Hour), OptFlagsHandlerSignals(Debug))
+	defer fh.Close()
+	fh.NotifySignals()
+
+	waitFor := func(enabled bool) bool {
+		deadline := time.Now().Add(5 * time.Second)
+		for time.Now().Before(deadline) {
+			if log.Flags.IsEnabled(Debug) == enabled {
+				return true
+			}
+			time.Sleep(time.Millisecond)
+		}
+		return false
+	}
+
+	assert.Nil(syscall.Kill(os.Getpid(), syscall.SIGUSR1))
+	assert.True(waitFor(true))
+	assert.Len(fh.State().Reverts, 1)
+
+	assert.Nil(syscall.Kill(os.Getpid(), syscall.SIGUSR2))
+	assert.True(waitFor(false))
+	assert.Empty(fh.State().Reverts)
+}
+
+func TestFlagsHandlerSignalsWithoutTTL(t *testing.T) {
+	assert := assert.New(t)
+
+	log := MustNew(OptOutput(nil), OptEnabled(Info))
+	fh := NewFlagsHandler(log, OptFlagsHandlerSignals(Debug))
+	defer fh.Close()
+	fh.NotifySignals()
+
+	waitFor := func(enabled bool) bool {
+		deadline := time.Now().Add(5 * time.Second)
+		for time.Now().Before(deadline) {
+			if log.Flags.IsEnabled(Debug) == enabled {
+				return true
+			}
+			time.Sleep(time.Millisecond)
+		}
+		return false
+	}
+
+	assert.Nil(syscall.Kill(os.Getpid(), syscall.SIGUSR1))
+	assert.True(waitFor(true))
+	assert.Empty(fh.State().Reverts)
+
+	assert.Nil(syscall.Kill(os.Getpid(), syscall.SIGUSR2))
+	assert.True(waitFor(false))
+}
@@ -26,6 +26,13 @@ type Scoper interface {
 	WithAnnotations(Annotations) Scope
 }
 
+// Drainer is a type that buffers events from listeners and can flush them.
+// Drainers registered with `Logger.AddDrainer` are drained after the listeners
+// when the logger is drained or closed.
+type Drainer interface {
+	DrainContext(context.Context) error
+}
+
 // Writable is an type that can write events.
 type Writable interface {
 	Write(context.Context, Event)
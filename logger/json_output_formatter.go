@@ -85,7 +85,11 @@ func (jw JSONOutputFormatter) WriteFormat(ctx context.Context, output io.Writer,
 		encoder.SetIndent(jw.PrettyPrefixOrDefault(), jw.PrettyIndentOrDefault())
 	}
 	if decomposer, ok := e.(JSONWritable); ok {
-		fields := jw.CombineFields(jw.GetScopeFields(ctx, e), decomposer.Decompose())
+		decomposed := decomposer.Decompose()
+		if redactor := GetRedactor(ctx); redactor != nil {
+			decomposed = redactor.Fields(decomposed)
+		}
+		fields := jw.CombineFields(jw.GetScopeFields(ctx, e), decomposed)
 		if err := encoder.Encode(fields); err != nil {
 			return err
 		}
@@ -124,6 +128,10 @@ func (jw JSONOutputFormatter) GetScopeFields(ctx context.Context, e Event) map[s
 	if path := GetPath(ctx); len(path) > 0 {
 		output[FieldScopePath] = path
 	}
+	if traceID, spanID := GetTrace(ctx); traceID != "" || spanID != "" {
+		output[FieldTraceID] = traceID
+		output[FieldSpanID] = spanID
+	}
 	if labels := GetLabels(ctx); len(labels) > 0 {
 		output[FieldLabels] = labels
 	}
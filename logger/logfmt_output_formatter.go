@@ -0,0 +1,295 @@
+package logger
+
+import (
+	"bytes"
+	"context"
+	"encoding"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/blend/go-sdk/bufferutil"
+)
+
+var (
+	_ WriteFormatter = (*LogfmtOutputFormatter)(nil)
+)
+
+// NewLogfmtOutputFormatter returns a new logfmt event formatter.
+func NewLogfmtOutputFormatter(options ...LogfmtOutputFormatterOption) *LogfmtOutputFormatter {
+	lf := &LogfmtOutputFormatter{
+		BufferPool: bufferutil.NewPool(DefaultBufferPoolSize),
+		TimeFormat: DefaultTextTimeFormat,
+	}
+	for _, option := range options {
+		option(lf)
+	}
+	return lf
+}
+
+// LogfmtOutputFormatterOption is an option for logfmt formatters.
+type LogfmtOutputFormatterOption func(*LogfmtOutputFormatter)
+
+// OptLogfmtConfig sets a logfmt formatter from a config.
+func OptLogfmtConfig(cfg TextConfig) LogfmtOutputFormatterOption {
+	return func(lf *LogfmtOutputFormatter) {
+		lf.HideTimestamp = cfg.HideTimestamp
+		lf.TimeFormat = cfg.TimeFormatOrDefault()
+	}
+}
+
+// OptLogfmtTimeFormat sets the timestamp format.
+func OptLogfmtTimeFormat(format string) LogfmtOutputFormatterOption {
+	return func(lf *LogfmtOutputFormatter) { lf.TimeFormat = format }
+}
+
+// OptLogfmtHideTimestamp hides the timestamp in output.
+func OptLogfmtHideTimestamp() LogfmtOutputFormatterOption {
+	return func(lf *LogfmtOutputFormatter) { lf.HideTimestamp = true }
+}
+
+/*
+LogfmtOutputFormatter writes events as logfmt, i.e. a single line of `key=value` pairs.
+
+The timestamp, flag and scope path are written first, followed by the event fields (from `Decompose()`)
+in alphabetical order, and then the labels and annotations, e.g.:
+
+	_timestamp=2020-01-02T03:04:05Z flag=info scope_path=api text="hello world" labels.env=prod
+
+The trace and span id, if the context has them, are written after the scope path.
+Nested maps and slices are flattened with dotted keys, and values are quoted if they contain
+spaces, quotes, `=` or control characters.
+*/
+type LogfmtOutputFormatter struct {
+	HideTimestamp bool
+	TimeFormat    string
+
+	BufferPool *bufferutil.Pool
+}
+
+// TimeFormatOrDefault returns the time format or a default.
+func (lf LogfmtOutputFormatter) TimeFormatOrDefault() string {
+	if len(lf.TimeFormat) > 0 {
+		return lf.TimeFormat
+	}
+	return DefaultTextTimeFormat
+}
+
+// WriteFormat implements WriteFormatter.
+func (lf LogfmtOutputFormatter) WriteFormat(ctx context.Context, output io.Writer, e Event) error {
+	buffer := lf.BufferPool.Get()
+	defer lf.BufferPool.Put(buffer)
+
+	if !lf.HideTimestamp {
+		lf.writePair(buffer, FieldTimestamp, GetEventTimestamp(ctx, e).Format(lf.TimeFormatOrDefault()))
+	}
+	lf.writePair(buffer, FieldFlag, e.GetFlag())
+	if path := GetPath(ctx); len(path) > 0 {
+		lf.writePair(buffer, FieldScopePath, strings.Join(path, "."))
+	}
+	if traceID, spanID := GetTrace(ctx); traceID != "" || spanID != "" {
+		lf.writePair(buffer, FieldTraceID, traceID)
+		lf.writePair(buffer, FieldSpanID, spanID)
+	}
+
+	redactor := GetRedactor(ctx)
+	if decomposer, ok := e.(JSONWritable); ok {
+		fields := decomposer.Decompose()
+		if redactor != nil {
+			fields = redactor.Fields(fields)
+		}
+		lf.writeFields(buffer, "", fields)
+	} else if text := lf.eventText(e); text != "" {
+		if redactor != nil {
+			text = redactor.String(text)
+		}
+		lf.writePair(buffer, FieldText, text)
+	}
+
+	if labels := GetLabels(ctx); len(labels) > 0 {
+		lf.writeValue(buffer, FieldLabels, labels)
+	}
+	if annotations := GetAnnotations(ctx); len(annotations) > 0 {
+		lf.writeValue(buffer, FieldAnnotations, annotations)
+	}
+
+	buffer.WriteString(Newline)
+	_, err := io.Copy(output, buffer)
+	return err
+}
+
+func (lf LogfmtOutputFormatter) eventText(e Event) string {
+	if typed, ok := e.(TextWritable); ok {
+		buffer := new(bytes.Buffer)
+		typed.WriteText(TextOutputFormatter{NoColor: true}, buffer)
+		return buffer.String()
+	}
+	if stringer, ok := e.(fmt.Stringer); ok {
+		return stringer.String()
+	}
+	return ""
+}
+
+// writeFields writes a map of fields in key order, with an optional key prefix.
+func (lf LogfmtOutputFormatter) writeFields(buffer *bytes.Buffer, prefix string, fields map[string]interface{}) {
+	keys := make([]string, 0, len(fields))
+	for key := range fields {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		lf.writeValue(buffer, logfmtJoinKey(prefix, key), fields[key])
+	}
+}
+
+// writeValue writes a value, flattening maps and slices into multiple pairs.
+func (lf LogfmtOutputFormatter) writeValue(buffer *bytes.Buffer, key string, value interface{}) {
+	switch typed := value.(type) {
+	case map[string]interface{}:
+		lf.writeFields(buffer, key, typed)
+	case Annotations:
+		lf.writeFields(buffer, key, typed)
+	case Labels:
+		lf.writeStrings(buffer, key, typed)
+	case map[string]string:
+		lf.writeStrings(buffer, key, typed)
+	case map[string]int64:
+		fields := make(map[string]interface{}, len(typed))
+		for k, v := range typed {
+			fields[k] = v
+		}
+		lf.writeFields(buffer, key, fields)
+	case http.Header:
+		fields := make(map[string]interface{}, len(typed))
+		for k, v := range typed {
+			fields[k] = strings.Join(v, ",")
+		}
+		lf.writeFields(buffer, key, fields)
+	case []interface{}:
+		for index, element := range typed {
+			lf.writeValue(buffer, logfmtJoinKey(key, strconv.Itoa(index)), element)
+		}
+	case []string:
+		for index, element := range typed {
+			lf.writePair(buffer, logfmtJoinKey(key, strconv.Itoa(index)), element)
+		}
+	default:
+		lf.writePair(buffer, key, lf.formatValue(value))
+	}
+}
+
+func (lf LogfmtOutputFormatter) writeStrings(buffer *bytes.Buffer, prefix string, values map[string]string) {
+	keys := make([]string, 0, len(values))
+	for key := range values {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		lf.writePair(buffer, logfmtJoinKey(prefix, key), values[key])
+	}
+}
+
+func (lf LogfmtOutputFormatter) formatValue(value interface{}) string {
+	switch typed := value.(type) {
+	case nil:
+		return ""
+	case string:
+		return typed
+	case time.Time:
+		return typed.Format(lf.TimeFormatOrDefault())
+	case time.Duration:
+		return typed.String()
+	case error:
+		return typed.Error()
+	case fmt.Stringer:
+		return typed.String()
+	case encoding.TextMarshaler:
+		contents, err := typed.MarshalText()
+		if err != nil {
+			return err.Error()
+		}
+		return string(contents)
+	default:
+		return fmt.Sprint(value)
+	}
+}
+
+// writePair writes a single `key=value` pair, separated from any previous pair by a space.
+func (lf LogfmtOutputFormatter) writePair(buffer *bytes.Buffer, key, value string) {
+	if buffer.Len() > 0 {
+		buffer.WriteString(Space)
+	}
+	buffer.WriteString(LogfmtKey(key))
+	buffer.WriteByte('=')
+	buffer.WriteString(LogfmtValue(value))
+}
+
+func logfmtJoinKey(prefix, key string) string {
+	if prefix == "" {
+		return key
+	}
+	return prefix + "." + key
+}
+
+// LogfmtKey returns a key that is safe to use in logfmt output;
+// spaces, quotes, `=` and control characters are replaced with underscores.
+func LogfmtKey(key string) string {
+	if key == "" {
+		return "_"
+	}
+	return strings.Map(func(r rune) rune {
+		if r <= ' ' || r == '=' || r == '"' || r == utf8.RuneError || unicode.IsControl(r) || unicode.IsSpace(r) {
+			return '_'
+		}
+		return r
+	}, key)
+}
+
+// LogfmtValue returns a value quoted and escaped if it needs to be for logfmt output.
+// Values are quoted if they are empty, or contain spaces, quotes, `=`, backslashes or control characters.
+func LogfmtValue(value string) string {
+	if value == "" {
+		return `""`
+	}
+	if !logfmtNeedsQuotes(value) {
+		return value
+	}
+	var output strings.Builder
+	output.WriteByte('"')
+	for _, r := range value {
+		switch r {
+		case '"', '\\':
+			output.WriteByte('\\')
+			output.WriteRune(r)
+		case '\n':
+			output.WriteString(`\n`)
+		case '\r':
+			output.WriteString(`\r`)
+		case '\t':
+			output.WriteString(`\t`)
+		default:
+			if unicode.IsControl(r) {
+				output.WriteString(strconv.QuoteRune(r)[1 : len(strconv.QuoteRune(r))-1])
+			} else {
+				output.WriteRune(r)
+			}
+		}
+	}
+	output.WriteByte('"')
+	return output.String()
+}
+
+func logfmtNeedsQuotes(value string) bool {
+	for _, r := range value {
+		if r <= ' ' || r == '=' || r == '"' || r == '\\' || r == utf8.RuneError || unicode.IsControl(r) || unicode.IsSpace(r) {
+			return true
+		}
+	}
+	return false
+}
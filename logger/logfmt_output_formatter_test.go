@@ -0,0 +1,117 @@
+package logger
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/blend/go-sdk/assert"
+	"github.com/blend/go-sdk/env"
+)
+
+func TestLogfmtOutputFormatter(t *testing.T) {
+	assert := assert.New(t)
+
+	lf := NewLogfmtOutputFormatter(OptLogfmtHideTimestamp())
+	assert.True(lf.HideTimestamp)
+
+	buf := new(bytes.Buffer)
+	assert.Nil(lf.WriteFormat(context.Background(), buf, NewMessageEvent(Info, "this is a test")))
+	assert.Equal("flag=info text=\"this is a test\"\n", buf.String())
+
+	ts := time.Date(2020, 01, 02, 03, 04, 05, 0, time.UTC)
+	lf = NewLogfmtOutputFormatter(OptLogfmtTimeFormat(time.RFC3339))
+	ctx := WithTimestamp(context.Background(), ts)
+	ctx = WithPath(ctx, "api", "users")
+	ctx = WithLabels(ctx, Labels{"env": "prod", "region": "us east"})
+	ctx = WithAnnotations(ctx, Annotations{"attempt": 2, "nested": map[string]interface{}{"ok": true}})
+
+	buf = new(bytes.Buffer)
+	assert.Nil(lf.WriteFormat(ctx, buf, NewMessageEvent(Info, "hello", OptMessageElapsed(time.Second))))
+	assert.Equal(
+		"_timestamp=2020-01-02T03:04:05Z flag=info scope_path=api.users elapsed=1000 text=hello "+
+			"labels.env=prod labels.region=\"us east\" annotations.attempt=2 annotations.nested.ok=true\n",
+		buf.String(),
+	)
+}
+
+func TestLogfmtOutputFormatterNested(t *testing.T) {
+	assert := assert.New(t)
+
+	lf := NewLogfmtOutputFormatter(OptLogfmtHideTimestamp())
+	buf := new(bytes.Buffer)
+	assert.Nil(lf.WriteFormat(context.Background(), buf, mockDecomposer{
+		"err":   fmt.Errorf("bad thing"),
+		"list":  []interface{}{"a", map[string]interface{}{"b": "c"}},
+		"tags":  []string{"x", "y z"},
+		"empty": "",
+		"nil":   nil,
+		"map":   map[string]string{"k": "v"},
+	}))
+	assert.Equal(
+		"flag=mock empty=\"\" err=\"bad thing\" list.0=a list.1.b=c map.k=v nil=\"\" tags.0=x tags.1=\"y z\"\n",
+		buf.String(),
+	)
+}
+
+func TestLogfmtOutputFormatterRedacted(t *testing.T) {
+	assert := assert.New(t)
+
+	output := new(bytes.Buffer)
+	log := MustNew(
+		OptAll(),
+		OptOutput(output),
+		OptLogfmt(OptLogfmtHideTimestamp()),
+		OptRedaction(RedactionConfig{Builtins: []string{RedactBearerToken}}),
+	)
+	defer log.Close()
+
+	log.WithLabels(Labels{"password": "hunter2"}).Infof("header was Bearer abcd")
+	assert.Equal("flag=info text=\"header was [REDACTED]\" labels.password=[REDACTED]\n", output.String())
+}
+
+func TestLogfmtKey(t *testing.T) {
+	assert := assert.New(t)
+
+	assert.Equal("foo", LogfmtKey("foo"))
+	assert.Equal("foo_bar", LogfmtKey("foo bar"))
+	assert.Equal("foo_bar", LogfmtKey("foo=bar"))
+	assert.Equal("foo_bar_", LogfmtKey("foo\"bar\n"))
+	assert.Equal("_", LogfmtKey(""))
+}
+
+func TestLogfmtValue(t *testing.T) {
+	assert := assert.New(t)
+
+	assert.Equal("foo", LogfmtValue("foo"))
+	assert.Equal("foo.bar/baz:1", LogfmtValue("foo.bar/baz:1"))
+	assert.Equal(`""`, LogfmtValue(""))
+	assert.Equal(`"foo bar"`, LogfmtValue("foo bar"))
+	assert.Equal(`"a=b"`, LogfmtValue("a=b"))
+	assert.Equal(`"say \"hi\""`, LogfmtValue(`say "hi"`))
+	assert.Equal(`"c:\\temp"`, LogfmtValue(`c:\temp`))
+	assert.Equal(`"line\nbreak\ttab"`, LogfmtValue("line\nbreak\ttab"))
+	assert.Equal(`"bell\a"`, LogfmtValue("bell\a"))
+	assert.Equal("héllo", LogfmtValue("héllo"))
+}
+
+func TestConfigFormatterLogfmt(t *testing.T) {
+	assert := assert.New(t)
+
+	defer env.Restore()
+	env.Env().Set(EnvVarFormat, FormatLogfmt)
+	env.Env().Set("LOG_HIDE_TIMESTAMP", "true")
+
+	log := None()
+	assert.Nil(OptConfigFromEnv()(log))
+	typed, ok := log.Formatter.(*LogfmtOutputFormatter)
+	assert.True(ok)
+	assert.True(typed.HideTimestamp)
+}
+
+type mockDecomposer map[string]interface{}
+
+func (md mockDecomposer) GetFlag() string                   { return "mock" }
+func (md mockDecomposer) Decompose() map[string]interface{} { return md }
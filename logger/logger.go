@@ -5,6 +5,9 @@ import (
 	"io"
 	"os"
 	"sync"
+	"time"
+
+	"github.com/blend/go-sdk/ex"
 )
 
 // New returns a new logger with a given set of enabled flags.
@@ -23,6 +26,9 @@ func New(options ...Option) (*Logger, error) {
 			return nil, err
 		}
 	}
+	if l.Sampler != nil {
+		l.Sampler.Start(l)
+	}
 	return l, nil
 }
 
@@ -70,11 +76,24 @@ type Logger struct {
 	Scope
 
 	RecoverPanics bool
+	// ShutdownTimeout is the time drainers are given to drain when the logger is closed;
+	// if unset `DefaultShutdownTimeout` is used.
+	ShutdownTimeout time.Duration
 
 	Output    io.Writer
 	Formatter WriteFormatter
 	Errors    chan error
 	Listeners map[string]map[string]*Worker
+	Sampler   *Sampler
+	Redactor  *Redactor
+	Drainers  map[string]Drainer
+
+	RecentEvents *RecentEvents
+	Overrides    []FlagOverride
+
+	TraceExtractor TraceExtractor
+
+	outputCloser io.Closer
 }
 
 // HasListeners returns if there are registered listener for an event.
@@ -126,6 +145,25 @@ func (l *Logger) Listen(flag, listenerName string, listener Listener) {
 	<-eventListener.NotifyStarted()
 }
 
+// AddDrainer adds a drainer that is drained after the listeners when the logger is drained or closed.
+func (l *Logger) AddDrainer(name string, drainer Drainer) {
+	l.Lock()
+	defer l.Unlock()
+
+	if l.Drainers == nil {
+		l.Drainers = make(map[string]Drainer)
+	}
+	l.Drainers[name] = drainer
+}
+
+// RemoveDrainer removes a drainer by name.
+func (l *Logger) RemoveDrainer(name string) {
+	l.Lock()
+	defer l.Unlock()
+
+	delete(l.Drainers, name)
+}
+
 // RemoveListeners clears *all* listeners for a Flag.
 func (l *Logger) RemoveListeners(flag string) error {
 	l.Lock()
@@ -181,17 +219,40 @@ func (l *Logger) RemoveListener(flag, listenerName string) error {
 // The invocations will be queued in a work queue per listener.
 // There are no order guarantees on when these events will be processed across listeners.
 // This call will not block on the event listeners, but will block on the write.
+// If the logger has a sampler, events it drops are not passed to listeners and / or written.
+// If the logger has a redactor, listeners and the output receive the redacted event and context.
+// If the logger has flag overrides, they are applied for the context scope path and labels.
+// If the logger has a trace extractor, the trace and span id are added to the event context.
+// If the logger keeps recent events, the event is added to them whether or not its flag is enabled.
+// If the context has a capture (see `WithCapture`), the event is also written to it.
 func (l *Logger) Trigger(ctx context.Context, e Event) {
 	if e == nil {
 		return
 	}
 
+	if l.RecentEvents != nil {
+		ctx = l.traceContext(ctx)
+		l.RecentEvents.Add(l.recentEventContext(ctx), e)
+	}
+
 	flag := e.GetFlag()
-	if !l.IsEnabled(flag) {
+	if !l.IsEnabledContext(ctx, flag) {
 		return
 	}
 
-	if !IsSkipTrigger(ctx) {
+	listen, write := true, true
+	if l.Sampler != nil {
+		listen, write = l.Sampler.Sample(ctx, e)
+	}
+	if !listen && !write {
+		return
+	}
+	ctx = l.traceContext(ctx)
+	if l.Redactor != nil {
+		ctx, e = l.Redactor.Apply(ctx, e)
+	}
+
+	if listen && !IsSkipTrigger(ctx) {
 		var listeners map[string]*Worker
 		l.Lock()
 		if l.Listeners != nil {
@@ -204,10 +265,44 @@ func (l *Logger) Trigger(ctx context.Context, e Event) {
 			listener.Work <- EventWithContext{ctx, e}
 		}
 	}
-	l.Write(ctx, e)
+	if write {
+		l.Write(ctx, e)
+		l.capture(ctx, e)
+	}
+}
+
+// IsEnabledContext returns if a flag is enabled for a given context,
+// applying any flag overrides for the context scope path and labels.
+func (l *Logger) IsEnabledContext(ctx context.Context, flag string) bool {
+	if len(l.Overrides) > 0 {
+		if enabled, ok := l.lookupOverride(ctx, flag); ok {
+			return enabled
+		}
+	}
+	return l.IsEnabled(flag)
+}
+
+// lookupOverride returns the last matching override's opinion on a flag.
+func (l *Logger) lookupOverride(ctx context.Context, flag string) (enabled, ok bool) {
+	for index := len(l.Overrides) - 1; index >= 0; index-- {
+		if enabled, ok = l.Overrides[index].Lookup(ctx, flag); ok {
+			return
+		}
+	}
+	return false, false
+}
+
+// recentEventContext returns the context recent events are kept with.
+// The redactor is carried with the context so recent events are redacted when they are read.
+func (l *Logger) recentEventContext(ctx context.Context) context.Context {
+	if l.Redactor != nil {
+		return WithRedactor(ctx, l.Redactor)
+	}
+	return ctx
 }
 
 // Write writes an event synchronously to the writer either as a normal even or as an error.
+// Flag overrides that disable the event flag for the context are applied.
 func (l *Logger) Write(ctx context.Context, e Event) {
 	// if a formater or the output are unset, bail.
 	if l.Formatter == nil || l.Output == nil {
@@ -217,6 +312,15 @@ func (l *Logger) Write(ctx context.Context, e Event) {
 	if IsSkipWrite(ctx) {
 		return
 	}
+	if len(l.Overrides) > 0 {
+		if enabled, ok := l.lookupOverride(ctx, e.GetFlag()); ok && !enabled {
+			return
+		}
+	}
+	ctx = l.traceContext(ctx)
+	if l.Redactor != nil && GetRedactor(ctx) == nil {
+		ctx, e = l.Redactor.Apply(ctx, e)
+	}
 
 	err := l.Formatter.WriteFormat(ctx, l.Output, e)
 	if err != nil && l.Errors != nil {
@@ -224,26 +328,63 @@ func (l *Logger) Write(ctx context.Context, e Event) {
 	}
 }
 
+// capture writes an event to the capture on the context, if there is one.
+func (l *Logger) capture(ctx context.Context, e Event) {
+	capture := GetCapture(ctx)
+	if capture == nil || capture.Output == nil || capture.Formatter == nil {
+		return
+	}
+	if err := capture.Formatter.WriteFormat(ctx, capture.Output, e); err != nil && l.Errors != nil {
+		l.Errors <- err
+	}
+}
+
 // --------------------------------------------------------------------------------
 // finalizers
 // --------------------------------------------------------------------------------
 
 // Close releases shared resources for the agent.
-// It will stop listeners and wait for them to complete work
+// It will stop listeners and wait for them to complete work,
+// drain any drainers for up to the shutdown timeout,
 // and then zero out any other resources.
 func (l *Logger) Close() error {
+	drainers, outputCloser, err := l.closeListeners()
+
+	ctx, cancel := context.WithTimeout(context.Background(), l.shutdownTimeoutOrDefault())
+	defer cancel()
+	for _, drainer := range drainers {
+		if drainErr := drainer.DrainContext(ctx); drainErr != nil {
+			err = ex.Nest(err, drainErr)
+		}
+	}
+	if outputCloser != nil {
+		if closeErr := outputCloser.Close(); closeErr != nil {
+			err = ex.Nest(err, closeErr)
+		}
+	}
+	return err
+}
+
+// closeListeners stops the sampler and listeners, and returns the drainers and output closer
+// so they can be drained and closed without holding the lock.
+// Errors stopping the sampler or listeners are returned along with them, so the output is still closed.
+func (l *Logger) closeListeners() (drainers []Drainer, outputCloser io.Closer, err error) {
 	l.Lock()
 	defer l.Unlock()
 
 	if l.Flags != nil {
 		l.Flags.SetNone()
 	}
+	if l.Sampler != nil {
+		if stopErr := l.Sampler.Stop(); stopErr != nil {
+			err = ex.Nest(err, stopErr)
+		}
+	}
 
-	var err error
 	for _, listeners := range l.Listeners {
 		for _, listener := range listeners {
-			if err = listener.Stop(); err != nil {
-				return err
+			if stopErr := listener.Stop(); stopErr != nil {
+				err = ex.Nest(err, stopErr)
 			}
 		}
 	}
@@ -251,7 +392,23 @@ func (l *Logger) Close() error {
 		delete(l.Listeners, key)
 	}
 	l.Listeners = nil
-	return nil
+
+	drainers = make([]Drainer, 0, len(l.Drainers))
+	for _, drainer := range l.Drainers {
+		drainers = append(drainers, drainer)
+	}
+	outputCloser = l.outputCloser
+	l.outputCloser = nil
+	return
+}
+
+func (l *Logger) shutdownTimeoutOrDefault() time.Duration {
+	l.Lock()
+	defer l.Unlock()
+	if l.ShutdownTimeout > 0 {
+		return l.ShutdownTimeout
+	}
+	return DefaultShutdownTimeout
 }
 
 // Drain stops the event listeners, letting them complete their work
@@ -261,6 +418,7 @@ func (l *Logger) Drain() error {
 }
 
 // DrainContext waits for the logger to finish its queue of events with a given context.
+// Once the listeners have processed their queues, any drainers are drained.
 func (l *Logger) DrainContext(ctx context.Context) error {
 	var err error
 	for _, workers := range l.Listeners {
@@ -271,5 +429,16 @@ func (l *Logger) DrainContext(ctx context.Context) error {
 			go worker.Start()
 		}
 	}
+	l.Lock()
+	drainers := make([]Drainer, 0, len(l.Drainers))
+	for _, drainer := range l.Drainers {
+		drainers = append(drainers, drainer)
+	}
+	l.Unlock()
+	for _, drainer := range drainers {
+		if err = drainer.DrainContext(ctx); err != nil {
+			return err
+		}
+	}
 	return nil
 }
@@ -8,6 +8,7 @@ import (
 	"time"
 
 	"github.com/blend/go-sdk/assert"
+	"github.com/blend/go-sdk/ex"
 	"github.com/blend/go-sdk/uuid"
 )
 
@@ -188,3 +189,84 @@ func TestLoggerProd(t *testing.T) {
 	assert.True(p.Flags.IsEnabled("bailey"))
 	assert.True(p.Formatter.(*TextOutputFormatter).NoColor)
 }
+
+type mockDrainer struct {
+	drained int
+}
+
+func (md *mockDrainer) DrainContext(_ context.Context) error {
+	md.drained++
+	return nil
+}
+
+func TestLoggerDrainers(t *testing.T) {
+	assert := assert.New(t)
+
+	log := MustNew(OptOutput(nil))
+	drainer := new(mockDrainer)
+	log.AddDrainer("test", drainer)
+
+	assert.Nil(log.DrainContext(context.Background()))
+	assert.Equal(1, drainer.drained)
+	assert.Nil(log.Close())
+	assert.Equal(2, drainer.drained)
+
+	log.RemoveDrainer("test")
+	assert.Empty(log.Drainers)
+}
+
+type drainerFunc func(context.Context) error
+
+func (df drainerFunc) DrainContext(ctx context.Context) error {
+	return df(ctx)
+}
+
+func TestLoggerCloseShutdownTimeout(t *testing.T) {
+	assert := assert.New(t)
+
+	log := MustNew(OptOutput(nil), OptShutdownTimeout(10*time.Millisecond))
+	log.AddDrainer("test", drainerFunc(func(ctx context.Context) error {
+		// drainers can use the logger while they drain.
+		log.HasListeners(Info)
+		<-ctx.Done()
+		return ctx.Err()
+	}))
+
+	closed := make(chan error)
+	go func() { closed <- log.Close() }()
+	select {
+	case err := <-closed:
+		assert.True(ex.Is(err, context.DeadlineExceeded))
+	case <-time.After(5 * time.Second):
+		assert.FailNow("close should return once the shutdown timeout elapses")
+	}
+}
+
+type closerFunc func() error
+
+func (cf closerFunc) Close() error { return cf() }
+
+func TestLoggerCloseDrainErrors(t *testing.T) {
+	assert := assert.New(t)
+
+	log := MustNew(OptOutput(nil))
+	var drained int
+	for _, name := range []string{"one", "two"} {
+		name := name
+		log.AddDrainer(name, drainerFunc(func(_ context.Context) error {
+			drained++
+			return fmt.Errorf("%s failed", name)
+		}))
+	}
+	var closed bool
+	log.outputCloser = closerFunc(func() error {
+		closed = true
+		return nil
+	})
+
+	err := log.Close()
+	assert.NotNil(err)
+	assert.Equal(2, drained, "every drainer should be drained")
+	assert.True(closed, "the output should be closed even if draining fails")
+	assert.NotNil(ex.ErrInner(err), "the drainer errors should be combined")
+}
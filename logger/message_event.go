@@ -4,11 +4,14 @@ import (
 	"context"
 	"io"
 	"time"
+
+	"github.com/blend/go-sdk/timeutil"
 )
 
 // these are compile time assertions
 var (
-	_ Event = (*MessageEvent)(nil)
+	_ Event      = (*MessageEvent)(nil)
+	_ Redactable = (*MessageEvent)(nil)
 )
 
 // NewMessageEvent returns a new message event.
@@ -74,10 +77,16 @@ func (e MessageEvent) Decompose() map[string]interface{} {
 	if e.Elapsed > 0 {
 		return map[string]interface{}{
 			FieldText:    e.Text,
-			FieldElapsed: e.Elapsed,
+			FieldElapsed: timeutil.Milliseconds(e.Elapsed),
 		}
 	}
 	return map[string]interface{}{
 		FieldText: e.Text,
 	}
 }
+
+// Redact implements Redactable.
+func (e MessageEvent) Redact(r *Redactor) Event {
+	e.Text = r.String(e.Text)
+	return e
+}
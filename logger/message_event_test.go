@@ -31,4 +31,7 @@ func TestMessageEvent(t *testing.T) {
 	contents, err := json.Marshal(me)
 	assert.Nil(err)
 	assert.Contains(string(contents), "event-message")
+
+	decomposed := me.Decompose()
+	assert.Equal(float64(1000), decomposed[FieldElapsed])
 }
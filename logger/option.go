@@ -2,6 +2,7 @@ package logger
 
 import (
 	"io"
+	"time"
 
 	"github.com/blend/go-sdk/env"
 )
@@ -12,9 +13,7 @@ type Option func(*Logger) error
 // OptConfig sets the logger based on a config.
 func OptConfig(cfg Config) Option {
 	return func(l *Logger) error {
-		l.Formatter = cfg.Formatter()
-		l.Flags = NewFlags(cfg.FlagsOrDefault()...)
-		return nil
+		return applyConfig(l, cfg)
 	}
 }
 
@@ -26,12 +25,56 @@ func OptConfigFromEnv() Option {
 		if err := env.Env().ReadInto(&cfg); err != nil {
 			return err
 		}
-		l.Formatter = cfg.Formatter()
-		l.Flags = NewFlags(cfg.FlagsOrDefault()...)
-		return nil
+		return applyConfig(l, cfg)
 	}
 }
 
+// applyConfig sets the logger based on a config.
+func applyConfig(l *Logger, cfg Config) error {
+	l.Formatter = cfg.Formatter()
+	l.Flags = NewFlags(cfg.FlagsOrDefault()...)
+	if len(cfg.Sampling.Rules) > 0 {
+		if err := OptSampling(cfg.Sampling)(l); err != nil {
+			return err
+		}
+	}
+	if cfg.Redaction.IsEnabled() {
+		if err := OptRedaction(cfg.Redaction)(l); err != nil {
+			return err
+		}
+	}
+	if cfg.RecentEvents.Enabled {
+		if err := OptRecentEvents(cfg.RecentEvents)(l); err != nil {
+			return err
+		}
+	}
+	if len(cfg.Overrides) > 0 {
+		if err := OptFlagOverridesConfig(cfg.Overrides...)(l); err != nil {
+			return err
+		}
+	}
+	return optConfigOutput(l, cfg)
+}
+
+// optConfigOutput sets the logger output if the config specifies one.
+// The output is closed when the logger is closed.
+func optConfigOutput(l *Logger, cfg Config) error {
+	output, err := cfg.Output()
+	if err != nil {
+		return err
+	}
+	if output != nil {
+		if l.outputCloser != nil {
+			_ = l.outputCloser.Close()
+		}
+		if closer, ok := output.(io.Closer); ok {
+			l.outputCloser = closer
+		}
+		l.Output = NewInterlockedWriter(output)
+	}
+	return nil
+}
+
 /*
 OptOutput sets the output writer for the logger.
 
@@ -43,6 +86,11 @@ To set the output to be both stdout and a file, use the following:
 	combined := io.MultiWriter(os.Stdout, file)
 	log := logger.New(logger.OptOutput(combined))
 
+To write to a file that is rotated by size or time, use a `RotatingFileWriter`:
+
+	file, _ := logger.NewRotatingFileWriter("app.log", logger.OptRotatingFileDaily())
+	log := logger.New(logger.OptOutput(file))
+
 */
 func OptOutput(output io.Writer) Option {
 	return func(l *Logger) error {
@@ -77,6 +125,11 @@ func OptText(opts ...TextOutputFormatterOption) Option {
 	return func(l *Logger) error { l.Formatter = NewTextOutputFormatter(opts...); return nil }
 }
 
+// OptLogfmt sets the output formatter for the logger as logfmt.
+func OptLogfmt(opts ...LogfmtOutputFormatterOption) Option {
+	return func(l *Logger) error { l.Formatter = NewLogfmtOutputFormatter(opts...); return nil }
+}
+
 // OptFormatter sets the output formatter.
 func OptFormatter(formatter WriteFormatter) Option {
 	return func(l *Logger) error { l.Formatter = formatter; return nil }
@@ -106,3 +159,76 @@ func OptEnabled(flags ...string) Option {
 func OptDisabled(flags ...string) Option {
 	return func(l *Logger) error { l.Flags.Disable(flags...); return nil }
 }
+
+// OptSampling samples events with a given config.
+//
+// It enables the `sampling` flag, and once the logger is created with `New`, triggers a `SamplingSummaryEvent`
+// with the number of dropped events per flag every summary interval. The summaries stop when the logger is closed.
+func OptSampling(cfg SamplingConfig) Option {
+	return func(l *Logger) error {
+		if l.Sampler != nil {
+			_ = l.Sampler.Stop()
+		}
+		l.Sampler = NewSampler(cfg)
+		if l.Flags != nil {
+			l.Flags.Enable(FlagSampling)
+		}
+		return nil
+	}
+}
+
+// OptSamplingRules samples events with a given set of rules.
+func OptSamplingRules(rules ...SamplingRule) Option {
+	return OptSampling(SamplingConfig{Rules: rules})
+}
+
+// OptRedaction redacts sensitive values from events with a given config.
+func OptRedaction(cfg RedactionConfig) Option {
+	return func(l *Logger) error {
+		redactor, err := NewRedactor(cfg)
+		if err != nil {
+			return err
+		}
+		l.Redactor = redactor
+		return nil
+	}
+}
+
+// OptRedactor sets the redactor on the logger.
+func OptRedactor(redactor *Redactor) Option {
+	return func(l *Logger) error { l.Redactor = redactor; return nil }
+}
+
+// OptRecentEvents keeps the most recent events per flag in memory with a given config,
+// including events for flags that are not enabled.
+func OptRecentEvents(cfg RecentEventsConfig) Option {
+	return func(l *Logger) error { l.RecentEvents = NewRecentEvents(OptRecentEventsConfig(cfg)); return nil }
+}
+
+// OptFlagOverrides sets flag overrides for events with a given scope path prefix and / or labels.
+// Later overrides take precedence over earlier ones.
+func OptFlagOverrides(overrides ...FlagOverride) Option {
+	return func(l *Logger) error { l.Overrides = overrides; return nil }
+}
+
+// OptFlagOverridesConfig sets flag overrides from config.
+func OptFlagOverridesConfig(configs ...FlagOverrideConfig) Option {
+	return func(l *Logger) error {
+		overrides := make([]FlagOverride, 0, len(configs))
+		for _, cfg := range configs {
+			overrides = append(overrides, NewFlagOverride(cfg))
+		}
+		l.Overrides = overrides
+		return nil
+	}
+}
+
+// OptShutdownTimeout sets the time drainers are given to drain when the logger is closed.
+func OptShutdownTimeout(timeout time.Duration) Option {
+	return func(l *Logger) error { l.ShutdownTimeout = timeout; return nil }
+}
+
+// OptTraceExtractor sets the trace extractor, which adds trace and span ids to event contexts.
+func OptTraceExtractor(extractor TraceExtractor) Option {
+	return func(l *Logger) error { l.TraceExtractor = extractor; return nil }
+}
@@ -0,0 +1,313 @@
+package logger
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/blend/go-sdk/collections"
+	"github.com/blend/go-sdk/ex"
+)
+
+var (
+	_ http.Handler = (*RecentEvents)(nil)
+)
+
+// DefaultRecentEventsSize is the default number of recent events kept per flag.
+const DefaultRecentEventsSize = 100
+
+// ErrRecentEventsParamInvalid is returned if a recent events request parameter is invalid.
+const ErrRecentEventsParamInvalid ex.Class = "logger; invalid recent events parameter"
+
+// Recent events handler request parameters.
+const (
+	RecentEventsParamFlag   = "flag"
+	RecentEventsParamPath   = "path"
+	RecentEventsParamLabel  = "label"
+	RecentEventsParamAfter  = "after"
+	RecentEventsParamBefore = "before"
+	RecentEventsParamSince  = "since"
+	RecentEventsParamLimit  = "limit"
+	RecentEventsParamFormat = "format"
+)
+
+// NewRecentEvents returns a new recent events buffer.
+func NewRecentEvents(options ...RecentEventsOption) *RecentEvents {
+	re := &RecentEvents{
+		Size:   DefaultRecentEventsSize,
+		events: make(map[string]*collections.RingBuffer),
+	}
+	for _, option := range options {
+		option(re)
+	}
+	return re
+}
+
+// RecentEventsOption mutates a recent events buffer.
+type RecentEventsOption func(*RecentEvents)
+
+// OptRecentEventsConfig sets the recent events buffer from a config.
+func OptRecentEventsConfig(cfg RecentEventsConfig) RecentEventsOption {
+	return func(re *RecentEvents) {
+		re.Size = cfg.SizeOrDefault()
+		if len(cfg.Flags) > 0 {
+			re.Flags = NewFlags(cfg.Flags...)
+		}
+	}
+}
+
+// OptRecentEventsSize sets the number of events kept per flag.
+func OptRecentEventsSize(size int) RecentEventsOption {
+	return func(re *RecentEvents) { re.Size = size }
+}
+
+// OptRecentEventsFlags sets the flags events are kept for.
+func OptRecentEventsFlags(flags ...string) RecentEventsOption {
+	return func(re *RecentEvents) { re.Flags = NewFlags(flags...) }
+}
+
+/*
+RecentEvents keeps the most recent events for each flag in memory.
+
+It is set on a logger with `OptRecentEvents`, and is added to for every triggered event,
+including events for flags that are not enabled, so that debug events are available after the fact
+without writing them to the output.
+
+It is also an `http.Handler` that returns the recent events, oldest first, as a json array or as text.
+Events can be filtered with the following parameters:
+
+	flag=debug,info        events with any of the given flags
+	path=api/users         events with a scope path that starts with the given (slash separated) path
+	label=key=value        events with the given label value; can be repeated
+	after=<RFC3339>        events after a given time
+	before=<RFC3339>       events before a given time
+	since=5m               events in the last duration
+	limit=50               the most recent events up to a limit
+	format=text            render events as text instead of json
+
+Events are redacted with the logger redactor when they are read.
+*/
+type RecentEvents struct {
+	Size  int
+	Flags *Flags
+
+	mu     sync.Mutex
+	events map[string]*collections.RingBuffer
+}
+
+// RecentEventsQuery filters recent events.
+type RecentEventsQuery struct {
+	Flags  []string
+	Path   []string
+	Labels Labels
+	After  time.Time
+	Before time.Time
+	Limit  int
+}
+
+// Matches returns if an event matches the query.
+func (q RecentEventsQuery) Matches(ewc EventWithContext) bool {
+	if len(q.Path) > 0 {
+		path := GetPath(ewc.Context)
+		if len(path) < len(q.Path) {
+			return false
+		}
+		for index := range q.Path {
+			if path[index] != q.Path[index] {
+				return false
+			}
+		}
+	}
+	if len(q.Labels) > 0 {
+		labels := GetLabels(ewc.Context)
+		for key, value := range q.Labels {
+			if actual, ok := labels[key]; !ok || actual != value {
+				return false
+			}
+		}
+	}
+	if !q.After.IsZero() || !q.Before.IsZero() {
+		ts := GetEventTimestamp(ewc.Context, ewc.Event)
+		if !q.After.IsZero() && ts.Before(q.After) {
+			return false
+		}
+		if !q.Before.IsZero() && ts.After(q.Before) {
+			return false
+		}
+	}
+	return true
+}
+
+// Add adds an event, dropping the oldest event for its flag if the buffer for the flag is full.
+func (re *RecentEvents) Add(ctx context.Context, e Event) {
+	if e == nil || re.Size <= 0 {
+		return
+	}
+	flag := e.GetFlag()
+	if re.Flags != nil && !re.Flags.IsEnabled(flag) {
+		return
+	}
+	if _, ok := e.(TimestampProvider); !ok && GetTimestamp(ctx).IsZero() && GetTriggerTimestamp(ctx).IsZero() {
+		ctx = WithTriggerTimestamp(ctx, time.Now().UTC())
+	}
+
+	re.mu.Lock()
+	defer re.mu.Unlock()
+	buffer, ok := re.events[flag]
+	if !ok {
+		buffer = collections.NewRingBufferWithCapacity(re.Size)
+		re.events[flag] = buffer
+	}
+	for buffer.Len() >= re.Size {
+		buffer.Dequeue()
+	}
+	buffer.Enqueue(EventWithContext{ctx, e})
+}
+
+// Query returns the events that match a query, oldest first.
+func (re *RecentEvents) Query(query RecentEventsQuery) []EventWithContext {
+	re.mu.Lock()
+	flags := query.Flags
+	if len(flags) == 0 {
+		for flag := range re.events {
+			flags = append(flags, flag)
+		}
+	}
+	var events []EventWithContext
+	for _, flag := range flags {
+		buffer, ok := re.events[flag]
+		if !ok {
+			continue
+		}
+		buffer.Each(func(value interface{}) {
+			if typed, ok := value.(EventWithContext); ok && query.Matches(typed) {
+				events = append(events, typed)
+			}
+		})
+	}
+	re.mu.Unlock()
+
+	sort.SliceStable(events, func(i, j int) bool {
+		return GetEventTimestamp(events[i].Context, events[i].Event).Before(GetEventTimestamp(events[j].Context, events[j].Event))
+	})
+	if query.Limit > 0 && len(events) > query.Limit {
+		events = events[len(events)-query.Limit:]
+	}
+	return events
+}
+
+// Clear removes all recent events.
+func (re *RecentEvents) Clear() {
+	re.mu.Lock()
+	defer re.mu.Unlock()
+	re.events = make(map[string]*collections.RingBuffer)
+}
+
+// ServeHTTP implements http.Handler.
+func (re *RecentEvents) ServeHTTP(rw http.ResponseWriter, req *http.Request) {
+	if req.Method != http.MethodGet && req.Method != http.MethodHead {
+		rw.Header().Set("Allow", strings.Join([]string{http.MethodGet, http.MethodHead}, ", "))
+		writeJSONError(rw, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+	query, err := re.parseQuery(req)
+	if err != nil {
+		writeJSONError(rw, http.StatusBadRequest, ex.ErrMessage(err))
+		return
+	}
+	events := re.Query(query)
+
+	if strings.ToLower(req.URL.Query().Get(RecentEventsParamFormat)) == FormatText {
+		buffer := new(bytes.Buffer)
+		formatter := NewTextOutputFormatter(OptTextNoColor())
+		for _, ewc := range events {
+			ctx, e := re.redact(ewc)
+			_ = formatter.WriteFormat(ctx, buffer, e)
+		}
+		rw.Header().Set("Content-Type", "text/plain; charset=utf-8")
+		rw.WriteHeader(http.StatusOK)
+		_, _ = rw.Write(buffer.Bytes())
+		return
+	}
+
+	formatter := NewJSONOutputFormatter()
+	output := make([]json.RawMessage, 0, len(events))
+	for _, ewc := range events {
+		buffer := new(bytes.Buffer)
+		ctx, e := re.redact(ewc)
+		if err := formatter.WriteFormat(ctx, buffer, e); err != nil {
+			continue
+		}
+		output = append(output, json.RawMessage(bytes.TrimSpace(buffer.Bytes())))
+	}
+	writeJSON(rw, http.StatusOK, output)
+}
+
+//
+// internal helpers
+//
+
+// redact applies the redactor the event was added with, if any.
+func (re *RecentEvents) redact(ewc EventWithContext) (context.Context, Event) {
+	if redactor := GetRedactor(ewc.Context); redactor != nil {
+		return redactor.Apply(ewc.Context, ewc.Event)
+	}
+	return ewc.Context, ewc.Event
+}
+
+func (re *RecentEvents) parseQuery(req *http.Request) (query RecentEventsQuery, err error) {
+	values := req.URL.Query()
+	for _, value := range values[RecentEventsParamFlag] {
+		for _, flag := range strings.Split(value, ",") {
+			if flag = normalizeFlag(flag); flag != "" {
+				query.Flags = append(query.Flags, flag)
+			}
+		}
+	}
+	if path := strings.Trim(values.Get(RecentEventsParamPath), "/"); path != "" {
+		query.Path = strings.Split(path, "/")
+	}
+	for _, label := range values[RecentEventsParamLabel] {
+		parts := strings.SplitN(label, "=", 2)
+		if len(parts) != 2 || parts[0] == "" {
+			return query, recentEventsParamError(RecentEventsParamLabel, label)
+		}
+		if query.Labels == nil {
+			query.Labels = make(Labels)
+		}
+		query.Labels[parts[0]] = parts[1]
+	}
+	if after := values.Get(RecentEventsParamAfter); after != "" {
+		if query.After, err = time.Parse(time.RFC3339Nano, after); err != nil {
+			return query, recentEventsParamError(RecentEventsParamAfter, after)
+		}
+	}
+	if before := values.Get(RecentEventsParamBefore); before != "" {
+		if query.Before, err = time.Parse(time.RFC3339Nano, before); err != nil {
+			return query, recentEventsParamError(RecentEventsParamBefore, before)
+		}
+	}
+	if since := values.Get(RecentEventsParamSince); since != "" {
+		duration, parseErr := time.ParseDuration(since)
+		if parseErr != nil || duration <= 0 {
+			return query, recentEventsParamError(RecentEventsParamSince, since)
+		}
+		query.After = time.Now().UTC().Add(-duration)
+	}
+	if limit := values.Get(RecentEventsParamLimit); limit != "" {
+		if query.Limit, err = strconv.Atoi(limit); err != nil || query.Limit < 0 {
+			return query, recentEventsParamError(RecentEventsParamLimit, limit)
+		}
+	}
+	return query, nil
+}
+
+func recentEventsParamError(param, value string) error {
+	return ex.New(ErrRecentEventsParamInvalid, ex.OptMessagef("invalid %s: %s", param, value))
+}
@@ -0,0 +1,152 @@
+package logger
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/blend/go-sdk/assert"
+	"github.com/blend/go-sdk/env"
+)
+
+func TestRecentEventsAdd(t *testing.T) {
+	assert := assert.New(t)
+
+	re := NewRecentEvents(OptRecentEventsSize(2))
+	for x := 0; x < 3; x++ {
+		re.Add(context.Background(), NewMessageEvent(Debug, fmt.Sprint(x)))
+	}
+	re.Add(context.Background(), NewMessageEvent(Info, "info"))
+
+	events := re.Query(RecentEventsQuery{Flags: []string{Debug}})
+	assert.Len(events, 2)
+	assert.Equal("1", events[0].Event.(MessageEvent).Text)
+	assert.Equal("2", events[1].Event.(MessageEvent).Text)
+	assert.False(GetEventTimestamp(events[0].Context, events[0].Event).IsZero())
+
+	assert.Len(re.Query(RecentEventsQuery{}), 3)
+	assert.Len(re.Query(RecentEventsQuery{Limit: 1}), 1)
+
+	re.Clear()
+	assert.Empty(re.Query(RecentEventsQuery{}))
+
+	re = NewRecentEvents(OptRecentEventsFlags(Debug))
+	re.Add(context.Background(), NewMessageEvent(Debug, "debug"))
+	re.Add(context.Background(), NewMessageEvent(Info, "info"))
+	assert.Len(re.Query(RecentEventsQuery{}), 1)
+}
+
+func TestRecentEventsQuery(t *testing.T) {
+	assert := assert.New(t)
+
+	ts := time.Date(2020, 01, 02, 03, 04, 05, 0, time.UTC)
+	re := NewRecentEvents()
+	re.Add(WithPath(WithTimestamp(context.Background(), ts), "api", "users"), NewMessageEvent(Info, "users"))
+	re.Add(WithPath(WithTimestamp(context.Background(), ts.Add(time.Minute)), "api"), NewMessageEvent(Debug, "api"))
+	re.Add(WithLabels(WithTimestamp(context.Background(), ts.Add(2*time.Minute)), Labels{"env": "prod"}), NewMessageEvent(Info, "labeled"))
+
+	texts := func(events []EventWithContext) (output []string) {
+		for _, e := range events {
+			output = append(output, e.Event.(MessageEvent).Text)
+		}
+		return
+	}
+
+	assert.Equal([]string{"users", "api", "labeled"}, texts(re.Query(RecentEventsQuery{})))
+	assert.Equal([]string{"users", "api"}, texts(re.Query(RecentEventsQuery{Path: []string{"api"}})))
+	assert.Equal([]string{"users"}, texts(re.Query(RecentEventsQuery{Path: []string{"api", "users"}})))
+	assert.Equal([]string{"labeled"}, texts(re.Query(RecentEventsQuery{Labels: Labels{"env": "prod"}})))
+	assert.Empty(re.Query(RecentEventsQuery{Labels: Labels{"env": "dev"}}))
+	assert.Equal([]string{"api", "labeled"}, texts(re.Query(RecentEventsQuery{After: ts.Add(time.Second)})))
+	assert.Equal([]string{"users", "api"}, texts(re.Query(RecentEventsQuery{Before: ts.Add(time.Minute)})))
+	assert.Equal([]string{"users", "labeled"}, texts(re.Query(RecentEventsQuery{Flags: []string{Info}})))
+}
+
+func TestRecentEventsLogger(t *testing.T) {
+	assert := assert.New(t)
+
+	log := MustNew(
+		OptOutput(nil),
+		OptEnabled(Info),
+		OptRecentEvents(RecentEventsConfig{Size: 10}),
+		OptRedaction(RedactionConfig{Builtins: []string{RedactBearerToken}}),
+	)
+	defer log.Close()
+
+	log.Debugf("disabled debug with Bearer abcd")
+	log.WithLabels(Labels{"password": "hunter2"}).Infof("enabled info")
+
+	req := httptest.NewRequest(http.MethodGet, "/debug/log/recent?flag=debug", nil)
+	res := httptest.NewRecorder()
+	log.RecentEvents.ServeHTTP(res, req)
+	assert.Equal(http.StatusOK, res.Code)
+
+	var events []map[string]interface{}
+	assert.Nil(json.Unmarshal(res.Body.Bytes(), &events))
+	assert.Len(events, 1)
+	assert.Equal(Debug, events[0][FieldFlag])
+	assert.Equal("disabled debug with [REDACTED]", events[0][FieldText])
+
+	req = httptest.NewRequest(http.MethodGet, "/debug/log/recent?format=text&label=password=hunter2", nil)
+	res = httptest.NewRecorder()
+	log.RecentEvents.ServeHTTP(res, req)
+	assert.Equal(http.StatusOK, res.Code)
+	assert.Contains(res.Body.String(), "[info] enabled info")
+	assert.Contains(res.Body.String(), "password=[REDACTED]")
+	assert.NotContains(res.Body.String(), "disabled debug")
+}
+
+func TestRecentEventsHandlerPath(t *testing.T) {
+	assert := assert.New(t)
+
+	re := NewRecentEvents()
+	re.Add(WithPath(context.Background(), "api", "users"), NewMessageEvent(Info, "users"))
+	re.Add(WithPath(context.Background(), "api"), NewMessageEvent(Info, "api"))
+
+	res := httptest.NewRecorder()
+	re.ServeHTTP(res, httptest.NewRequest(http.MethodGet, "/?format=text&path=api/users", nil))
+	assert.Equal(http.StatusOK, res.Code)
+	assert.Contains(res.Body.String(), "users")
+	assert.NotContains(res.Body.String(), "[info] api")
+}
+
+func TestRecentEventsHandlerInvalid(t *testing.T) {
+	assert := assert.New(t)
+
+	re := NewRecentEvents()
+	for _, query := range []string{"label=nope", "after=yesterday", "before=tomorrow", "since=-1m", "limit=some"} {
+		res := httptest.NewRecorder()
+		re.ServeHTTP(res, httptest.NewRequest(http.MethodGet, "/?"+query, nil))
+		assert.Equal(http.StatusBadRequest, res.Code, query)
+	}
+
+	res := httptest.NewRecorder()
+	re.ServeHTTP(res, httptest.NewRequest(http.MethodPost, "/", nil))
+	assert.Equal(http.StatusMethodNotAllowed, res.Code)
+
+	res = httptest.NewRecorder()
+	re.ServeHTTP(res, httptest.NewRequest(http.MethodGet, "/?since=1h", nil))
+	assert.Equal(http.StatusOK, res.Code)
+	assert.Equal("[]\n", res.Body.String())
+}
+
+func TestConfigRecentEvents(t *testing.T) {
+	assert := assert.New(t)
+
+	defer env.Restore()
+	env.Env().Set("LOG_RECENT_EVENTS", "true")
+	env.Env().Set("LOG_RECENT_EVENTS_SIZE", "5")
+	env.Env().Set("LOG_RECENT_EVENTS_FLAGS", "debug,info")
+
+	log := None()
+	assert.Nil(OptConfigFromEnv()(log))
+	assert.NotNil(log.RecentEvents)
+	assert.Equal(5, log.RecentEvents.Size)
+	assert.True(log.RecentEvents.Flags.IsEnabled(Debug))
+	assert.False(log.RecentEvents.Flags.IsEnabled(Error))
+	assert.Equal(DefaultRecentEventsSize, RecentEventsConfig{}.SizeOrDefault())
+}
@@ -0,0 +1,338 @@
+package logger
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/url"
+	"path"
+	"regexp"
+	"strings"
+
+	"github.com/blend/go-sdk/ex"
+)
+
+// DefaultRedactReplacement is the default value redacted values are replaced with.
+const DefaultRedactReplacement = "[REDACTED]"
+
+// Redaction errors.
+const (
+	// ErrRedactPatternInvalid is returned by `NewRedactor` if a pattern does not compile.
+	ErrRedactPatternInvalid ex.Class = "logger; invalid redaction pattern"
+	// ErrRedactBuiltinUnknown is returned by `NewRedactor` if a builtin pattern name is unknown.
+	ErrRedactBuiltinUnknown ex.Class = "logger; unknown builtin redaction pattern"
+)
+
+// Builtin redaction pattern names.
+const (
+	RedactCreditCard  = "credit_card"
+	RedactBearerToken = "bearer_token"
+	RedactBasicAuth   = "basic_auth"
+	RedactAWSKeyID    = "aws_access_key_id"
+)
+
+// RedactBuiltins are value patterns that can be enabled by name in a `RedactionConfig`.
+var RedactBuiltins = map[string]*regexp.Regexp{
+	RedactCreditCard:  regexp.MustCompile(`\b(?:4\d{3}|5[1-5]\d{2}|2[2-7]\d{2}|6011)(?:[ -]?\d{4}){3}\b|\b3[47]\d{2}[ -]?\d{6}[ -]?\d{5}\b`),
+	RedactBearerToken: regexp.MustCompile(`(?i)\bbearer\s+[a-z0-9\-._~+/]+=*`),
+	RedactBasicAuth:   regexp.MustCompile(`(?i)\bbasic\s+[a-z0-9+/]+=*`),
+	RedactAWSKeyID:    regexp.MustCompile(`\b(?:AKIA|ASIA)[A-Z0-9]{16}\b`),
+}
+
+// DefaultRedactKeys are key names that are redacted by default.
+var DefaultRedactKeys = []string{
+	"authorization",
+	"proxy-authorization",
+	"cookie",
+	"set-cookie",
+	"password",
+	"secret",
+	"token",
+	"api_key",
+	"x-api-key",
+}
+
+// Redactable is an event that can return a copy of itself with sensitive values redacted.
+//
+// Events that implement it are redacted before they are passed to listeners or written,
+// so listeners that type assert the event (e.g. `NewErrorEventListener`) receive the redacted copy.
+type Redactable interface {
+	Redact(*Redactor) Event
+}
+
+// NewRedactor returns a new redactor from a config.
+func NewRedactor(cfg RedactionConfig) (*Redactor, error) {
+	r := &Redactor{
+		Replacement: cfg.ReplacementOrDefault(),
+	}
+	for _, key := range cfg.KeysOrDefault() {
+		r.Keys = append(r.Keys, strings.ToLower(strings.TrimSpace(key)))
+	}
+	for _, glob := range cfg.Globs {
+		if _, err := path.Match(glob, ""); err != nil {
+			return nil, ex.New(ErrRedactPatternInvalid, ex.OptMessagef("glob: %s", glob))
+		}
+		r.Globs = append(r.Globs, strings.ToLower(strings.TrimSpace(glob)))
+	}
+	for _, name := range cfg.Builtins {
+		pattern, ok := RedactBuiltins[strings.ToLower(strings.TrimSpace(name))]
+		if !ok {
+			return nil, ex.New(ErrRedactBuiltinUnknown, ex.OptMessagef("builtin: %s", name))
+		}
+		r.Patterns = append(r.Patterns, pattern)
+	}
+	for _, expr := range cfg.Patterns {
+		pattern, err := regexp.Compile(expr)
+		if err != nil {
+			return nil, ex.New(ErrRedactPatternInvalid, ex.OptMessagef("pattern: %s", expr), ex.OptInner(err))
+		}
+		r.Patterns = append(r.Patterns, pattern)
+	}
+	return r, nil
+}
+
+// Redactor redacts sensitive values from events, labels and annotations.
+//
+// Values are redacted if their key matches one of `Keys` (caselessly) or `Globs`,
+// and any part of a string value that matches one of `Patterns` is replaced.
+type Redactor struct {
+	// Keys are key names whose values are redacted.
+	Keys []string
+	// Globs are key name globs (see `path.Match`) whose values are redacted.
+	Globs []string
+	// Patterns are value patterns that are replaced wherever they appear.
+	Patterns []*regexp.Regexp
+	// Replacement is the value redacted values are replaced with.
+	Replacement string
+}
+
+// Apply redacts the labels and annotations on a context, and the event if it is `Redactable`.
+// The returned context also holds the redactor so formatters can redact event output.
+func (r *Redactor) Apply(ctx context.Context, e Event) (context.Context, Event) {
+	ctx = WithRedactor(ctx, r)
+	if labels := GetLabels(ctx); len(labels) > 0 {
+		ctx = WithLabels(ctx, r.Labels(labels))
+	}
+	if annotations := GetAnnotations(ctx); len(annotations) > 0 {
+		ctx = WithAnnotations(ctx, r.Annotations(annotations))
+	}
+	if typed, ok := e.(Redactable); ok {
+		e = typed.Redact(r)
+	}
+	return ctx, e
+}
+
+// IsRedactedKey returns if values for a key should be redacted.
+func (r *Redactor) IsRedactedKey(key string) bool {
+	key = strings.ToLower(key)
+	for _, redacted := range r.Keys {
+		if key == redacted {
+			return true
+		}
+	}
+	for _, glob := range r.Globs {
+		if matched, _ := path.Match(glob, key); matched {
+			return true
+		}
+	}
+	return false
+}
+
+// String replaces any parts of a value that match the redaction patterns.
+func (r *Redactor) String(value string) string {
+	for _, pattern := range r.Patterns {
+		value = pattern.ReplaceAllLiteralString(value, r.replacement())
+	}
+	return value
+}
+
+// Labels returns a redacted copy of a set of labels.
+func (r *Redactor) Labels(labels Labels) Labels {
+	if labels == nil {
+		return nil
+	}
+	output := make(Labels, len(labels))
+	for key, value := range labels {
+		if r.IsRedactedKey(key) {
+			output[key] = r.replacement()
+		} else {
+			output[key] = r.String(value)
+		}
+	}
+	return output
+}
+
+// Annotations returns a redacted copy of a set of annotations.
+func (r *Redactor) Annotations(annotations Annotations) Annotations {
+	if annotations == nil {
+		return nil
+	}
+	return Annotations(r.Fields(annotations))
+}
+
+// Fields returns a redacted copy of a set of fields, e.g. the output of `Decompose()`.
+// Nested maps and slices are redacted recursively.
+func (r *Redactor) Fields(fields map[string]interface{}) map[string]interface{} {
+	if fields == nil {
+		return nil
+	}
+	output := make(map[string]interface{}, len(fields))
+	for key, value := range fields {
+		if r.IsRedactedKey(key) {
+			output[key] = r.replacement()
+		} else {
+			output[key] = r.value(value)
+		}
+	}
+	return output
+}
+
+// Header returns a redacted copy of a set of http headers.
+func (r *Redactor) Header(header http.Header) http.Header {
+	if header == nil {
+		return nil
+	}
+	output := make(http.Header, len(header))
+	for key, values := range header {
+		redacted := make([]string, len(values))
+		for index, value := range values {
+			if r.IsRedactedKey(key) {
+				redacted[index] = r.replacement()
+			} else {
+				redacted[index] = r.String(value)
+			}
+		}
+		output[key] = redacted
+	}
+	return output
+}
+
+// URL returns a redacted copy of a url; the password and redacted query values are replaced.
+func (r *Redactor) URL(u *url.URL) *url.URL {
+	if u == nil {
+		return nil
+	}
+	output := *u
+	if u.User != nil {
+		if _, hasPassword := u.User.Password(); hasPassword {
+			output.User = url.UserPassword(u.User.Username(), r.replacement())
+		}
+	}
+	if u.RawQuery != "" {
+		query := u.Query()
+		for key, values := range query {
+			for index, value := range values {
+				if r.IsRedactedKey(key) {
+					values[index] = r.replacement()
+				} else {
+					values[index] = r.String(value)
+				}
+			}
+			query[key] = values
+		}
+		output.RawQuery = query.Encode()
+	}
+	return &output
+}
+
+// Request returns a shallow copy of a request with its headers and url redacted.
+// The body is not read or redacted.
+func (r *Redactor) Request(req *http.Request) *http.Request {
+	if req == nil {
+		return nil
+	}
+	output := *req
+	output.Header = r.Header(req.Header)
+	output.URL = r.URL(req.URL)
+	return &output
+}
+
+// Error returns an error with its message redacted.
+// For exceptions, the class and inner error are redacted as well, since wrapping an error
+// with `ex.New(err)` makes it the class.
+// If nothing in the message is redacted, the original error is returned.
+func (r *Redactor) Error(err error) error {
+	if err == nil {
+		return nil
+	}
+	if typed := ex.As(err); typed != nil {
+		redacted := *typed
+		redacted.Class = r.errorClass(typed.Class)
+		redacted.Message = r.String(typed.Message)
+		redacted.Inner = r.Error(typed.Inner)
+		if redacted.Class == typed.Class && redacted.Message == typed.Message && redacted.Inner == typed.Inner {
+			return err
+		}
+		return &redacted
+	}
+	if message := r.String(err.Error()); message != err.Error() {
+		return errors.New(message)
+	}
+	return err
+}
+
+// errorClass returns an exception class redacted.
+// String classes are kept as `ex.Class` so they can still be matched with `ex.Is`.
+func (r *Redactor) errorClass(class error) error {
+	if typed, ok := class.(ex.Class); ok {
+		return ex.Class(r.String(string(typed)))
+	}
+	return r.Error(class)
+}
+
+func (r *Redactor) value(value interface{}) interface{} {
+	switch typed := value.(type) {
+	case string:
+		return r.String(typed)
+	case error:
+		return r.Error(typed)
+	case Labels:
+		return r.Labels(typed)
+	case Annotations:
+		return r.Annotations(typed)
+	case map[string]interface{}:
+		return r.Fields(typed)
+	case map[string]string:
+		return map[string]string(r.Labels(Labels(typed)))
+	case http.Header:
+		return r.Header(typed)
+	case []string:
+		output := make([]string, len(typed))
+		for index, value := range typed {
+			output[index] = r.String(value)
+		}
+		return output
+	case []interface{}:
+		output := make([]interface{}, len(typed))
+		for index, value := range typed {
+			output[index] = r.value(value)
+		}
+		return output
+	default:
+		return value
+	}
+}
+
+func (r *Redactor) replacement() string {
+	if r.Replacement != "" {
+		return r.Replacement
+	}
+	return DefaultRedactReplacement
+}
+
+type redactorKey struct{}
+
+// WithRedactor returns a new context with a given redactor.
+func WithRedactor(ctx context.Context, r *Redactor) context.Context {
+	return context.WithValue(ctx, redactorKey{}, r)
+}
+
+// GetRedactor gets a redactor off a context.
+func GetRedactor(ctx context.Context) *Redactor {
+	if raw := ctx.Value(redactorKey{}); raw != nil {
+		if typed, ok := raw.(*Redactor); ok {
+			return typed
+		}
+	}
+	return nil
+}
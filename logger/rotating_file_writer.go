@@ -0,0 +1,503 @@
+package logger
+
+import (
+	"compress/gzip"
+	"io"
+	"io/ioutil"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/blend/go-sdk/ex"
+)
+
+var (
+	_ io.WriteCloser = (*RotatingFileWriter)(nil)
+)
+
+// Rotation intervals.
+const (
+	RotationNone   = ""
+	RotationHourly = "hourly"
+	RotationDaily  = "daily"
+)
+
+// Rotating file writer defaults.
+const (
+	// DefaultRotatingFileMode is the file mode log files are created with.
+	DefaultRotatingFileMode os.FileMode = 0644
+	// RotatingFileBackupTimeFormat is the timestamp format used in backup file names.
+	RotatingFileBackupTimeFormat = "2006-01-02T15-04-05.000"
+	// RotatingFileCompressedExtension is the extension added to compressed backups.
+	RotatingFileCompressedExtension = ".gz"
+)
+
+// ErrRotatingFileWriterClosed is returned when writing to a rotating file writer that has been closed.
+const ErrRotatingFileWriterClosed ex.Class = "logger; rotating file writer is closed"
+
+// NewRotatingFileWriter opens (or creates) a log file at a given path that is rotated
+// based on the given options.
+func NewRotatingFileWriter(path string, options ...RotatingFileWriterOption) (*RotatingFileWriter, error) {
+	rfw := &RotatingFileWriter{
+		Path: path,
+		Mode: DefaultRotatingFileMode,
+	}
+	for _, option := range options {
+		option(rfw)
+	}
+	rfw.mu.Lock()
+	defer rfw.mu.Unlock()
+	if err := rfw.openExistingOrNew(); err != nil {
+		return nil, err
+	}
+	if len(rfw.ReopenSignals) > 0 {
+		rfw.notifyReopen()
+	}
+	return rfw, nil
+}
+
+// RotatingFileWriterOption mutates a rotating file writer.
+type RotatingFileWriterOption func(*RotatingFileWriter)
+
+// OptRotatingFileConfig sets the rotating file writer fields from a config.
+func OptRotatingFileConfig(cfg FileConfig) RotatingFileWriterOption {
+	return func(rfw *RotatingFileWriter) {
+		rfw.MaxSizeBytes = cfg.MaxSizeBytes
+		rfw.Rotation = cfg.Rotation
+		rfw.MaxBackups = cfg.MaxBackups
+		rfw.MaxAge = cfg.MaxAge
+		rfw.Compress = cfg.Compress
+		if cfg.ReopenOnSignal {
+			rfw.ReopenSignals = []os.Signal{syscall.SIGHUP}
+		}
+	}
+}
+
+// OptRotatingFileMaxSizeBytes sets the size at which the file is rotated.
+func OptRotatingFileMaxSizeBytes(maxSizeBytes int64) RotatingFileWriterOption {
+	return func(rfw *RotatingFileWriter) { rfw.MaxSizeBytes = maxSizeBytes }
+}
+
+// OptRotatingFileDaily rotates the file at the start of every (local) day.
+func OptRotatingFileDaily() RotatingFileWriterOption {
+	return func(rfw *RotatingFileWriter) { rfw.Rotation = RotationDaily }
+}
+
+// OptRotatingFileHourly rotates the file at the start of every hour.
+func OptRotatingFileHourly() RotatingFileWriterOption {
+	return func(rfw *RotatingFileWriter) { rfw.Rotation = RotationHourly }
+}
+
+// OptRotatingFileMaxBackups sets the maximum number of rotated files to keep.
+func OptRotatingFileMaxBackups(maxBackups int) RotatingFileWriterOption {
+	return func(rfw *RotatingFileWriter) { rfw.MaxBackups = maxBackups }
+}
+
+// OptRotatingFileMaxAge sets the maximum age of rotated files to keep.
+func OptRotatingFileMaxAge(maxAge time.Duration) RotatingFileWriterOption {
+	return func(rfw *RotatingFileWriter) { rfw.MaxAge = maxAge }
+}
+
+// OptRotatingFileCompress gzips rotated files.
+func OptRotatingFileCompress() RotatingFileWriterOption {
+	return func(rfw *RotatingFileWriter) { rfw.Compress = true }
+}
+
+// OptRotatingFileMode sets the file mode new log files are created with.
+func OptRotatingFileMode(mode os.FileMode) RotatingFileWriterOption {
+	return func(rfw *RotatingFileWriter) { rfw.Mode = mode }
+}
+
+// OptRotatingFileReopenOnSignal reopens the file when the process receives one of the given signals.
+// If no signals are given, it defaults to SIGHUP.
+//
+// This lets external tools like `logrotate` move the file out from under the process.
+func OptRotatingFileReopenOnSignal(signals ...os.Signal) RotatingFileWriterOption {
+	return func(rfw *RotatingFileWriter) {
+		if len(signals) == 0 {
+			signals = []os.Signal{syscall.SIGHUP}
+		}
+		rfw.ReopenSignals = signals
+	}
+}
+
+/*
+RotatingFileWriter is an io.WriteCloser that writes to a file, rotating it
+when it reaches a given size or when a time interval (hourly or daily) elapses.
+
+Rotated files are renamed with a timestamp suffix, e.g. `app.log` becomes
+`app-2020-01-02T15-04-05.000.log`, and optionally gzipped. Compression and removal of
+old backups happen in the background, so `Write` never blocks on them.
+
+It is safe for concurrent use, and can be wrapped in an `InterlockedWriter`:
+
+	file, err := logger.NewRotatingFileWriter("/var/log/app.log",
+		logger.OptRotatingFileMaxSizeBytes(100<<20),
+		logger.OptRotatingFileDaily(),
+		logger.OptRotatingFileMaxBackups(7),
+		logger.OptRotatingFileCompress(),
+	)
+	if err != nil {
+		return err
+	}
+	log := logger.MustNew(logger.OptOutput(file))
+	defer file.Close()
+*/
+type RotatingFileWriter struct {
+	// Path is the path to the current log file.
+	Path string
+	// Mode is the file mode new files are created with.
+	Mode os.FileMode
+	// MaxSizeBytes is the size at which the file is rotated; zero disables size based rotation.
+	MaxSizeBytes int64
+	// Rotation is the time based rotation interval, one of `RotationHourly` or `RotationDaily`.
+	Rotation string
+	// MaxBackups is the maximum number of rotated files to keep; zero keeps all of them.
+	MaxBackups int
+	// MaxAge is the maximum age of rotated files to keep; zero keeps all of them.
+	MaxAge time.Duration
+	// Compress gzips rotated files.
+	Compress bool
+	// ReopenSignals are process signals that cause the file to be reopened.
+	ReopenSignals []os.Signal
+
+	mu      sync.Mutex
+	closed  bool
+	file    *os.File
+	size    int64
+	period  time.Time
+	signals chan os.Signal
+	done    chan struct{}
+	cleanup sync.Mutex
+	pending sync.WaitGroup
+	now     func() time.Time
+}
+
+// Write implements io.Writer.
+// It rotates the file first if the write would exceed the max size or if the rotation interval has elapsed.
+// Writes after the writer is closed return `ErrRotatingFileWriterClosed`.
+func (rfw *RotatingFileWriter) Write(contents []byte) (int, error) {
+	rfw.mu.Lock()
+	defer rfw.mu.Unlock()
+
+	if rfw.closed {
+		return 0, ex.New(ErrRotatingFileWriterClosed)
+	}
+	if rfw.file == nil {
+		if err := rfw.openExistingOrNew(); err != nil {
+			return 0, err
+		}
+	}
+	if rfw.shouldRotate(int64(len(contents))) {
+		if err := rfw.rotate(); err != nil {
+			return 0, err
+		}
+	}
+	written, err := rfw.file.Write(contents)
+	rfw.size += int64(written)
+	if err != nil {
+		return written, ex.New(err)
+	}
+	return written, nil
+}
+
+// Rotate closes the current file, moves it aside to a backup, and opens a new file.
+func (rfw *RotatingFileWriter) Rotate() error {
+	rfw.mu.Lock()
+	defer rfw.mu.Unlock()
+	if rfw.closed {
+		return ex.New(ErrRotatingFileWriterClosed)
+	}
+	return rfw.rotate()
+}
+
+// Reopen closes and reopens the file at `Path`, creating it if it no longer exists.
+// It is used after the file has been moved by an external tool.
+func (rfw *RotatingFileWriter) Reopen() error {
+	rfw.mu.Lock()
+	defer rfw.mu.Unlock()
+	if rfw.closed {
+		return ex.New(ErrRotatingFileWriterClosed)
+	}
+	if err := rfw.closeFile(); err != nil {
+		return err
+	}
+	return rfw.openExistingOrNew()
+}
+
+// Close stops listening for signals, closes the file,
+// and waits for any background compression and cleanup to finish.
+func (rfw *RotatingFileWriter) Close() error {
+	rfw.mu.Lock()
+	rfw.closed = true
+	if rfw.signals != nil {
+		signal.Stop(rfw.signals)
+		close(rfw.done)
+		rfw.signals = nil
+	}
+	err := rfw.closeFile()
+	rfw.mu.Unlock()
+
+	rfw.pending.Wait()
+	return err
+}
+
+// Backups returns the rotated files for the writer, newest first.
+func (rfw *RotatingFileWriter) Backups() ([]string, error) {
+	backups, err := rfw.backups()
+	if err != nil {
+		return nil, err
+	}
+	output := make([]string, 0, len(backups))
+	for _, backup := range backups {
+		output = append(output, backup.path)
+	}
+	return output, nil
+}
+
+//
+// internal helpers; these all assume the mutex is held.
+//
+
+func (rfw *RotatingFileWriter) shouldRotate(incoming int64) bool {
+	if rfw.MaxSizeBytes > 0 && rfw.size > 0 && rfw.size+incoming > rfw.MaxSizeBytes {
+		return true
+	}
+	if rfw.Rotation != RotationNone && !rfw.periodOf(rfw.nowUTC()).Equal(rfw.period) {
+		return true
+	}
+	return false
+}
+
+func (rfw *RotatingFileWriter) openExistingOrNew() error {
+	if err := os.MkdirAll(filepath.Dir(rfw.Path), 0755); err != nil {
+		return ex.New(err)
+	}
+	info, err := os.Stat(rfw.Path)
+	if os.IsNotExist(err) {
+		return rfw.openNew()
+	}
+	if err != nil {
+		return ex.New(err)
+	}
+	if rfw.Rotation != RotationNone && !rfw.periodOf(info.ModTime()).Equal(rfw.periodOf(rfw.nowUTC())) {
+		return rfw.rotate()
+	}
+	file, err := os.OpenFile(rfw.Path, os.O_APPEND|os.O_WRONLY, rfw.modeOrDefault())
+	if err != nil {
+		return ex.New(err)
+	}
+	rfw.file = file
+	rfw.size = info.Size()
+	rfw.period = rfw.periodOf(rfw.nowUTC())
+	return nil
+}
+
+func (rfw *RotatingFileWriter) openNew() error {
+	file, err := os.OpenFile(rfw.Path, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, rfw.modeOrDefault())
+	if err != nil {
+		return ex.New(err)
+	}
+	rfw.file = file
+	rfw.size = 0
+	rfw.period = rfw.periodOf(rfw.nowUTC())
+	return nil
+}
+
+func (rfw *RotatingFileWriter) rotate() error {
+	if err := rfw.closeFile(); err != nil {
+		return err
+	}
+	if _, err := os.Stat(rfw.Path); err == nil {
+		backup := rfw.backupName(rfw.nowUTC())
+		if err := os.Rename(rfw.Path, backup); err != nil {
+			return ex.New(err)
+		}
+		rfw.pending.Add(1)
+		go rfw.compressAndPrune(backup)
+	}
+	return rfw.openNew()
+}
+
+func (rfw *RotatingFileWriter) closeFile() error {
+	if rfw.file == nil {
+		return nil
+	}
+	err := rfw.file.Close()
+	rfw.file = nil
+	if err != nil {
+		return ex.New(err)
+	}
+	return nil
+}
+
+func (rfw *RotatingFileWriter) notifyReopen() {
+	rfw.signals = make(chan os.Signal, 1)
+	rfw.done = make(chan struct{})
+	signal.Notify(rfw.signals, rfw.ReopenSignals...)
+	go func(signals chan os.Signal, done chan struct{}) {
+		for {
+			select {
+			case <-done:
+				return
+			case <-signals:
+				_ = rfw.Reopen()
+			}
+		}
+	}(rfw.signals, rfw.done)
+}
+
+// periodOf returns the start of the rotation interval a given time falls in.
+func (rfw *RotatingFileWriter) periodOf(t time.Time) time.Time {
+	t = t.Local()
+	switch rfw.Rotation {
+	case RotationHourly:
+		return time.Date(t.Year(), t.Month(), t.Day(), t.Hour(), 0, 0, 0, t.Location())
+	case RotationDaily:
+		return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
+	default:
+		return time.Time{}
+	}
+}
+
+func (rfw *RotatingFileWriter) backupName(t time.Time) string {
+	dir, prefix, ext := rfw.nameParts()
+	name := filepath.Join(dir, prefix+"-"+t.Format(RotatingFileBackupTimeFormat)+ext)
+	// avoid clobbering a backup if we rotate more than once a millisecond.
+	for index := 1; ; index++ {
+		if _, err := os.Stat(name); os.IsNotExist(err) {
+			if _, err := os.Stat(name + RotatingFileCompressedExtension); os.IsNotExist(err) {
+				return name
+			}
+		}
+		name = filepath.Join(dir, prefix+"-"+t.Add(time.Duration(index)*time.Millisecond).Format(RotatingFileBackupTimeFormat)+ext)
+	}
+}
+
+func (rfw *RotatingFileWriter) nameParts() (dir, prefix, ext string) {
+	dir = filepath.Dir(rfw.Path)
+	base := filepath.Base(rfw.Path)
+	ext = filepath.Ext(base)
+	prefix = strings.TrimSuffix(base, ext)
+	return
+}
+
+func (rfw *RotatingFileWriter) modeOrDefault() os.FileMode {
+	if rfw.Mode != 0 {
+		return rfw.Mode
+	}
+	return DefaultRotatingFileMode
+}
+
+func (rfw *RotatingFileWriter) nowUTC() time.Time {
+	if rfw.now != nil {
+		return rfw.now().UTC()
+	}
+	return time.Now().UTC()
+}
+
+//
+// background work; these do not hold the write mutex.
+//
+
+// compressAndPrune compresses a backup (if enabled) and removes backups
+// past the max count or max age. Runs are serialized with the cleanup mutex.
+func (rfw *RotatingFileWriter) compressAndPrune(backup string) {
+	defer rfw.pending.Done()
+	rfw.cleanup.Lock()
+	defer rfw.cleanup.Unlock()
+
+	if rfw.Compress {
+		_ = compressFile(backup, rfw.modeOrDefault())
+	}
+	_ = rfw.prune()
+}
+
+func (rfw *RotatingFileWriter) prune() error {
+	backups, err := rfw.backups()
+	if err != nil {
+		return err
+	}
+	cutoff := rfw.nowUTC().Add(-rfw.MaxAge)
+	for index, backup := range backups {
+		if (rfw.MaxBackups > 0 && index >= rfw.MaxBackups) || (rfw.MaxAge > 0 && backup.timestamp.Before(cutoff)) {
+			if err := os.Remove(backup.path); err != nil && !os.IsNotExist(err) {
+				return ex.New(err)
+			}
+		}
+	}
+	return nil
+}
+
+type rotatedFile struct {
+	path      string
+	timestamp time.Time
+}
+
+// backups returns the rotated files, newest first.
+func (rfw *RotatingFileWriter) backups() ([]rotatedFile, error) {
+	dir, prefix, ext := rfw.nameParts()
+	entries, err := ioutil.ReadDir(dir)
+	if err != nil {
+		return nil, ex.New(err)
+	}
+	var output []rotatedFile
+	for _, entry := range entries {
+		if entry.IsDir() {
+			continue
+		}
+		name := strings.TrimSuffix(entry.Name(), RotatingFileCompressedExtension)
+		if !strings.HasPrefix(name, prefix+"-") || !strings.HasSuffix(name, ext) {
+			continue
+		}
+		timestamp, err := time.Parse(RotatingFileBackupTimeFormat, strings.TrimSuffix(strings.TrimPrefix(name, prefix+"-"), ext))
+		if err != nil {
+			continue
+		}
+		output = append(output, rotatedFile{path: filepath.Join(dir, entry.Name()), timestamp: timestamp})
+	}
+	sort.Slice(output, func(i, j int) bool {
+		return output[i].timestamp.After(output[j].timestamp)
+	})
+	return output, nil
+}
+
+// compressFile gzips a file to `<path>.gz` and removes the original.
+func compressFile(path string, mode os.FileMode) (err error) {
+	source, err := os.Open(path)
+	if err != nil {
+		return ex.New(err)
+	}
+	defer source.Close()
+
+	destination, err := os.OpenFile(path+RotatingFileCompressedExtension, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, mode)
+	if err != nil {
+		return ex.New(err)
+	}
+	defer func() {
+		if err != nil {
+			_ = destination.Close()
+			_ = os.Remove(path + RotatingFileCompressedExtension)
+		}
+	}()
+
+	gz := gzip.NewWriter(destination)
+	if _, err = io.Copy(gz, source); err != nil {
+		return ex.New(err)
+	}
+	if err = gz.Close(); err != nil {
+		return ex.New(err)
+	}
+	if err = destination.Close(); err != nil {
+		return ex.New(err)
+	}
+	if err = os.Remove(path); err != nil {
+		return ex.New(err)
+	}
+	return nil
+}
@@ -0,0 +1,318 @@
+package logger
+
+import (
+	"compress/gzip"
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/blend/go-sdk/assert"
+	"github.com/blend/go-sdk/ex"
+)
+
+func rotatingFileTempDir(t *testing.T) (string, func()) {
+	dir, err := ioutil.TempDir("", "rotating_file_writer")
+	if err != nil {
+		t.Fatal(err)
+	}
+	return dir, func() { os.RemoveAll(dir) }
+}
+
+func readFileString(path string) string {
+	contents, _ := ioutil.ReadFile(path)
+	return string(contents)
+}
+
+func TestRotatingFileWriterWrite(t *testing.T) {
+	assert := assert.New(t)
+
+	dir, cleanup := rotatingFileTempDir(t)
+	defer cleanup()
+
+	path := filepath.Join(dir, "app.log")
+	rfw, err := NewRotatingFileWriter(path)
+	assert.Nil(err)
+
+	_, err = rfw.Write([]byte("foo\n"))
+	assert.Nil(err)
+	_, err = rfw.Write([]byte("bar\n"))
+	assert.Nil(err)
+	assert.Nil(rfw.Close())
+
+	assert.Equal("foo\nbar\n", readFileString(path))
+
+	// reopening appends to the existing file.
+	rfw, err = NewRotatingFileWriter(path)
+	assert.Nil(err)
+	_, err = rfw.Write([]byte("baz\n"))
+	assert.Nil(err)
+	assert.Nil(rfw.Close())
+	assert.Equal("foo\nbar\nbaz\n", readFileString(path))
+}
+
+func TestRotatingFileWriterWriteAfterClose(t *testing.T) {
+	assert := assert.New(t)
+
+	dir, cleanup := rotatingFileTempDir(t)
+	defer cleanup()
+
+	path := filepath.Join(dir, "app.log")
+	rfw, err := NewRotatingFileWriter(path)
+	assert.Nil(err)
+	_, err = rfw.Write([]byte("foo\n"))
+	assert.Nil(err)
+	assert.Nil(rfw.Close())
+
+	_, err = rfw.Write([]byte("bar\n"))
+	assert.True(ex.Is(err, ErrRotatingFileWriterClosed))
+	assert.Nil(rfw.file, "the file should not be reopened")
+	assert.True(ex.Is(rfw.Reopen(), ErrRotatingFileWriterClosed))
+	assert.True(ex.Is(rfw.Rotate(), ErrRotatingFileWriterClosed))
+	assert.Equal("foo\n", readFileString(path))
+}
+
+func TestRotatingFileWriterMaxSize(t *testing.T) {
+	assert := assert.New(t)
+
+	dir, cleanup := rotatingFileTempDir(t)
+	defer cleanup()
+
+	path := filepath.Join(dir, "app.log")
+	rfw, err := NewRotatingFileWriter(path, OptRotatingFileMaxSizeBytes(8))
+	assert.Nil(err)
+
+	for _, line := range []string{"0001\n", "0002\n", "0003\n"} {
+		_, err = rfw.Write([]byte(line))
+		assert.Nil(err)
+	}
+	assert.Nil(rfw.Close())
+
+	assert.Equal("0003\n", readFileString(path))
+	backups, err := rfw.Backups()
+	assert.Nil(err)
+	assert.Len(backups, 2)
+	assert.Equal("0002\n", readFileString(backups[0]))
+	assert.Equal("0001\n", readFileString(backups[1]))
+	for _, backup := range backups {
+		assert.True(strings.HasPrefix(filepath.Base(backup), "app-"))
+		assert.True(strings.HasSuffix(backup, ".log"))
+	}
+}
+
+func TestRotatingFileWriterInterval(t *testing.T) {
+	assert := assert.New(t)
+
+	dir, cleanup := rotatingFileTempDir(t)
+	defer cleanup()
+
+	now := time.Date(2020, 01, 02, 12, 30, 0, 0, time.Local)
+	path := filepath.Join(dir, "app.log")
+	rfw := &RotatingFileWriter{
+		Path:     path,
+		Rotation: RotationHourly,
+		now:      func() time.Time { return now },
+	}
+
+	_, err := rfw.Write([]byte("first\n"))
+	assert.Nil(err)
+	now = now.Add(20 * time.Minute)
+	_, err = rfw.Write([]byte("second\n"))
+	assert.Nil(err)
+
+	backups, err := rfw.Backups()
+	assert.Nil(err)
+	assert.Empty(backups)
+
+	now = now.Add(20 * time.Minute)
+	_, err = rfw.Write([]byte("third\n"))
+	assert.Nil(err)
+	assert.Nil(rfw.Close())
+
+	assert.Equal("third\n", readFileString(path))
+	backups, err = rfw.Backups()
+	assert.Nil(err)
+	assert.Len(backups, 1)
+	assert.Equal("first\nsecond\n", readFileString(backups[0]))
+}
+
+func TestRotatingFileWriterPeriodOf(t *testing.T) {
+	assert := assert.New(t)
+
+	ts := time.Date(2020, 01, 02, 12, 30, 45, 0, time.Local)
+	assert.Equal(time.Date(2020, 01, 02, 12, 0, 0, 0, time.Local), (&RotatingFileWriter{Rotation: RotationHourly}).periodOf(ts))
+	assert.Equal(time.Date(2020, 01, 02, 0, 0, 0, 0, time.Local), (&RotatingFileWriter{Rotation: RotationDaily}).periodOf(ts))
+	assert.True((&RotatingFileWriter{}).periodOf(ts).IsZero())
+}
+
+func TestRotatingFileWriterCompressAndMaxBackups(t *testing.T) {
+	assert := assert.New(t)
+
+	dir, cleanup := rotatingFileTempDir(t)
+	defer cleanup()
+
+	path := filepath.Join(dir, "app.log")
+	rfw, err := NewRotatingFileWriter(path,
+		OptRotatingFileCompress(),
+		OptRotatingFileMaxBackups(2),
+	)
+	assert.Nil(err)
+
+	for _, line := range []string{"one\n", "two\n", "three\n", "four\n"} {
+		_, err = rfw.Write([]byte(line))
+		assert.Nil(err)
+		assert.Nil(rfw.Rotate())
+	}
+	assert.Nil(rfw.Close())
+
+	backups, err := rfw.Backups()
+	assert.Nil(err)
+	assert.Len(backups, 2)
+	for _, backup := range backups {
+		assert.True(strings.HasSuffix(backup, ".log.gz"), backup)
+	}
+
+	f, err := os.Open(backups[0])
+	assert.Nil(err)
+	defer f.Close()
+	gz, err := gzip.NewReader(f)
+	assert.Nil(err)
+	contents, err := ioutil.ReadAll(gz)
+	assert.Nil(err)
+	assert.Equal("four\n", string(contents))
+}
+
+func TestRotatingFileWriterMaxAge(t *testing.T) {
+	assert := assert.New(t)
+
+	dir, cleanup := rotatingFileTempDir(t)
+	defer cleanup()
+
+	now := time.Date(2020, 01, 02, 12, 30, 0, 0, time.UTC)
+	path := filepath.Join(dir, "app.log")
+	rfw := &RotatingFileWriter{
+		Path:   path,
+		MaxAge: 24 * time.Hour,
+		now:    func() time.Time { return now },
+	}
+
+	_, err := rfw.Write([]byte("old\n"))
+	assert.Nil(err)
+	assert.Nil(rfw.Rotate())
+	rfw.pending.Wait()
+	now = now.Add(48 * time.Hour)
+	_, err = rfw.Write([]byte("new\n"))
+	assert.Nil(err)
+	assert.Nil(rfw.Rotate())
+	assert.Nil(rfw.Close())
+
+	backups, err := rfw.Backups()
+	assert.Nil(err)
+	assert.Len(backups, 1)
+	assert.Equal("new\n", readFileString(backups[0]))
+}
+
+func TestRotatingFileWriterReopen(t *testing.T) {
+	assert := assert.New(t)
+
+	dir, cleanup := rotatingFileTempDir(t)
+	defer cleanup()
+
+	path := filepath.Join(dir, "app.log")
+	rfw, err := NewRotatingFileWriter(path, OptRotatingFileReopenOnSignal(syscall.SIGUSR2))
+	assert.Nil(err)
+	defer rfw.Close()
+
+	_, err = rfw.Write([]byte("before\n"))
+	assert.Nil(err)
+
+	moved := filepath.Join(dir, "moved.log")
+	assert.Nil(os.Rename(path, moved))
+	assert.Nil(syscall.Kill(os.Getpid(), syscall.SIGUSR2))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	for {
+		if _, err := os.Stat(path); err == nil {
+			break
+		}
+		select {
+		case <-ctx.Done():
+			t.Fatal("file was not reopened")
+		case <-time.After(time.Millisecond):
+		}
+	}
+
+	_, err = rfw.Write([]byte("after\n"))
+	assert.Nil(err)
+	assert.Equal("before\n", readFileString(moved))
+	assert.Equal("after\n", readFileString(path))
+}
+
+func TestRotatingFileWriterInterlocked(t *testing.T) {
+	assert := assert.New(t)
+
+	dir, cleanup := rotatingFileTempDir(t)
+	defer cleanup()
+
+	path := filepath.Join(dir, "app.log")
+	rfw, err := NewRotatingFileWriter(path, OptRotatingFileMaxSizeBytes(64))
+	assert.Nil(err)
+
+	log := MustNew(OptOutput(rfw), OptText(OptTextHideTimestamp(), OptTextNoColor()))
+	wg := sync.WaitGroup{}
+	for index := 0; index < 8; index++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for x := 0; x < 16; x++ {
+				log.Info("test message")
+			}
+		}()
+	}
+	wg.Wait()
+	assert.Nil(log.Output.(*InterlockedWriter).Close())
+
+	backups, err := rfw.Backups()
+	assert.Nil(err)
+	lines := strings.Count(readFileString(path), "\n")
+	for _, backup := range backups {
+		lines += strings.Count(readFileString(backup), "\n")
+	}
+	assert.Equal(8*16, lines)
+}
+
+func TestConfigOutput(t *testing.T) {
+	assert := assert.New(t)
+
+	output, err := Config{}.Output()
+	assert.Nil(err)
+	assert.Nil(output)
+
+	dir, cleanup := rotatingFileTempDir(t)
+	defer cleanup()
+
+	log := None()
+	assert.Nil(OptConfig(Config{
+		File: FileConfig{
+			Path:       filepath.Join(dir, "app.log"),
+			Rotation:   RotationDaily,
+			MaxBackups: 3,
+			Compress:   true,
+		},
+	})(log))
+	rfw, ok := log.Output.(*InterlockedWriter).Output.(*RotatingFileWriter)
+	assert.True(ok)
+	assert.Equal(RotationDaily, rfw.Rotation)
+	assert.Equal(3, rfw.MaxBackups)
+	assert.True(rfw.Compress)
+	assert.NotNil(rfw.file)
+
+	assert.Nil(log.Close())
+	assert.Nil(rfw.file, "closing the logger should close the config output")
+}
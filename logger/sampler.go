@@ -0,0 +1,252 @@
+package logger
+
+import (
+	"bytes"
+	"context"
+	"hash/fnv"
+	"math/rand"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/blend/go-sdk/async"
+)
+
+// Sampling targets.
+const (
+	// SampleAll applies a sampling rule to both listeners and output.
+	SampleAll = ""
+	// SampleOutput applies a sampling rule to output only; listeners see every event.
+	SampleOutput = "output"
+	// SampleListeners applies a sampling rule to listeners only; output sees every event.
+	SampleListeners = "listeners"
+)
+
+// Sampling defaults.
+const (
+	// DefaultSamplingInterval is the default burst sampling interval.
+	DefaultSamplingInterval = time.Second
+	// DefaultSamplingSummaryInterval is the default interval summary events are triggered on.
+	DefaultSamplingSummaryInterval = time.Minute
+)
+
+// SamplingRule is a sampling rule for a flag.
+//
+// A rule can sample events randomly with `Rate`, and / or with burst sampling,
+// where the `First` events for a key in each `Interval` are kept, and then every `Thereafter`th event.
+//
+// The burst sampling key is the flag, plus the value of the label `KeyLabel` if it is set,
+// or a hash of the event text if `KeyMessage` is set.
+type SamplingRule struct {
+	// Flag is the flag the rule applies to.
+	Flag string `json:"flag,omitempty" yaml:"flag,omitempty"`
+	// Rate is the fraction of events to keep, between 0 and 1; zero disables random sampling.
+	Rate float64 `json:"rate,omitempty" yaml:"rate,omitempty"`
+	// First is the number of events per key to keep in each interval; zero disables burst sampling.
+	First int `json:"first,omitempty" yaml:"first,omitempty"`
+	// Thereafter keeps every Nth event per key after `First` in each interval; zero drops them all.
+	Thereafter int `json:"thereafter,omitempty" yaml:"thereafter,omitempty"`
+	// Interval is the burst sampling interval.
+	Interval time.Duration `json:"interval,omitempty" yaml:"interval,omitempty"`
+	// KeyLabel is a label whose value is added to the burst sampling key.
+	KeyLabel string `json:"keyLabel,omitempty" yaml:"keyLabel,omitempty"`
+	// KeyMessage adds a hash of the event text to the burst sampling key.
+	KeyMessage bool `json:"keyMessage,omitempty" yaml:"keyMessage,omitempty"`
+	// Target is what the rule applies to, one of `SampleAll`, `SampleOutput` or `SampleListeners`.
+	Target string `json:"target,omitempty" yaml:"target,omitempty"`
+}
+
+// IntervalOrDefault returns the burst sampling interval or a default.
+func (sr SamplingRule) IntervalOrDefault() time.Duration {
+	if sr.Interval > 0 {
+		return sr.Interval
+	}
+	return DefaultSamplingInterval
+}
+
+// NewSampler returns a new sampler for a given set of rules.
+func NewSampler(cfg SamplingConfig) *Sampler {
+	s := &Sampler{
+		Rules:           make(map[string]SamplingRule),
+		SummaryInterval: cfg.SummaryIntervalOrDefault(),
+		counters:        make(map[string]*samplingCounter),
+		dropped:         make(map[string]int64),
+		random:          rand.New(rand.NewSource(time.Now().UnixNano())).Float64,
+	}
+	for _, rule := range cfg.Rules {
+		s.Rules[rule.Flag] = rule
+	}
+	return s
+}
+
+// Sampler decides if events should be passed to listeners and written to output,
+// and keeps a count of events that were dropped.
+//
+// The dropped counts are periodically triggered as a `SamplingSummaryEvent`
+// once the sampler is started with a logger.
+type Sampler struct {
+	// Rules are the sampling rules by flag.
+	Rules map[string]SamplingRule
+	// SummaryInterval is the interval summary events are triggered on.
+	SummaryInterval time.Duration
+
+	mu       sync.Mutex
+	counters map[string]*samplingCounter
+	dropped  map[string]int64
+	since    time.Time
+	random   func() float64
+	interval *async.Interval
+}
+
+// Sample returns if an event should be passed to listeners, and if it should be written to output.
+func (s *Sampler) Sample(ctx context.Context, e Event) (listen, write bool) {
+	rule, ok := s.Rules[e.GetFlag()]
+	if !ok {
+		return true, true
+	}
+	keep := s.keep(ctx, rule, e)
+	switch rule.Target {
+	case SampleOutput:
+		return true, keep
+	case SampleListeners:
+		return keep, true
+	default:
+		return keep, keep
+	}
+}
+
+// Dropped returns the number of events dropped per flag since the last summary.
+func (s *Sampler) Dropped() map[string]int64 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	output := make(map[string]int64, len(s.dropped))
+	for flag, count := range s.dropped {
+		output[flag] = count
+	}
+	return output
+}
+
+// Start starts triggering summary events on the given logger.
+func (s *Sampler) Start(log Triggerable) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.interval != nil {
+		return
+	}
+	s.interval = async.NewInterval(func(ctx context.Context) error {
+		s.Summarize(ctx, log)
+		return nil
+	}, s.summaryIntervalOrDefault())
+	go s.interval.Start()
+	<-s.interval.NotifyStarted()
+}
+
+// Stop stops triggering summary events.
+func (s *Sampler) Stop() error {
+	s.mu.Lock()
+	interval := s.interval
+	s.interval = nil
+	s.mu.Unlock()
+	if interval == nil {
+		return nil
+	}
+	return interval.Stop()
+}
+
+// Summarize triggers a summary event with the dropped counts, if any events were dropped,
+// and resets the counts and any idle burst counters.
+func (s *Sampler) Summarize(ctx context.Context, log Triggerable) {
+	s.mu.Lock()
+	now := time.Now().UTC()
+	summary := NewSamplingSummaryEvent(s.dropped, now.Sub(s.since))
+	s.dropped = make(map[string]int64)
+	s.since = now
+	for key, counter := range s.counters {
+		if now.Sub(counter.start) > counter.interval {
+			delete(s.counters, key)
+		}
+	}
+	s.mu.Unlock()
+
+	if len(summary.Dropped) > 0 && log != nil {
+		log.Trigger(ctx, summary)
+	}
+}
+
+func (s *Sampler) keep(ctx context.Context, rule SamplingRule, e Event) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.since.IsZero() {
+		s.since = time.Now().UTC()
+	}
+	if rule.Rate > 0 && rule.Rate < 1 && s.random() >= rule.Rate {
+		s.dropped[rule.Flag]++
+		return false
+	}
+	if rule.First > 0 {
+		key := s.key(ctx, rule, e)
+		counter, ok := s.counters[key]
+		now := time.Now().UTC()
+		if !ok || now.Sub(counter.start) >= counter.interval {
+			counter = &samplingCounter{start: now, interval: rule.IntervalOrDefault()}
+			s.counters[key] = counter
+		}
+		counter.count++
+		if counter.count > rule.First && (rule.Thereafter <= 0 || (counter.count-rule.First)%rule.Thereafter != 0) {
+			s.dropped[rule.Flag]++
+			return false
+		}
+	}
+	return true
+}
+
+func (s *Sampler) key(ctx context.Context, rule SamplingRule, e Event) string {
+	key := rule.Flag
+	if rule.KeyLabel != "" {
+		key = key + "|" + GetLabels(ctx)[rule.KeyLabel]
+	}
+	if rule.KeyMessage {
+		key = key + "|" + strconv.FormatUint(eventTextHash(e), 16)
+	}
+	return key
+}
+
+func (s *Sampler) summaryIntervalOrDefault() time.Duration {
+	if s.SummaryInterval > 0 {
+		return s.SummaryInterval
+	}
+	return DefaultSamplingSummaryInterval
+}
+
+type samplingCounter struct {
+	start    time.Time
+	interval time.Duration
+	count    int
+}
+
+// eventTextHash returns a hash of the text output of an event, without colors.
+func eventTextHash(e Event) uint64 {
+	hash := fnv.New64a()
+	if typed, ok := e.(MessageEvent); ok {
+		hash.Write([]byte(typed.Text))
+		return hash.Sum64()
+	}
+	if typed, ok := e.(TextWritable); ok {
+		buffer := new(bytes.Buffer)
+		typed.WriteText(TextOutputFormatter{NoColor: true}, buffer)
+		hash.Write(buffer.Bytes())
+	}
+	return hash.Sum64()
+}
+
+// sortedFlags returns the flags of a dropped counts map in order.
+func sortedFlags(dropped map[string]int64) []string {
+	flags := make([]string, 0, len(dropped))
+	for flag := range dropped {
+		flags = append(flags, flag)
+	}
+	sort.Strings(flags)
+	return flags
+}
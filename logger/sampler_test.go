@@ -0,0 +1,218 @@
+package logger
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/blend/go-sdk/assert"
+)
+
+func TestSamplerBurst(t *testing.T) {
+	assert := assert.New(t)
+
+	s := NewSampler(SamplingConfig{
+		Rules: []SamplingRule{
+			{Flag: Info, First: 3, Thereafter: 5, Interval: time.Hour},
+		},
+	})
+
+	var kept int
+	for x := 0; x < 20; x++ {
+		listen, write := s.Sample(context.Background(), NewMessageEvent(Info, "test"))
+		assert.Equal(listen, write)
+		if write {
+			kept++
+		}
+	}
+	// the first 3, then the 8th, 13th and 18th.
+	assert.Equal(6, kept)
+	assert.Equal(14, s.Dropped()[Info])
+
+	// flags without rules are always kept.
+	listen, write := s.Sample(context.Background(), NewMessageEvent(Error, "test"))
+	assert.True(listen)
+	assert.True(write)
+}
+
+func TestSamplerBurstInterval(t *testing.T) {
+	assert := assert.New(t)
+
+	s := NewSampler(SamplingConfig{
+		Rules: []SamplingRule{
+			{Flag: Info, First: 1, Interval: time.Millisecond},
+		},
+	})
+
+	_, write := s.Sample(context.Background(), NewMessageEvent(Info, "test"))
+	assert.True(write)
+	_, write = s.Sample(context.Background(), NewMessageEvent(Info, "test"))
+	assert.False(write)
+	time.Sleep(2 * time.Millisecond)
+	_, write = s.Sample(context.Background(), NewMessageEvent(Info, "test"))
+	assert.True(write)
+}
+
+func TestSamplerKeys(t *testing.T) {
+	assert := assert.New(t)
+
+	s := NewSampler(SamplingConfig{
+		Rules: []SamplingRule{
+			{Flag: Info, First: 1, Interval: time.Hour, KeyLabel: "route"},
+			{Flag: Error, First: 1, Interval: time.Hour, KeyMessage: true},
+		},
+	})
+
+	foo := WithLabels(context.Background(), Labels{"route": "/foo"})
+	bar := WithLabels(context.Background(), Labels{"route": "/bar"})
+	_, write := s.Sample(foo, NewMessageEvent(Info, "test"))
+	assert.True(write)
+	_, write = s.Sample(bar, NewMessageEvent(Info, "test"))
+	assert.True(write)
+	_, write = s.Sample(foo, NewMessageEvent(Info, "test"))
+	assert.False(write)
+
+	_, write = s.Sample(context.Background(), NewMessageEvent(Error, "foo"))
+	assert.True(write)
+	_, write = s.Sample(context.Background(), NewMessageEvent(Error, "bar"))
+	assert.True(write)
+	_, write = s.Sample(context.Background(), NewMessageEvent(Error, "foo"))
+	assert.False(write)
+}
+
+func TestSamplerRate(t *testing.T) {
+	assert := assert.New(t)
+
+	s := NewSampler(SamplingConfig{
+		Rules: []SamplingRule{
+			{Flag: Info, Rate: 0.5},
+		},
+	})
+	values := []float64{0.1, 0.6, 0.4, 0.9}
+	s.random = func() float64 {
+		value := values[0]
+		values = values[1:]
+		return value
+	}
+
+	var kept int
+	for x := 0; x < 4; x++ {
+		if _, write := s.Sample(context.Background(), NewMessageEvent(Info, "test")); write {
+			kept++
+		}
+	}
+	assert.Equal(2, kept)
+	assert.Equal(2, s.Dropped()[Info])
+}
+
+func TestSamplerTarget(t *testing.T) {
+	assert := assert.New(t)
+
+	s := NewSampler(SamplingConfig{
+		Rules: []SamplingRule{
+			{Flag: Info, First: 1, Interval: time.Hour, Target: SampleOutput},
+			{Flag: Error, First: 1, Interval: time.Hour, Target: SampleListeners},
+		},
+	})
+
+	s.Sample(context.Background(), NewMessageEvent(Info, "test"))
+	listen, write := s.Sample(context.Background(), NewMessageEvent(Info, "test"))
+	assert.True(listen)
+	assert.False(write)
+
+	s.Sample(context.Background(), NewMessageEvent(Error, "test"))
+	listen, write = s.Sample(context.Background(), NewMessageEvent(Error, "test"))
+	assert.False(listen)
+	assert.True(write)
+}
+
+func TestSamplerSummarize(t *testing.T) {
+	assert := assert.New(t)
+
+	output := new(bytes.Buffer)
+	log := MustNew(
+		OptAll(),
+		OptOutput(output),
+		OptText(OptTextHideTimestamp(), OptTextNoColor()),
+		OptSamplingRules(SamplingRule{Flag: Info, First: 2, Interval: time.Hour}),
+	)
+	defer log.Close()
+
+	for x := 0; x < 5; x++ {
+		log.Info("test")
+	}
+	log.Sampler.Summarize(context.Background(), log)
+	assert.Empty(log.Sampler.Dropped())
+
+	// summaries are only triggered if events were dropped.
+	log.Sampler.Summarize(context.Background(), log)
+
+	lines := strings.Split(strings.TrimSpace(output.String()), "\n")
+	assert.Len(lines, 3)
+	assert.Equal("[info] test", lines[0])
+	assert.True(strings.HasPrefix(lines[2], "[sampling] dropped info=3"), lines[2])
+}
+
+func TestOptSamplingStartsAfterOptions(t *testing.T) {
+	assert := assert.New(t)
+
+	var sampler *Sampler
+	_, err := New(
+		OptSamplingRules(SamplingRule{Flag: Info, First: 1, Interval: time.Hour}),
+		func(l *Logger) error {
+			sampler = l.Sampler
+			return fmt.Errorf("this is only a test")
+		},
+	)
+	assert.NotNil(err)
+	assert.NotNil(sampler)
+	assert.Nil(sampler.interval, "the sampler should not be started if an option fails")
+
+	log, err := New(OptSamplingRules(SamplingRule{Flag: Info, First: 1, Interval: time.Hour}))
+	assert.Nil(err)
+	defer log.Close()
+	assert.NotNil(log.Sampler.interval)
+}
+
+func TestLoggerTriggerSampled(t *testing.T) {
+	assert := assert.New(t)
+
+	output := new(bytes.Buffer)
+	log := MustNew(
+		OptAll(),
+		OptOutput(output),
+		OptText(OptTextHideTimestamp(), OptTextNoColor()),
+		OptSamplingRules(SamplingRule{Flag: Info, First: 1, Interval: time.Hour, Target: SampleOutput}),
+	)
+	defer log.Close()
+
+	wg := sync.WaitGroup{}
+	wg.Add(3)
+	log.Listen(Info, DefaultListenerName, func(_ context.Context, _ Event) { wg.Done() })
+
+	log.Info("one")
+	log.Info("two")
+	log.Info("three")
+	wg.Wait()
+
+	assert.Equal("[info] one\n", output.String())
+}
+
+func TestSamplingSummaryEvent(t *testing.T) {
+	assert := assert.New(t)
+
+	e := NewSamplingSummaryEvent(map[string]int64{"foo": 2, "bar": 1}, time.Second)
+	assert.Equal(FlagSampling, e.GetFlag())
+
+	buf := new(bytes.Buffer)
+	e.WriteText(NewTextOutputFormatter(OptTextNoColor()), buf)
+	assert.Equal("dropped bar=1 foo=2 (1s)", buf.String())
+
+	decomposed := e.Decompose()
+	assert.Equal(e.Dropped, decomposed[FieldDropped])
+	assert.Equal(float64(1000), decomposed[FieldElapsed])
+}
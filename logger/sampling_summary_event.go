@@ -0,0 +1,54 @@
+package logger
+
+import (
+	"io"
+	"strconv"
+	"time"
+
+	"github.com/blend/go-sdk/timeutil"
+)
+
+// these are compile time assertions
+var (
+	_ Event        = (*SamplingSummaryEvent)(nil)
+	_ TextWritable = (*SamplingSummaryEvent)(nil)
+	_ JSONWritable = (*SamplingSummaryEvent)(nil)
+)
+
+// NewSamplingSummaryEvent returns a new sampling summary event.
+func NewSamplingSummaryEvent(dropped map[string]int64, elapsed time.Duration) SamplingSummaryEvent {
+	return SamplingSummaryEvent{
+		Dropped: dropped,
+		Elapsed: elapsed,
+	}
+}
+
+// SamplingSummaryEvent reports how many events were dropped by sampling, per flag, over a period.
+type SamplingSummaryEvent struct {
+	Dropped map[string]int64
+	Elapsed time.Duration
+}
+
+// GetFlag implements Event.
+func (e SamplingSummaryEvent) GetFlag() string { return FlagSampling }
+
+// WriteText implements TextWritable.
+func (e SamplingSummaryEvent) WriteText(formatter TextFormatter, output io.Writer) {
+	io.WriteString(output, "dropped")
+	for _, flag := range sortedFlags(e.Dropped) {
+		io.WriteString(output, Space)
+		io.WriteString(output, flag+"="+strconv.FormatInt(e.Dropped[flag], 10))
+	}
+	if e.Elapsed > 0 {
+		io.WriteString(output, Space)
+		io.WriteString(output, "("+e.Elapsed.String()+")")
+	}
+}
+
+// Decompose implements JSONWritable.
+func (e SamplingSummaryEvent) Decompose() map[string]interface{} {
+	return map[string]interface{}{
+		FieldDropped: e.Dropped,
+		FieldElapsed: timeutil.Milliseconds(e.Elapsed),
+	}
+}
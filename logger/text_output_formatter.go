@@ -120,6 +120,11 @@ func (tf TextOutputFormatter) FormatLabels(labels Labels) string {
 	return FormatLabels(tf, ansi.ColorBlue, labels)
 }
 
+// FormatTrace returns the trace and span id section of the message as a string.
+func (tf TextOutputFormatter) FormatTrace(traceID, spanID string) string {
+	return fmt.Sprintf("%s=%s %s=%s", tf.Colorize(FieldTraceID, ansi.ColorLightBlack), traceID, tf.Colorize(FieldSpanID, ansi.ColorLightBlack), spanID)
+}
+
 // WriteFormat implements write formatter.
 func (tf TextOutputFormatter) WriteFormat(ctx context.Context, output io.Writer, e Event) error {
 	buffer := tf.BufferPool.Get()
@@ -139,10 +144,10 @@ func (tf TextOutputFormatter) WriteFormat(ctx context.Context, output io.Writer,
 	buffer.WriteString(tf.FormatFlag(e.GetFlag(), FlagTextColor(e.GetFlag())))
 	buffer.WriteString(Space)
 
-	if typed, ok := e.(TextWritable); ok {
-		typed.WriteText(tf, buffer)
-	} else if stringer, ok := e.(fmt.Stringer); ok {
-		buffer.WriteString(stringer.String())
+	if redactor := GetRedactor(ctx); redactor != nil {
+		buffer.WriteString(redactor.String(tf.formatEventText(e)))
+	} else {
+		tf.writeEventText(buffer, e)
 	}
 
 	labels := GetLabels(ctx)
@@ -151,7 +156,27 @@ func (tf TextOutputFormatter) WriteFormat(ctx context.Context, output io.Writer,
 		buffer.WriteString(tf.FormatLabels(labels))
 	}
 
+	if traceID, spanID := GetTrace(ctx); traceID != "" || spanID != "" {
+		buffer.WriteString("\t")
+		buffer.WriteString(tf.FormatTrace(traceID, spanID))
+	}
+
 	buffer.WriteString(Newline)
 	_, err := io.Copy(output, buffer)
 	return err
 }
+
+func (tf TextOutputFormatter) writeEventText(output io.Writer, e Event) {
+	if typed, ok := e.(TextWritable); ok {
+		typed.WriteText(tf, output)
+	} else if stringer, ok := e.(fmt.Stringer); ok {
+		io.WriteString(output, stringer.String())
+	}
+}
+
+func (tf TextOutputFormatter) formatEventText(e Event) string {
+	buffer := tf.BufferPool.Get()
+	defer tf.BufferPool.Put(buffer)
+	tf.writeEventText(buffer, e)
+	return buffer.String()
+}
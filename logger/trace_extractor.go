@@ -0,0 +1,22 @@
+package logger
+
+import "context"
+
+// TraceExtractor returns the trace and span id for a context, e.g. from the active tracing span.
+// It should return empty strings if there is no trace for the context.
+type TraceExtractor func(context.Context) (traceID, spanID string)
+
+// traceContext returns the context with the trace and span id from the trace extractor,
+// if the logger has one and the context does not already have a trace.
+func (l *Logger) traceContext(ctx context.Context) context.Context {
+	if l.TraceExtractor == nil {
+		return ctx
+	}
+	if traceID, _ := GetTrace(ctx); traceID != "" {
+		return ctx
+	}
+	if traceID, spanID := l.TraceExtractor(ctx); traceID != "" || spanID != "" {
+		return WithTrace(ctx, traceID, spanID)
+	}
+	return ctx
+}
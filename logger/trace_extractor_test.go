@@ -0,0 +1,67 @@
+package logger
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/blend/go-sdk/assert"
+)
+
+type traceIDKey struct{}
+
+func mockTraceExtractor(ctx context.Context) (traceID, spanID string) {
+	if value, ok := ctx.Value(traceIDKey{}).(string); ok {
+		return value, value + "-span"
+	}
+	return "", ""
+}
+
+func TestTraceExtractor(t *testing.T) {
+	assert := assert.New(t)
+
+	output := new(bytes.Buffer)
+	log := MustNew(
+		OptOutput(output),
+		OptText(OptTextHideTimestamp(), OptTextNoColor()),
+		OptTraceExtractor(mockTraceExtractor),
+	)
+	defer log.Close()
+
+	log.Infof("untraced")
+	log.WithContext(context.WithValue(context.Background(), traceIDKey{}, "abcd")).Infof("traced")
+	log.Write(context.WithValue(context.Background(), traceIDKey{}, "ef01"), NewMessageEvent(Info, "written"))
+	assert.Equal(
+		"[info] untraced\n"+
+			"[info] traced\ttrace_id=abcd span_id=abcd-span\n"+
+			"[info] written\ttrace_id=ef01 span_id=ef01-span\n",
+		output.String(),
+	)
+
+	ctx := log.traceContext(WithTrace(context.WithValue(context.Background(), traceIDKey{}, "abcd"), "existing", "span"))
+	traceID, spanID := GetTrace(ctx)
+	assert.Equal("existing", traceID)
+	assert.Equal("span", spanID)
+}
+
+func TestTraceFormatters(t *testing.T) {
+	assert := assert.New(t)
+
+	ctx := WithTrace(context.Background(), "abcd", "ef01")
+
+	buffer := new(bytes.Buffer)
+	assert.Nil(NewLogfmtOutputFormatter(OptLogfmtHideTimestamp()).WriteFormat(WithPath(ctx, "api"), buffer, NewMessageEvent(Info, "hello")))
+	assert.Equal("flag=info scope_path=api trace_id=abcd span_id=ef01 text=hello\n", buffer.String())
+
+	buffer = new(bytes.Buffer)
+	assert.Nil(NewJSONOutputFormatter().WriteFormat(ctx, buffer, NewMessageEvent(Info, "hello")))
+	var fields map[string]interface{}
+	assert.Nil(json.Unmarshal(buffer.Bytes(), &fields))
+	assert.Equal("abcd", fields[FieldTraceID])
+	assert.Equal("ef01", fields[FieldSpanID])
+
+	buffer = new(bytes.Buffer)
+	assert.Nil(NewJSONOutputFormatter().WriteFormat(context.Background(), buffer, NewMessageEvent(Info, "hello")))
+	assert.NotContains(buffer.String(), FieldTraceID)
+}
@@ -0,0 +1,107 @@
+package logshipper
+
+import (
+	"context"
+	"time"
+
+	"github.com/blend/go-sdk/configutil"
+	"github.com/blend/go-sdk/logger"
+)
+
+// Config is the logshipper config.
+type Config struct {
+	// URL is the endpoint batches are posted to. If the url is not set, the shipper is disabled.
+	URL string `json:"url,omitempty" yaml:"url,omitempty" env:"LOG_SHIPPER_URL"`
+	// Flags are the logger flags to ship.
+	Flags []string `json:"flags,omitempty" yaml:"flags,omitempty" env:"LOG_SHIPPER_FLAGS,csv"`
+	// BatchSize is the number of events per batch.
+	BatchSize int `json:"batchSize,omitempty" yaml:"batchSize,omitempty" env:"LOG_SHIPPER_BATCH_SIZE"`
+	// FlushInterval is the interval buffered events are flushed on.
+	FlushInterval time.Duration `json:"flushInterval,omitempty" yaml:"flushInterval,omitempty" env:"LOG_SHIPPER_FLUSH_INTERVAL"`
+	// MaxPending is the maximum number of events waiting to be sent; the oldest events are dropped past it.
+	MaxPending int `json:"maxPending,omitempty" yaml:"maxPending,omitempty" env:"LOG_SHIPPER_MAX_PENDING"`
+	// MaxRetries is the number of times a failed batch is retried; zero disables retries.
+	MaxRetries *int `json:"maxRetries,omitempty" yaml:"maxRetries,omitempty" env:"LOG_SHIPPER_MAX_RETRIES"`
+	// RetryBackoff is the initial backoff between retries; it doubles with each retry.
+	RetryBackoff time.Duration `json:"retryBackoff,omitempty" yaml:"retryBackoff,omitempty" env:"LOG_SHIPPER_RETRY_BACKOFF"`
+	// MaxRetryBackoff is the maximum backoff between retries.
+	MaxRetryBackoff time.Duration `json:"maxRetryBackoff,omitempty" yaml:"maxRetryBackoff,omitempty" env:"LOG_SHIPPER_MAX_RETRY_BACKOFF"`
+	// Timeout is the timeout for a single request.
+	Timeout time.Duration `json:"timeout,omitempty" yaml:"timeout,omitempty" env:"LOG_SHIPPER_TIMEOUT"`
+	// DisableGzip disables gzip compression of request bodies.
+	DisableGzip bool `json:"disableGzip,omitempty" yaml:"disableGzip,omitempty" env:"LOG_SHIPPER_DISABLE_GZIP"`
+}
+
+// IsZero returns if the config is unset.
+func (c Config) IsZero() bool {
+	return c.URL == ""
+}
+
+// Resolve applies configutil resolution steps.
+func (c *Config) Resolve(ctx context.Context) error {
+	return configutil.GetEnvVars(ctx).ReadInto(c)
+}
+
+// FlagsOrDefault returns the flags or a default.
+func (c Config) FlagsOrDefault() []string {
+	if len(c.Flags) > 0 {
+		return c.Flags
+	}
+	return logger.DefaultFlags
+}
+
+// BatchSizeOrDefault returns the batch size or a default.
+func (c Config) BatchSizeOrDefault() int {
+	if c.BatchSize > 0 {
+		return c.BatchSize
+	}
+	return DefaultBatchSize
+}
+
+// FlushIntervalOrDefault returns the flush interval or a default.
+func (c Config) FlushIntervalOrDefault() time.Duration {
+	if c.FlushInterval > 0 {
+		return c.FlushInterval
+	}
+	return DefaultFlushInterval
+}
+
+// MaxPendingOrDefault returns the max pending events or a default.
+func (c Config) MaxPendingOrDefault() int {
+	if c.MaxPending > 0 {
+		return c.MaxPending
+	}
+	return DefaultMaxPending
+}
+
+// MaxRetriesOrDefault returns the max retries or a default.
+func (c Config) MaxRetriesOrDefault() int {
+	if c.MaxRetries != nil && *c.MaxRetries >= 0 {
+		return *c.MaxRetries
+	}
+	return DefaultMaxRetries
+}
+
+// RetryBackoffOrDefault returns the retry backoff or a default.
+func (c Config) RetryBackoffOrDefault() time.Duration {
+	if c.RetryBackoff > 0 {
+		return c.RetryBackoff
+	}
+	return DefaultRetryBackoff
+}
+
+// MaxRetryBackoffOrDefault returns the max retry backoff or a default.
+func (c Config) MaxRetryBackoffOrDefault() time.Duration {
+	if c.MaxRetryBackoff > 0 {
+		return c.MaxRetryBackoff
+	}
+	return DefaultMaxRetryBackoff
+}
+
+// TimeoutOrDefault returns the request timeout or a default.
+func (c Config) TimeoutOrDefault() time.Duration {
+	if c.Timeout > 0 {
+		return c.Timeout
+	}
+	return DefaultTimeout
+}
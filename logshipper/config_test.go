@@ -0,0 +1,36 @@
+package logshipper
+
+import (
+	"testing"
+	"time"
+
+	"github.com/blend/go-sdk/assert"
+	"github.com/blend/go-sdk/logger"
+	"github.com/blend/go-sdk/ref"
+)
+
+func TestConfigDefaults(t *testing.T) {
+	assert := assert.New(t)
+
+	var cfg Config
+	assert.True(cfg.IsZero())
+	assert.Equal(logger.DefaultFlags, cfg.FlagsOrDefault())
+	assert.Equal(DefaultBatchSize, cfg.BatchSizeOrDefault())
+	assert.Equal(DefaultFlushInterval, cfg.FlushIntervalOrDefault())
+	assert.Equal(DefaultMaxPending, cfg.MaxPendingOrDefault())
+	assert.Equal(DefaultMaxRetries, cfg.MaxRetriesOrDefault())
+	assert.Equal(DefaultRetryBackoff, cfg.RetryBackoffOrDefault())
+	assert.Equal(DefaultMaxRetryBackoff, cfg.MaxRetryBackoffOrDefault())
+	assert.Equal(DefaultTimeout, cfg.TimeoutOrDefault())
+
+	cfg = Config{URL: "http://localhost", Flags: []string{logger.Error}, BatchSize: 1, Timeout: time.Second}
+	assert.False(cfg.IsZero())
+	assert.Equal([]string{logger.Error}, cfg.FlagsOrDefault())
+	assert.Equal(1, cfg.BatchSizeOrDefault())
+	assert.Equal(time.Second, cfg.TimeoutOrDefault())
+
+	cfg.MaxRetries = ref.Int(0)
+	assert.Zero(cfg.MaxRetriesOrDefault())
+	cfg.MaxRetries = ref.Int(-1)
+	assert.Equal(DefaultMaxRetries, cfg.MaxRetriesOrDefault())
+}
@@ -0,0 +1,44 @@
+package logshipper
+
+import (
+	"time"
+
+	"github.com/blend/go-sdk/ex"
+)
+
+const (
+	// ListenerName is the logshipper listener name.
+	ListenerName = "logshipper"
+)
+
+// Defaults
+const (
+	// DefaultBatchSize is the default number of events per batch.
+	DefaultBatchSize = 500
+	// DefaultFlushInterval is the default interval buffered events are flushed on.
+	DefaultFlushInterval = 5 * time.Second
+	// DefaultMaxPending is the default maximum number of events waiting to be sent.
+	DefaultMaxPending = 10000
+	// DefaultMaxRetries is the default number of times a batch is retried.
+	DefaultMaxRetries = 5
+	// DefaultRetryBackoff is the default initial retry backoff.
+	DefaultRetryBackoff = 500 * time.Millisecond
+	// DefaultMaxRetryBackoff is the default maximum retry backoff.
+	DefaultMaxRetryBackoff = 30 * time.Second
+	// DefaultTimeout is the default timeout for a single request.
+	DefaultTimeout = 10 * time.Second
+)
+
+// Content types and encodings.
+const (
+	ContentTypeNDJSON   = "application/x-ndjson"
+	ContentEncodingGZIP = "gzip"
+)
+
+// Errors
+const (
+	// ErrURLUnset is returned by `New` if the config url is unset.
+	ErrURLUnset ex.Class = "logshipper; url is unset"
+	// ErrShipFailed is returned when a batch fails to ship after retries.
+	ErrShipFailed ex.Class = "logshipper; failed to ship batch"
+)
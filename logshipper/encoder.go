@@ -0,0 +1,43 @@
+package logshipper
+
+import (
+	"bytes"
+	"context"
+
+	"github.com/blend/go-sdk/logger"
+)
+
+// Encoder encodes an event as a single entry in a batch.
+type Encoder interface {
+	// ContentType is the content type of a batch of encoded events.
+	ContentType() string
+	// Encode encodes an event; the output of each event is concatenated to form a batch.
+	Encode(context.Context, logger.Event) ([]byte, error)
+}
+
+// NDJSONEncoder returns an encoder that writes events as newline delimited json,
+// with the same fields as the logger json output.
+func NDJSONEncoder() Encoder {
+	return FormatterEncoder{
+		Formatter: logger.NewJSONOutputFormatter(),
+		Type:      ContentTypeNDJSON,
+	}
+}
+
+// FormatterEncoder encodes events with a logger write formatter.
+type FormatterEncoder struct {
+	Formatter logger.WriteFormatter
+	Type      string
+}
+
+// ContentType implements Encoder.
+func (fe FormatterEncoder) ContentType() string { return fe.Type }
+
+// Encode implements Encoder.
+func (fe FormatterEncoder) Encode(ctx context.Context, e logger.Event) ([]byte, error) {
+	buffer := new(bytes.Buffer)
+	if err := fe.Formatter.WriteFormat(ctx, buffer, e); err != nil {
+		return nil, err
+	}
+	return buffer.Bytes(), nil
+}
@@ -0,0 +1,28 @@
+package logshipper
+
+import (
+	"github.com/blend/go-sdk/logger"
+)
+
+// Listenable is a logger that can add listeners and drainers.
+type Listenable interface {
+	logger.Listenable
+	AddDrainer(string, logger.Drainer)
+}
+
+// AddListeners creates and starts a shipper, and adds it as a listener for the config flags
+// and as a drainer so it is flushed when the logger is drained.
+// It returns nil if the config is unset.
+func AddListeners(log Listenable, cfg Config, options ...Option) *Shipper {
+	if log == nil || cfg.IsZero() {
+		return nil
+	}
+	shipper := MustNew(cfg, options...)
+	shipper.Start()
+	listener := shipper.Listener()
+	for _, flag := range cfg.FlagsOrDefault() {
+		log.Listen(flag, ListenerName, listener)
+	}
+	log.AddDrainer(ListenerName, shipper)
+	return shipper
+}
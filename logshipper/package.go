@@ -0,0 +1,14 @@
+/*
+Package logshipper ships logger events in batches to an http endpoint.
+
+Events are encoded as they are triggered (as newline delimited json by default), buffered with an
+`async.AutoflushBuffer`, and posted in gzipped batches with retries. If the endpoint is slow or down,
+memory use is bounded by dropping the oldest pending events.
+
+	shipper := logshipper.AddListeners(log, logshipper.Config{URL: "https://logs.example.com/ingest"})
+	defer shipper.Stop()
+
+The shipper is registered as a drainer on the logger, so `log.DrainContext(ctx)` flushes any
+buffered events.
+*/
+package logshipper
@@ -0,0 +1,303 @@
+package logshipper
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"math/rand"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/blend/go-sdk/async"
+	"github.com/blend/go-sdk/ex"
+	"github.com/blend/go-sdk/logger"
+	"github.com/blend/go-sdk/r2"
+	"github.com/blend/go-sdk/webutil"
+)
+
+var (
+	_ logger.Drainer = (*Shipper)(nil)
+)
+
+// MustNew returns a new shipper and panics on error.
+func MustNew(cfg Config, options ...Option) *Shipper {
+	s, err := New(cfg, options...)
+	if err != nil {
+		panic(err)
+	}
+	return s
+}
+
+// New returns a new shipper for a given config.
+// The shipper must be started with `Start` to flush events on an interval.
+func New(cfg Config, options ...Option) (*Shipper, error) {
+	if cfg.IsZero() {
+		return nil, ex.New(ErrURLUnset)
+	}
+	s := &Shipper{
+		Config:  cfg,
+		Encoder: NDJSONEncoder(),
+		sending: make(chan struct{}, 1),
+	}
+	for _, option := range options {
+		option(s)
+	}
+	s.Buffer = async.NewAutoflushBuffer(s.handleFlush,
+		async.OptAutoflushBufferMaxLen(cfg.BatchSizeOrDefault()),
+		async.OptAutoflushBufferInterval(cfg.FlushIntervalOrDefault()),
+	)
+	return s, nil
+}
+
+// Option mutates a shipper.
+type Option func(*Shipper)
+
+// OptEncoder sets the event encoder.
+func OptEncoder(encoder Encoder) Option {
+	return func(s *Shipper) { s.Encoder = encoder }
+}
+
+// OptRequestOptions sets additional request options, e.g. for authentication.
+func OptRequestOptions(options ...r2.Option) Option {
+	return func(s *Shipper) { s.RequestOptions = append(s.RequestOptions, options...) }
+}
+
+// OptErrors sets a channel that encoding and shipping errors are sent to.
+// Errors are dropped if the channel is full.
+func OptErrors(errors chan error) Option {
+	return func(s *Shipper) { s.Errors = errors }
+}
+
+// Shipper posts batches of encoded events to an http endpoint.
+type Shipper struct {
+	Config         Config
+	Encoder        Encoder
+	RequestOptions []r2.Option
+	Errors         chan error
+	Buffer         *async.AutoflushBuffer
+
+	mu      sync.Mutex
+	pending [][]byte
+	dropped int64
+	sending chan struct{}
+}
+
+// Listener returns a logger listener that encodes and buffers events.
+func (s *Shipper) Listener() logger.Listener {
+	return func(ctx context.Context, e logger.Event) {
+		encoded, err := s.Encoder.Encode(ctx, e)
+		if err != nil {
+			s.error(err)
+			return
+		}
+		s.Buffer.Add(encoded)
+	}
+}
+
+// Start starts flushing buffered events on the flush interval.
+func (s *Shipper) Start() {
+	go s.Buffer.Start()
+	<-s.Buffer.NotifyStarted()
+}
+
+// Stop stops the flush interval and flushes any buffered events.
+func (s *Shipper) Stop() error {
+	return s.Buffer.Stop()
+}
+
+// DrainContext implements logger.Drainer.
+// It sends any buffered and pending events, and returns the first error encountered.
+func (s *Shipper) DrainContext(ctx context.Context) error {
+	s.Buffer.Lock()
+	contents := s.Buffer.Contents.Drain()
+	s.Buffer.Unlock()
+
+	s.enqueue(contents)
+	return s.Send(ctx)
+}
+
+// Pending returns the number of events waiting to be sent.
+func (s *Shipper) Pending() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return len(s.pending)
+}
+
+// Dropped returns the number of events dropped, either because too many were pending
+// or because their batch failed to send.
+func (s *Shipper) Dropped() int64 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.dropped
+}
+
+// Send sends pending events in batches until none are left, waiting for any send already in flight.
+// If a batch fails to send after retries, it is dropped and the error is returned;
+// any remaining events are left pending for the next send.
+func (s *Shipper) Send(ctx context.Context) error {
+	select {
+	case s.sending <- struct{}{}:
+	case <-ctx.Done():
+		return ex.New(ErrShipFailed, ex.OptInner(ctx.Err()))
+	}
+	defer func() { <-s.sending }()
+	return s.sendPending(ctx)
+}
+
+// sendPending sends pending events in batches; the caller must hold the send slot.
+func (s *Shipper) sendPending(ctx context.Context) error {
+	for {
+		batch := s.take(s.Config.BatchSizeOrDefault())
+		if len(batch) == 0 {
+			return nil
+		}
+		if err := s.ship(ctx, batch); err != nil {
+			s.mu.Lock()
+			s.dropped += int64(len(batch))
+			s.mu.Unlock()
+			return err
+		}
+	}
+}
+
+// handleFlush is the autoflush buffer handler.
+// If a send is already in flight (e.g. retrying during an outage) the events are left pending, up to
+// the max pending, rather than waiting for it; the send in flight sends them once it has sent its batch.
+// Errors are reported to the errors channel without blocking, as the buffer would block on them.
+func (s *Shipper) handleFlush(ctx context.Context, contents []interface{}) error {
+	s.enqueue(contents)
+	select {
+	case s.sending <- struct{}{}:
+	default:
+		return nil
+	}
+	defer func() { <-s.sending }()
+	if err := s.sendPending(ctx); err != nil {
+		s.error(err)
+	}
+	return nil
+}
+
+// enqueue adds encoded events to the pending queue, dropping the oldest events past the max pending.
+func (s *Shipper) enqueue(contents []interface{}) {
+	if len(contents) == 0 {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, item := range contents {
+		if typed, ok := item.([]byte); ok {
+			s.pending = append(s.pending, typed)
+		}
+	}
+	if over := len(s.pending) - s.Config.MaxPendingOrDefault(); over > 0 {
+		s.pending = append([][]byte(nil), s.pending[over:]...)
+		s.dropped += int64(over)
+	}
+}
+
+// take removes up to a given number of the oldest pending events.
+func (s *Shipper) take(count int) [][]byte {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if count > len(s.pending) {
+		count = len(s.pending)
+	}
+	batch := s.pending[:count]
+	s.pending = s.pending[count:]
+	return batch
+}
+
+// ship posts a batch, retrying with backoff on network errors, 429s and 5xx responses.
+func (s *Shipper) ship(ctx context.Context, batch [][]byte) error {
+	body, err := s.body(batch)
+	if err != nil {
+		return err
+	}
+
+	var lastErr error
+	for attempt := 0; attempt <= s.Config.MaxRetriesOrDefault(); attempt++ {
+		if attempt > 0 {
+			timer := time.NewTimer(s.backoff(attempt))
+			select {
+			case <-ctx.Done():
+				timer.Stop()
+				return ex.New(ErrShipFailed, ex.OptInner(ctx.Err()))
+			case <-timer.C:
+			}
+		}
+
+		res, err := r2.New(s.Config.URL, s.requestOptions(ctx, body)...).Discard()
+		if err != nil {
+			lastErr = ex.New(ErrShipFailed, ex.OptInner(err))
+			continue
+		}
+		if res.StatusCode < http.StatusMultipleChoices {
+			return nil
+		}
+		lastErr = ex.New(ErrShipFailed, ex.OptMessagef("status code: %d", res.StatusCode))
+		if res.StatusCode != http.StatusTooManyRequests && res.StatusCode < http.StatusInternalServerError {
+			return lastErr
+		}
+	}
+	return lastErr
+}
+
+func (s *Shipper) requestOptions(ctx context.Context, body []byte) []r2.Option {
+	options := []r2.Option{
+		r2.OptContext(ctx),
+		r2.OptPost(),
+		r2.OptTimeout(s.Config.TimeoutOrDefault()),
+		r2.OptHeaderValue(webutil.HeaderContentType, s.Encoder.ContentType()),
+		r2.OptBodyBytes(body),
+	}
+	if !s.Config.DisableGzip {
+		options = append(options, r2.OptHeaderValue(webutil.HeaderContentEncoding, ContentEncodingGZIP))
+	}
+	return append(options, s.RequestOptions...)
+}
+
+func (s *Shipper) body(batch [][]byte) ([]byte, error) {
+	buffer := new(bytes.Buffer)
+	if s.Config.DisableGzip {
+		for _, line := range batch {
+			buffer.Write(line)
+		}
+		return buffer.Bytes(), nil
+	}
+	gz := gzip.NewWriter(buffer)
+	for _, line := range batch {
+		if _, err := gz.Write(line); err != nil {
+			return nil, ex.New(err)
+		}
+	}
+	if err := gz.Close(); err != nil {
+		return nil, ex.New(err)
+	}
+	return buffer.Bytes(), nil
+}
+
+// backoff returns the exponential backoff for a given retry attempt, with jitter.
+func (s *Shipper) backoff(attempt int) time.Duration {
+	backoff := s.Config.RetryBackoffOrDefault()
+	max := s.Config.MaxRetryBackoffOrDefault()
+	for x := 1; x < attempt && backoff < max; x++ {
+		backoff = backoff * 2
+	}
+	if backoff > max {
+		backoff = max
+	}
+	half := int64(backoff / 2)
+	return time.Duration(half + rand.Int63n(half+1))
+}
+
+func (s *Shipper) error(err error) {
+	if s.Errors == nil {
+		return
+	}
+	select {
+	case s.Errors <- err:
+	default:
+	}
+}
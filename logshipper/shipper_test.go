@@ -0,0 +1,258 @@
+package logshipper
+
+import (
+	"bufio"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/blend/go-sdk/assert"
+	"github.com/blend/go-sdk/ex"
+	"github.com/blend/go-sdk/logger"
+	"github.com/blend/go-sdk/ref"
+	"github.com/blend/go-sdk/webutil"
+)
+
+type mockIngest struct {
+	sync.Mutex
+	Statuses []int
+	Requests int
+	Headers  []http.Header
+	Lines    []map[string]interface{}
+}
+
+func (mi *mockIngest) ServeHTTP(rw http.ResponseWriter, r *http.Request) {
+	mi.Lock()
+	defer mi.Unlock()
+
+	status := http.StatusOK
+	if mi.Requests < len(mi.Statuses) {
+		status = mi.Statuses[mi.Requests]
+	}
+	mi.Requests++
+	mi.Headers = append(mi.Headers, r.Header)
+	if status != http.StatusOK {
+		rw.WriteHeader(status)
+		return
+	}
+
+	var body io.Reader = r.Body
+	if r.Header.Get(webutil.HeaderContentEncoding) == ContentEncodingGZIP {
+		gz, err := gzip.NewReader(r.Body)
+		if err != nil {
+			rw.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		body = gz
+	}
+	scanner := bufio.NewScanner(body)
+	for scanner.Scan() {
+		line := make(map[string]interface{})
+		if err := json.Unmarshal(scanner.Bytes(), &line); err != nil {
+			rw.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		mi.Lines = append(mi.Lines, line)
+	}
+	rw.WriteHeader(http.StatusOK)
+}
+
+func testConfig(url string) Config {
+	return Config{
+		URL:           url,
+		FlushInterval: time.Hour,
+		RetryBackoff:  time.Millisecond,
+	}
+}
+
+func TestShipperDrainContext(t *testing.T) {
+	assert := assert.New(t)
+
+	ingest := new(mockIngest)
+	server := httptest.NewServer(ingest)
+	defer server.Close()
+
+	log := logger.MustNew(logger.OptOutput(nil), logger.OptAll())
+
+	shipper := AddListeners(log, testConfig(server.URL))
+	assert.NotNil(shipper)
+	defer shipper.Stop()
+
+	log.Infof("hello %s", "world")
+	log.Error(ex.New("bad things"))
+	assert.Nil(log.DrainContext(context.Background()))
+
+	assert.Equal(1, ingest.Requests)
+	assert.Equal(ContentTypeNDJSON, ingest.Headers[0].Get(webutil.HeaderContentType))
+	assert.Equal(ContentEncodingGZIP, ingest.Headers[0].Get(webutil.HeaderContentEncoding))
+	assert.Len(ingest.Lines, 2)
+	lines := make(map[interface{}]map[string]interface{})
+	for _, line := range ingest.Lines {
+		lines[line[logger.FieldFlag]] = line
+	}
+	assert.Equal("hello world", lines[logger.Info][logger.FieldText])
+	assert.NotNil(lines[logger.Error])
+	assert.Zero(shipper.Pending())
+	assert.Zero(shipper.Dropped())
+}
+
+func TestShipperBatchSize(t *testing.T) {
+	assert := assert.New(t)
+
+	ingest := new(mockIngest)
+	server := httptest.NewServer(ingest)
+	defer server.Close()
+
+	cfg := testConfig(server.URL)
+	cfg.BatchSize = 2
+	cfg.MaxPending = 10
+	cfg.DisableGzip = true
+	shipper := MustNew(cfg)
+
+	shipper.enqueue([]interface{}{[]byte("{\"a\":1}\n"), []byte("{\"a\":2}\n"), []byte("{\"a\":3}\n")})
+	assert.Equal(3, shipper.Pending())
+	assert.Nil(shipper.Send(context.Background()))
+
+	assert.Equal(2, ingest.Requests)
+	assert.Empty(ingest.Headers[0].Get(webutil.HeaderContentEncoding))
+	assert.Len(ingest.Lines, 3)
+}
+
+func TestShipperRetries(t *testing.T) {
+	assert := assert.New(t)
+
+	ingest := &mockIngest{Statuses: []int{http.StatusInternalServerError, http.StatusTooManyRequests}}
+	server := httptest.NewServer(ingest)
+	defer server.Close()
+
+	shipper := MustNew(testConfig(server.URL))
+	shipper.enqueue([]interface{}{[]byte("{\"a\":1}\n")})
+	assert.Nil(shipper.Send(context.Background()))
+	assert.Equal(3, ingest.Requests)
+	assert.Len(ingest.Lines, 1)
+	assert.Zero(shipper.Dropped())
+}
+
+func TestShipperRetriesExhausted(t *testing.T) {
+	assert := assert.New(t)
+
+	ingest := &mockIngest{Statuses: []int{http.StatusBadGateway, http.StatusBadGateway, http.StatusBadGateway}}
+	server := httptest.NewServer(ingest)
+	defer server.Close()
+
+	cfg := testConfig(server.URL)
+	cfg.MaxRetries = ref.Int(2)
+	shipper := MustNew(cfg)
+	shipper.enqueue([]interface{}{[]byte("{\"a\":1}\n"), []byte("{\"a\":2}\n")})
+	err := shipper.Send(context.Background())
+	assert.True(ex.Is(err, ErrShipFailed))
+	assert.Equal(3, ingest.Requests)
+	assert.Equal(2, shipper.Dropped())
+	assert.Zero(shipper.Pending())
+}
+
+func TestShipperNoRetries(t *testing.T) {
+	assert := assert.New(t)
+
+	ingest := &mockIngest{Statuses: []int{http.StatusBadGateway}}
+	server := httptest.NewServer(ingest)
+	defer server.Close()
+
+	cfg := testConfig(server.URL)
+	cfg.MaxRetries = ref.Int(0)
+	shipper := MustNew(cfg)
+	shipper.enqueue([]interface{}{[]byte("{\"a\":1}\n")})
+	assert.True(ex.Is(shipper.Send(context.Background()), ErrShipFailed))
+	assert.Equal(1, ingest.Requests)
+	assert.Equal(1, shipper.Dropped())
+}
+
+func TestShipperFlushDuringSend(t *testing.T) {
+	assert := assert.New(t)
+
+	ingest := new(mockIngest)
+	server := httptest.NewServer(ingest)
+	defer server.Close()
+
+	shipper := MustNew(testConfig(server.URL))
+	// hold the send slot as a send in flight would.
+	shipper.sending <- struct{}{}
+
+	flushed := make(chan error)
+	go func() {
+		flushed <- shipper.handleFlush(context.Background(), []interface{}{[]byte("{\"a\":1}\n")})
+	}()
+	select {
+	case err := <-flushed:
+		assert.Nil(err)
+	case <-time.After(5 * time.Second):
+		assert.FailNow("flushes should not wait for a send in flight")
+	}
+	assert.Equal(1, shipper.Pending())
+	assert.Zero(ingest.Requests)
+
+	<-shipper.sending
+	assert.Nil(shipper.Send(context.Background()))
+	assert.Equal(1, ingest.Requests)
+	assert.Zero(shipper.Pending())
+}
+
+func TestShipperNoRetryOnClientError(t *testing.T) {
+	assert := assert.New(t)
+
+	ingest := &mockIngest{Statuses: []int{http.StatusBadRequest}}
+	server := httptest.NewServer(ingest)
+	defer server.Close()
+
+	errors := make(chan error, 1)
+	shipper := MustNew(testConfig(server.URL), OptErrors(errors))
+	assert.Nil(shipper.handleFlush(context.Background(), []interface{}{[]byte("{\"a\":1}\n")}))
+	assert.Equal(1, ingest.Requests)
+	assert.Equal(1, shipper.Dropped())
+	assert.True(ex.Is(<-errors, ErrShipFailed))
+}
+
+func TestShipperMaxPending(t *testing.T) {
+	assert := assert.New(t)
+
+	cfg := testConfig("http://localhost")
+	cfg.MaxPending = 2
+	shipper := MustNew(cfg)
+	shipper.enqueue([]interface{}{[]byte("1"), []byte("2"), []byte("3")})
+	assert.Equal(2, shipper.Pending())
+	assert.Equal(1, shipper.Dropped())
+	batch := shipper.take(10)
+	assert.Equal("2", string(batch[0]))
+	assert.Equal("3", string(batch[1]))
+}
+
+func TestShipperBackoff(t *testing.T) {
+	assert := assert.New(t)
+
+	shipper := MustNew(Config{URL: "http://localhost", RetryBackoff: time.Second, MaxRetryBackoff: 4 * time.Second})
+	for attempt, expected := range map[int]time.Duration{1: time.Second, 2: 2 * time.Second, 3: 4 * time.Second, 10: 4 * time.Second} {
+		backoff := shipper.backoff(attempt)
+		assert.True(backoff >= expected/2, attempt)
+		assert.True(backoff <= expected, attempt)
+	}
+}
+
+func TestNew(t *testing.T) {
+	assert := assert.New(t)
+
+	_, err := New(Config{})
+	assert.True(ex.Is(err, ErrURLUnset))
+	assert.Nil(AddListeners(logger.None(), Config{}))
+
+	shipper, err := New(Config{URL: "http://localhost"}, OptEncoder(FormatterEncoder{Formatter: logger.NewLogfmtOutputFormatter(), Type: "text/plain"}))
+	assert.Nil(err)
+	assert.Equal("text/plain", shipper.Encoder.ContentType())
+	assert.Equal(DefaultBatchSize, shipper.Buffer.MaxLen)
+	assert.Equal(DefaultFlushInterval, shipper.Buffer.Interval)
+}
@@ -0,0 +1,166 @@
+package r2
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/blend/go-sdk/ex"
+)
+
+var (
+	_ TokenSource      = (*CachingTokenSource)(nil)
+	_ TokenInvalidator = (*CachingTokenSource)(nil)
+)
+
+// DefaultTokenRefreshWindow is the default window before a token's expiry
+// that it will be proactively refreshed.
+const DefaultTokenRefreshWindow = time.Minute
+
+// DefaultTokenErrorBackoff is the default time after a failed fetch
+// that its error is returned instead of fetching again.
+const DefaultTokenErrorBackoff = time.Second
+
+// NewCachingTokenSource returns a new caching token source that wraps a given source.
+func NewCachingTokenSource(source TokenSource, options ...CachingTokenSourceOption) *CachingTokenSource {
+	cts := &CachingTokenSource{
+		Source:        source,
+		RefreshWindow: DefaultTokenRefreshWindow,
+		ErrorBackoff:  DefaultTokenErrorBackoff,
+	}
+	for _, option := range options {
+		option(cts)
+	}
+	return cts
+}
+
+// CachingTokenSourceOption mutates a caching token source.
+type CachingTokenSourceOption func(*CachingTokenSource)
+
+// OptCachingTokenSourceRefreshWindow sets the proactive refresh window.
+func OptCachingTokenSourceRefreshWindow(window time.Duration) CachingTokenSourceOption {
+	return func(cts *CachingTokenSource) {
+		cts.RefreshWindow = window
+	}
+}
+
+// OptCachingTokenSourceFetchTimeout sets the timeout for fetching tokens from the underlying source.
+func OptCachingTokenSourceFetchTimeout(timeout time.Duration) CachingTokenSourceOption {
+	return func(cts *CachingTokenSource) {
+		cts.FetchTimeout = timeout
+	}
+}
+
+// OptCachingTokenSourceErrorBackoff sets the time after a failed fetch that its error is returned instead of fetching again.
+func OptCachingTokenSourceErrorBackoff(backoff time.Duration) CachingTokenSourceOption {
+	return func(cts *CachingTokenSource) {
+		cts.ErrorBackoff = backoff
+	}
+}
+
+// CachingTokenSource caches tokens from an underlying source until they expire.
+//
+// When a cached token is within `RefreshWindow` of its expiry, it is still returned, but a
+// refresh is started in the background so callers do not block on the fetch.
+// Concurrent callers that need a new token share a single fetch from the underlying source, and
+// if a fetch fails its error is returned to callers for `ErrorBackoff` before the source is tried again.
+type CachingTokenSource struct {
+	// Source is the underlying token source.
+	Source TokenSource
+	// RefreshWindow is the window before expiry in which a token is proactively refreshed.
+	RefreshWindow time.Duration
+	// FetchTimeout is an optional timeout for fetches from the underlying source.
+	// Fetches are shared between callers, so they do not use any one caller's context.
+	FetchTimeout time.Duration
+	// ErrorBackoff is the time after a failed fetch that its error is returned instead of fetching again,
+	// so that callers don't all call the underlying source while it is failing.
+	ErrorBackoff time.Duration
+
+	mu            sync.Mutex
+	token         *Token
+	inflight      *tokenFetch
+	fetchErr      error
+	fetchErrUntil time.Time
+}
+
+// tokenFetch is a fetch from the underlying source shared between callers.
+type tokenFetch struct {
+	done  chan struct{}
+	token *Token
+	err   error
+}
+
+// Token implements TokenSource.
+func (cts *CachingTokenSource) Token(ctx context.Context) (*Token, error) {
+	now := time.Now().UTC()
+
+	cts.mu.Lock()
+	backoff := cts.fetchErr != nil && now.Before(cts.fetchErrUntil)
+	if cts.token.IsValid(now) {
+		token := cts.token
+		if token.IsExpired(now, cts.RefreshWindow) && !backoff {
+			cts.fetchUnsafe()
+		}
+		cts.mu.Unlock()
+		return token, nil
+	}
+	if backoff {
+		err := cts.fetchErr
+		cts.mu.Unlock()
+		return nil, err
+	}
+	fetch := cts.fetchUnsafe()
+	cts.mu.Unlock()
+
+	select {
+	case <-ctx.Done():
+		return nil, ex.New(ctx.Err())
+	case <-fetch.done:
+		return fetch.token, fetch.err
+	}
+}
+
+// Invalidate clears the cached token if it is the given token.
+// It is called when a server rejects a token before it was expected to expire.
+func (cts *CachingTokenSource) Invalidate(token *Token) {
+	cts.mu.Lock()
+	defer cts.mu.Unlock()
+	if token == nil || cts.token == nil || cts.token.AccessToken == token.AccessToken {
+		cts.token = nil
+	}
+}
+
+// fetchUnsafe starts a fetch from the underlying source, or returns the fetch that is already in flight.
+// It must be called while holding the lock.
+func (cts *CachingTokenSource) fetchUnsafe() *tokenFetch {
+	if cts.inflight != nil {
+		return cts.inflight
+	}
+	fetch := &tokenFetch{done: make(chan struct{})}
+	cts.inflight = fetch
+	go func() {
+		ctx := context.Background()
+		if cts.FetchTimeout > 0 {
+			var cancel context.CancelFunc
+			ctx, cancel = context.WithTimeout(ctx, cts.FetchTimeout)
+			defer cancel()
+		}
+		fetch.token, fetch.err = cts.Source.Token(ctx)
+		if fetch.err == nil && fetch.token == nil {
+			fetch.err = ex.New(ErrTokenEmpty)
+		}
+
+		cts.mu.Lock()
+		if fetch.err == nil {
+			cts.token = fetch.token
+			cts.fetchErr = nil
+		} else {
+			cts.fetchErr = fetch.err
+			cts.fetchErrUntil = time.Now().UTC().Add(cts.ErrorBackoff)
+		}
+		cts.inflight = nil
+		cts.mu.Unlock()
+		close(fetch.done)
+	}()
+	return fetch
+}
@@ -0,0 +1,158 @@
+package r2
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/blend/go-sdk/assert"
+)
+
+func TestCachingTokenSourceCaches(t *testing.T) {
+	assert := assert.New(t)
+
+	var fetches int32
+	cts := NewCachingTokenSource(TokenSourceFunc(func(_ context.Context) (*Token, error) {
+		count := atomic.AddInt32(&fetches, 1)
+		return &Token{AccessToken: fmt.Sprint(count), Expiry: time.Now().UTC().Add(time.Hour)}, nil
+	}))
+
+	for x := 0; x < 5; x++ {
+		token, err := cts.Token(context.Background())
+		assert.Nil(err)
+		assert.Equal("1", token.AccessToken)
+	}
+	assert.Equal(1, atomic.LoadInt32(&fetches))
+
+	cts.Invalidate(&Token{AccessToken: "not-cached"})
+	token, err := cts.Token(context.Background())
+	assert.Nil(err)
+	assert.Equal("1", token.AccessToken)
+
+	cts.Invalidate(token)
+	token, err = cts.Token(context.Background())
+	assert.Nil(err)
+	assert.Equal("2", token.AccessToken)
+}
+
+func TestCachingTokenSourceSharesFetches(t *testing.T) {
+	assert := assert.New(t)
+
+	var fetches int32
+	release := make(chan struct{})
+	cts := NewCachingTokenSource(TokenSourceFunc(func(_ context.Context) (*Token, error) {
+		atomic.AddInt32(&fetches, 1)
+		<-release
+		return &Token{AccessToken: "foo", Expiry: time.Now().UTC().Add(time.Hour)}, nil
+	}))
+
+	var wg sync.WaitGroup
+	errs := make(chan error, 10)
+	for x := 0; x < 10; x++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			token, err := cts.Token(context.Background())
+			if err == nil && token.AccessToken != "foo" {
+				err = fmt.Errorf("unexpected token: %s", token.AccessToken)
+			}
+			errs <- err
+		}()
+	}
+	time.Sleep(10 * time.Millisecond)
+	close(release)
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		assert.Nil(err)
+	}
+	assert.Equal(1, atomic.LoadInt32(&fetches))
+}
+
+func TestCachingTokenSourceProactiveRefresh(t *testing.T) {
+	assert := assert.New(t)
+
+	var fetches int32
+	refreshed := make(chan struct{})
+	cts := NewCachingTokenSource(TokenSourceFunc(func(_ context.Context) (*Token, error) {
+		count := atomic.AddInt32(&fetches, 1)
+		if count == 2 {
+			defer close(refreshed)
+		}
+		return &Token{AccessToken: fmt.Sprint(count), Expiry: time.Now().UTC().Add(30 * time.Second)}, nil
+	}), OptCachingTokenSourceRefreshWindow(time.Minute))
+
+	token, err := cts.Token(context.Background())
+	assert.Nil(err)
+	assert.Equal("1", token.AccessToken)
+
+	// the token is within the refresh window, so the cached token is returned and a refresh starts.
+	token, err = cts.Token(context.Background())
+	assert.Nil(err)
+	assert.Equal("1", token.AccessToken)
+
+	<-refreshed
+	time.Sleep(5 * time.Millisecond)
+	token, err = cts.Token(context.Background())
+	assert.Nil(err)
+	assert.NotEqual("1", token.AccessToken)
+}
+
+func TestCachingTokenSourceError(t *testing.T) {
+	assert := assert.New(t)
+
+	cts := NewCachingTokenSource(TokenSourceFunc(func(_ context.Context) (*Token, error) {
+		return nil, fmt.Errorf("this is only a test")
+	}))
+	_, err := cts.Token(context.Background())
+	assert.NotNil(err)
+
+	empty := NewCachingTokenSource(TokenSourceFunc(func(_ context.Context) (*Token, error) {
+		return nil, nil
+	}))
+	_, err = empty.Token(context.Background())
+	assert.NotNil(err)
+}
+
+func TestCachingTokenSourceErrorBackoff(t *testing.T) {
+	assert := assert.New(t)
+
+	var fetches int32
+	cts := NewCachingTokenSource(TokenSourceFunc(func(_ context.Context) (*Token, error) {
+		if atomic.AddInt32(&fetches, 1) == 1 {
+			return nil, fmt.Errorf("this is only a test")
+		}
+		return &Token{AccessToken: "foo"}, nil
+	}), OptCachingTokenSourceErrorBackoff(50*time.Millisecond))
+
+	for x := 0; x < 5; x++ {
+		_, err := cts.Token(context.Background())
+		assert.NotNil(err)
+	}
+	assert.Equal(1, atomic.LoadInt32(&fetches))
+
+	time.Sleep(60 * time.Millisecond)
+	token, err := cts.Token(context.Background())
+	assert.Nil(err)
+	assert.Equal("foo", token.AccessToken)
+	assert.Equal(2, atomic.LoadInt32(&fetches))
+}
+
+func TestCachingTokenSourceContextCancelled(t *testing.T) {
+	assert := assert.New(t)
+
+	release := make(chan struct{})
+	defer close(release)
+	cts := NewCachingTokenSource(TokenSourceFunc(func(_ context.Context) (*Token, error) {
+		<-release
+		return &Token{AccessToken: "foo"}, nil
+	}))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Millisecond)
+	defer cancel()
+	_, err := cts.Token(ctx)
+	assert.NotNil(err)
+}
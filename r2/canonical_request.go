@@ -0,0 +1,200 @@
+package r2
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+
+	"github.com/blend/go-sdk/ex"
+)
+
+// EmptyPayloadHash is the hex encoded sha256 hash of an empty payload.
+const EmptyPayloadHash = "e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855"
+
+// CanonicalRequest is the canonical form of a request used when computing signatures.
+//
+// The canonical form is:
+//
+//	METHOD \n
+//	CANONICAL PATH \n
+//	CANONICAL QUERY \n
+//	CANONICAL HEADERS \n
+//	SIGNED HEADERS \n
+//	PAYLOAD HASH
+//
+// This is the form used by AWS Signature Version 4, and by the `HMACSigner`.
+type CanonicalRequest struct {
+	Method        string
+	Path          string
+	Query         string
+	Headers       string
+	SignedHeaders string
+	PayloadHash   string
+}
+
+// String returns the canonical request string.
+func (cr CanonicalRequest) String() string {
+	return strings.Join([]string{
+		cr.Method,
+		cr.Path,
+		cr.Query,
+		cr.Headers,
+		cr.SignedHeaders,
+		cr.PayloadHash,
+	}, "\n")
+}
+
+// Hash returns the hex encoded sha256 hash of the canonical request string.
+func (cr CanonicalRequest) Hash() string {
+	return hashHex([]byte(cr.String()))
+}
+
+// canonicalPath returns the canonical path of a url, uri encoding each segment.
+// If `escapeTwice` is set, the encoded path is encoded again, as required by most aws services (except s3).
+func canonicalPath(u *url.URL, escapeTwice bool) string {
+	path := u.Path
+	if path == "" {
+		return "/"
+	}
+	segments := strings.Split(path, "/")
+	for index, segment := range segments {
+		segments[index] = uriEncode(segment)
+		if escapeTwice {
+			segments[index] = uriEncode(segments[index])
+		}
+	}
+	return strings.Join(segments, "/")
+}
+
+// canonicalQuery returns the canonical querystring, sorted by key and then value.
+// The pairs are sorted by their encoded key and then encoded value rather than as whole strings, so that
+// a key that is a prefix of another key (e.g. `a` and `a1`) sorts first.
+func canonicalQuery(query url.Values) string {
+	var pairs [][2]string
+	for key, values := range query {
+		for _, value := range values {
+			pairs = append(pairs, [2]string{uriEncode(key), uriEncode(value)})
+		}
+	}
+	sort.Slice(pairs, func(i, j int) bool {
+		if pairs[i][0] != pairs[j][0] {
+			return pairs[i][0] < pairs[j][0]
+		}
+		return pairs[i][1] < pairs[j][1]
+	})
+	output := make([]string, 0, len(pairs))
+	for _, pair := range pairs {
+		output = append(output, pair[0]+"="+pair[1])
+	}
+	return strings.Join(output, "&")
+}
+
+// canonicalHeaders returns the canonical headers string and the signed headers list
+// for a given set of (lowercase) header names.
+func canonicalHeaders(req *http.Request, names []string) (headers, signedHeaders string) {
+	sorted := make([]string, 0, len(names))
+	seen := make(map[string]bool)
+	for _, name := range names {
+		name = strings.ToLower(strings.TrimSpace(name))
+		if name == "" || seen[name] {
+			continue
+		}
+		seen[name] = true
+		sorted = append(sorted, name)
+	}
+	sort.Strings(sorted)
+
+	var output strings.Builder
+	for _, name := range sorted {
+		output.WriteString(name)
+		output.WriteString(":")
+		if name == "host" {
+			output.WriteString(requestHost(req))
+		} else {
+			values := req.Header[http.CanonicalHeaderKey(name)]
+			trimmed := make([]string, 0, len(values))
+			for _, value := range values {
+				trimmed = append(trimmed, strings.Join(strings.Fields(value), " "))
+			}
+			output.WriteString(strings.Join(trimmed, ","))
+		}
+		output.WriteString("\n")
+	}
+	headers = output.String()
+	signedHeaders = strings.Join(sorted, ";")
+	return
+}
+
+// requestHost returns the host of a request, preferring the host override.
+func requestHost(req *http.Request) string {
+	if req.Host != "" {
+		return req.Host
+	}
+	if req.URL != nil {
+		return req.URL.Host
+	}
+	return ""
+}
+
+// payloadHash returns the hex encoded sha256 hash of the request body.
+// The body is read with `GetBody` if set, otherwise it is read fully and replaced.
+func payloadHash(req *http.Request) (string, error) {
+	if req.Body == nil || req.Body == http.NoBody {
+		return EmptyPayloadHash, nil
+	}
+	var body io.ReadCloser
+	if req.GetBody != nil {
+		var err error
+		if body, err = req.GetBody(); err != nil {
+			return "", ex.New(err)
+		}
+	} else {
+		contents, err := ioutil.ReadAll(req.Body)
+		if err != nil {
+			return "", ex.New(err)
+		}
+		req.Body = ioutil.NopCloser(bytes.NewReader(contents))
+		req.GetBody = func() (io.ReadCloser, error) {
+			return ioutil.NopCloser(bytes.NewReader(contents)), nil
+		}
+		body = ioutil.NopCloser(bytes.NewReader(contents))
+	}
+	defer body.Close()
+	hash := sha256.New()
+	if _, err := io.Copy(hash, body); err != nil {
+		return "", ex.New(err)
+	}
+	return hex.EncodeToString(hash.Sum(nil)), nil
+}
+
+// uriEncode encodes a value per RFC 3986, leaving only unreserved characters unencoded.
+func uriEncode(value string) string {
+	var output strings.Builder
+	for _, b := range []byte(value) {
+		if (b >= 'A' && b <= 'Z') || (b >= 'a' && b <= 'z') || (b >= '0' && b <= '9') || b == '-' || b == '_' || b == '.' || b == '~' {
+			output.WriteByte(b)
+			continue
+		}
+		output.WriteByte('%')
+		output.WriteString(strings.ToUpper(hex.EncodeToString([]byte{b})))
+	}
+	return output.String()
+}
+
+func hashHex(data []byte) string {
+	hash := sha256.Sum256(data)
+	return hex.EncodeToString(hash[:])
+}
+
+func hmacSHA256(key, data []byte) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write(data)
+	return mac.Sum(nil)
+}
@@ -0,0 +1,24 @@
+package r2
+
+import (
+	"net/url"
+	"testing"
+
+	"github.com/blend/go-sdk/assert"
+)
+
+func TestCanonicalQuery(t *testing.T) {
+	assert := assert.New(t)
+
+	assert.Empty(canonicalQuery(nil))
+	assert.Equal("a=2&a1=1&b=y&b-c=x", canonicalQuery(url.Values{
+		"a":   {"2"},
+		"a1":  {"1"},
+		"b":   {"y"},
+		"b-c": {"x"},
+	}))
+	assert.Equal("a=1&a=2&b=a%20b", canonicalQuery(url.Values{
+		"a": {"2", "1"},
+		"b": {"a b"},
+	}))
+}
@@ -0,0 +1,133 @@
+package r2
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/blend/go-sdk/ex"
+	"github.com/blend/go-sdk/secrets"
+)
+
+var (
+	_ TokenSource = (*ClientCredentials)(nil)
+)
+
+// SecretProvider returns a secret value, e.g. a client secret.
+type SecretProvider func(context.Context) (string, error)
+
+// SecretFromKV returns a secret provider that reads a given field of a key from a secrets kv store.
+// The secret is read each time a token is fetched, so rotated secrets are picked up.
+func SecretFromKV(kv secrets.KV, key, field string) SecretProvider {
+	return func(ctx context.Context) (string, error) {
+		values, err := kv.Get(ctx, key)
+		if err != nil {
+			return "", err
+		}
+		value, ok := values[field]
+		if !ok {
+			return "", ex.New(ErrSecretFieldMissing, ex.OptMessagef("key: %s, field: %s", key, field))
+		}
+		return fmt.Sprint(value), nil
+	}
+}
+
+// ClientCredentials is a token source that fetches tokens with the oauth2
+// client credentials grant (RFC 6749 section 4.4).
+//
+// It fetches a new token every time it is called; wrap it with `NewCachingTokenSource`
+// (as `OptClientCredentials` does) to cache and proactively refresh tokens.
+type ClientCredentials struct {
+	// TokenURL is the token endpoint url.
+	TokenURL string
+	// ClientID is the client id.
+	ClientID string
+	// ClientSecret is the client secret.
+	ClientSecret string
+	// ClientSecretProvider provides the client secret, and takes precedence over `ClientSecret` if set.
+	ClientSecretProvider SecretProvider
+	// Scopes are the requested scopes.
+	Scopes []string
+	// EndpointParams are additional form values sent to the token endpoint, e.g. `audience`.
+	EndpointParams url.Values
+	// AuthInParams sends the client id and secret as form values instead of with basic auth.
+	AuthInParams bool
+	// Options are additional options for the token request, e.g. timeouts or tls settings.
+	Options []Option
+}
+
+// clientCredentialsResponse is the token endpoint response.
+type clientCredentialsResponse struct {
+	AccessToken string `json:"access_token"`
+	TokenType   string `json:"token_type"`
+	ExpiresIn   int64  `json:"expires_in"`
+}
+
+// Token implements TokenSource.
+func (cc ClientCredentials) Token(ctx context.Context) (*Token, error) {
+	clientSecret := cc.ClientSecret
+	if cc.ClientSecretProvider != nil {
+		var err error
+		if clientSecret, err = cc.ClientSecretProvider(ctx); err != nil {
+			return nil, err
+		}
+	}
+
+	form := url.Values{}
+	for key, values := range cc.EndpointParams {
+		form[key] = values
+	}
+	form.Set("grant_type", "client_credentials")
+	if len(cc.Scopes) > 0 {
+		form.Set("scope", strings.Join(cc.Scopes, " "))
+	}
+
+	options := []Option{
+		OptPost(),
+		OptContext(ctx),
+		OptHeaderValue("Accept", ContentTypeApplicationJSON),
+	}
+	if cc.AuthInParams {
+		form.Set("client_id", cc.ClientID)
+		form.Set("client_secret", clientSecret)
+	} else {
+		options = append(options, OptBasicAuth(url.QueryEscape(cc.ClientID), url.QueryEscape(clientSecret)))
+	}
+	options = append(options, OptPostForm(form))
+	options = append(options, cc.Options...)
+
+	started := time.Now().UTC()
+	contents, res, err := New(cc.TokenURL, options...).Bytes()
+	if err != nil {
+		return nil, err
+	}
+	if res.StatusCode < 200 || res.StatusCode > 299 {
+		return nil, ex.New(ErrTokenRequestFailed, ex.OptMessagef("status code: %d, body: %s", res.StatusCode, truncate(string(contents), 512)))
+	}
+
+	var response clientCredentialsResponse
+	if err := json.Unmarshal(contents, &response); err != nil {
+		return nil, ex.New(err)
+	}
+	if response.AccessToken == "" {
+		return nil, ex.New(ErrTokenEmpty)
+	}
+	token := &Token{
+		AccessToken: response.AccessToken,
+		TokenType:   response.TokenType,
+	}
+	if response.ExpiresIn > 0 {
+		token.Expiry = started.Add(time.Duration(response.ExpiresIn) * time.Second)
+	}
+	return token, nil
+}
+
+func truncate(value string, length int) string {
+	if len(value) > length {
+		return value[:length]
+	}
+	return value
+}
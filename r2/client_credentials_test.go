@@ -0,0 +1,99 @@
+package r2
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/blend/go-sdk/assert"
+	"github.com/blend/go-sdk/ex"
+	"github.com/blend/go-sdk/secrets"
+)
+
+func mockTokenServer(clientSecret string) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		clientID, secret, ok := r.BasicAuth()
+		if !ok {
+			clientID, secret = r.FormValue("client_id"), r.FormValue("client_secret")
+		}
+		if clientID != "client" || secret != clientSecret || r.FormValue("grant_type") != "client_credentials" {
+			rw.WriteHeader(http.StatusUnauthorized)
+			fmt.Fprint(rw, `{"error":"invalid_client"}`)
+			return
+		}
+		rw.Header().Set(HeaderContentType, ContentTypeApplicationJSON)
+		fmt.Fprintf(rw, `{"access_token":"token-%s","token_type":"bearer","expires_in":3600}`, r.FormValue("scope"))
+	}))
+}
+
+func TestClientCredentials(t *testing.T) {
+	assert := assert.New(t)
+
+	server := mockTokenServer("secret")
+	defer server.Close()
+
+	cc := ClientCredentials{
+		TokenURL:     server.URL,
+		ClientID:     "client",
+		ClientSecret: "secret",
+		Scopes:       []string{"read", "write"},
+	}
+	token, err := cc.Token(context.Background())
+	assert.Nil(err)
+	assert.Equal("token-read write", token.AccessToken)
+	assert.Equal("Bearer token-read write", token.HeaderValue())
+	assert.True(token.Expiry.After(time.Now().UTC().Add(59 * time.Minute)))
+
+	cc.AuthInParams = true
+	token, err = cc.Token(context.Background())
+	assert.Nil(err)
+	assert.NotEmpty(token.AccessToken)
+
+	cc.ClientSecret = "not-secret"
+	_, err = cc.Token(context.Background())
+	assert.True(ex.Is(err, ErrTokenRequestFailed))
+}
+
+type mockKV map[string]secrets.Values
+
+func (m mockKV) Put(_ context.Context, key string, data secrets.Values, _ ...secrets.RequestOption) error {
+	m[key] = data
+	return nil
+}
+
+func (m mockKV) Get(_ context.Context, key string, _ ...secrets.RequestOption) (secrets.Values, error) {
+	return m[key], nil
+}
+
+func (m mockKV) Delete(_ context.Context, key string, _ ...secrets.RequestOption) error {
+	delete(m, key)
+	return nil
+}
+
+func (m mockKV) List(_ context.Context, _ string, _ ...secrets.RequestOption) ([]string, error) {
+	return nil, nil
+}
+
+func TestClientCredentialsSecretFromKV(t *testing.T) {
+	assert := assert.New(t)
+
+	server := mockTokenServer("from-vault")
+	defer server.Close()
+
+	kv := mockKV{"secret/client": secrets.Values{"client_secret": "from-vault"}}
+	cc := ClientCredentials{
+		TokenURL:             server.URL,
+		ClientID:             "client",
+		ClientSecretProvider: SecretFromKV(kv, "secret/client", "client_secret"),
+	}
+	token, err := cc.Token(context.Background())
+	assert.Nil(err)
+	assert.Equal("token-", token.AccessToken)
+
+	cc.ClientSecretProvider = SecretFromKV(kv, "secret/client", "not_a_field")
+	_, err = cc.Token(context.Background())
+	assert.True(ex.Is(err, ErrSecretFieldMissing))
+}
@@ -22,6 +22,8 @@ const (
 	HeaderConnection = "Connection"
 	// HeaderContentType is a http header.
 	HeaderContentType = "Content-Type"
+	// HeaderLink is a http header.
+	HeaderLink = "Link"
 )
 
 const (
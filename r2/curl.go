@@ -0,0 +1,147 @@
+package r2
+
+import (
+	"bytes"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"sort"
+	"strings"
+)
+
+// CurlRedacted is the value written in place of redacted header values.
+const CurlRedacted = "<redacted>"
+
+// DefaultCurlRedactedHeaders are the headers whose values are redacted by default
+// when rendering a request as a curl command.
+var DefaultCurlRedactedHeaders = []string{
+	"Authorization",
+	"Proxy-Authorization",
+	"Cookie",
+	"X-Api-Key",
+	"X-Auth-Token",
+}
+
+// CurlOptions are options for rendering a request as a curl command.
+type CurlOptions struct {
+	// RedactedHeaders are the header names whose values are replaced with `CurlRedacted`.
+	RedactedHeaders []string
+}
+
+// CurlOption mutates curl options.
+type CurlOption func(*CurlOptions)
+
+// OptCurlRedactHeaders adds headers to the set of redacted headers.
+func OptCurlRedactHeaders(headers ...string) CurlOption {
+	return func(co *CurlOptions) {
+		co.RedactedHeaders = append(co.RedactedHeaders, headers...)
+	}
+}
+
+// OptCurlUnredacted disables header redaction entirely.
+// Use with care; the output will include credentials.
+func OptCurlUnredacted() CurlOption {
+	return func(co *CurlOptions) {
+		co.RedactedHeaders = nil
+	}
+}
+
+// Curl renders the request as a reproducible curl command.
+//
+// Header values for `DefaultCurlRedactedHeaders` are redacted unless `OptCurlUnredacted` is passed.
+// If the request body cannot be re-read (i.e. `GetBody` is unset) it is buffered
+// and replaced so the request can still be sent afterwards.
+func (r *Request) Curl(options ...CurlOption) string {
+	if r.Err != nil {
+		return ""
+	}
+	if r.Request.Body != nil && r.Request.GetBody == nil {
+		contents, err := ioutil.ReadAll(r.Request.Body)
+		if err == nil {
+			r.Request.Body = ioutil.NopCloser(bytes.NewReader(contents))
+			r.Request.GetBody = func() (io.ReadCloser, error) {
+				return ioutil.NopCloser(bytes.NewReader(contents)), nil
+			}
+		}
+	}
+	return curlCommand(&r.Request, options...)
+}
+
+// curlCommand renders an http request as a curl command.
+// It will only include the body if it can be re-read with `GetBody`, or
+// if it is described by the post form.
+func curlCommand(req *http.Request, options ...CurlOption) string {
+	co := CurlOptions{
+		RedactedHeaders: append([]string(nil), DefaultCurlRedactedHeaders...),
+	}
+	for _, option := range options {
+		option(&co)
+	}
+
+	redacted := make(map[string]bool)
+	for _, header := range co.RedactedHeaders {
+		redacted[http.CanonicalHeaderKey(header)] = true
+	}
+
+	body, hasBody := curlBody(req)
+
+	// curl sends a POST if there is a body and a GET otherwise, so the method is
+	// set explicitly unless it's a GET without a body.
+	args := []string{"curl"}
+	if method := req.Method; (method != "" && method != MethodGet) || hasBody {
+		if method == "" {
+			method = MethodGet
+		}
+		args = append(args, "-X", method)
+	}
+	if req.URL != nil {
+		args = append(args, shellQuote(req.URL.String()))
+	}
+	if req.Host != "" && (req.URL == nil || req.Host != req.URL.Host) {
+		args = append(args, "-H", shellQuote("Host: "+req.Host))
+	}
+
+	keys := make([]string, 0, len(req.Header))
+	for key := range req.Header {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		for _, value := range req.Header[key] {
+			if redacted[http.CanonicalHeaderKey(key)] {
+				value = CurlRedacted
+			}
+			args = append(args, "-H", shellQuote(key+": "+value))
+		}
+	}
+
+	if hasBody {
+		args = append(args, "--data-raw", shellQuote(body))
+	}
+	return strings.Join(args, " ")
+}
+
+// curlBody returns the request body without consuming it.
+func curlBody(req *http.Request) (string, bool) {
+	if req.GetBody != nil {
+		body, err := req.GetBody()
+		if err != nil {
+			return "", false
+		}
+		defer body.Close()
+		contents, err := ioutil.ReadAll(body)
+		if err != nil || len(contents) == 0 {
+			return "", false
+		}
+		return string(contents), true
+	}
+	if len(req.PostForm) > 0 {
+		return req.PostForm.Encode(), true
+	}
+	return "", false
+}
+
+// shellQuote single quotes a value for use in a posix shell.
+func shellQuote(value string) string {
+	return "'" + strings.Replace(value, "'", `'\''`, -1) + "'"
+}
@@ -0,0 +1,78 @@
+package r2
+
+import (
+	"io/ioutil"
+	"strings"
+	"testing"
+
+	"github.com/blend/go-sdk/assert"
+)
+
+func TestRequestCurl(t *testing.T) {
+	assert := assert.New(t)
+
+	r := New("https://foo.bar.local/buzz?a=b",
+		OptPost(),
+		OptHeaderValue("X-Foo", "it's bar"),
+		OptBasicAuth("user", "pass"),
+		OptBodyBytes([]byte(`{"foo":"bar"}`)),
+	)
+	assert.Nil(r.Err)
+
+	expected := `curl -X POST 'https://foo.bar.local/buzz?a=b' -H 'Authorization: <redacted>' -H 'X-Foo: it'\''s bar' --data-raw '{"foo":"bar"}'`
+	assert.Equal(expected, r.Curl())
+
+	unredacted := r.Curl(OptCurlUnredacted())
+	assert.Contains(unredacted, "Basic dXNlcjpwYXNz")
+
+	redacted := r.Curl(OptCurlRedactHeaders("X-Foo"))
+	assert.Contains(redacted, `-H 'X-Foo: <redacted>'`)
+}
+
+func TestRequestCurlBuffersBody(t *testing.T) {
+	assert := assert.New(t)
+
+	server := mockServerOK()
+	defer server.Close()
+
+	r := New(server.URL, OptPost(), OptBody(ioutil.NopCloser(strings.NewReader("this is a test"))))
+	assert.Contains(r.Curl(), `--data-raw 'this is a test'`)
+	assert.NotNil(r.GetBody)
+
+	res, err := r.Do()
+	assert.Nil(err)
+	assert.Equal(200, res.StatusCode)
+}
+
+func TestRequestCurlPostForm(t *testing.T) {
+	assert := assert.New(t)
+
+	r := New("https://foo.bar.local", OptPost(), OptPostFormValue("foo", "bar"))
+	assert.Equal(`curl -X POST 'https://foo.bar.local' -H 'Content-Type: application/x-www-form-urlencoded' --data-raw 'foo=bar'`, r.Curl())
+}
+
+func TestRequestCurlMethod(t *testing.T) {
+	assert := assert.New(t)
+
+	assert.Equal(`curl 'https://foo.bar.local'`, New("https://foo.bar.local").Curl())
+	assert.Equal(`curl -X DELETE 'https://foo.bar.local'`, New("https://foo.bar.local", OptDelete()).Curl())
+	assert.Equal(`curl -X GET 'https://foo.bar.local' --data-raw 'foo'`, New("https://foo.bar.local", OptGet(), OptBodyBytes([]byte("foo"))).Curl())
+}
+
+func TestRequestCurlErr(t *testing.T) {
+	assert := assert.New(t)
+	assert.Empty(New("\n").Curl())
+}
+
+func TestRequestCurlRedactHeadersDefaults(t *testing.T) {
+	assert := assert.New(t)
+
+	defaults := DefaultCurlRedactedHeaders
+	defer func() { DefaultCurlRedactedHeaders = defaults }()
+	DefaultCurlRedactedHeaders = append(make([]string, 0, 8), "Authorization")
+
+	r := New("https://foo.bar.local", OptHeaderValue("X-Foo", "foo"), OptHeaderValue("X-Bar", "bar"))
+	assert.Contains(r.Curl(OptCurlRedactHeaders("X-Foo")), `-H 'X-Foo: <redacted>'`)
+	assert.Contains(r.Curl(OptCurlRedactHeaders("X-Bar")), `-H 'X-Foo: foo'`)
+	assert.Empty(DefaultCurlRedactedHeaders[:2][1], "the defaults should not be appended to")
+}
@@ -6,6 +6,15 @@ import (
 
 // Error Constants
 const (
-	ErrNoContentJSON ex.Class = "server returned an http 204 for a request expecting json"
-	ErrNoContentXML  ex.Class = "server returned an http 204 for a request expecting xml"
+	ErrNoContentJSON              ex.Class = "server returned an http 204 for a request expecting json"
+	ErrNoContentXML               ex.Class = "server returned an http 204 for a request expecting xml"
+	ErrCurlInvalid                ex.Class = "invalid curl command"
+	ErrCurlUnsupportedFlag        ex.Class = "unsupported curl flag"
+	ErrCurlMissingURL             ex.Class = "curl command is missing a url"
+	ErrTokenRequestFailed         ex.Class = "token request failed"
+	ErrTokenEmpty                 ex.Class = "token source returned an empty token"
+	ErrSecretFieldMissing         ex.Class = "secret field missing"
+	ErrSignerURLUnset             ex.Class = "cannot sign a request without a url"
+	ErrSignerPresignExpiry        ex.Class = "presigned url expiry must be positive and at most 7 days"
+	ErrLimiterWouldExceedDeadline ex.Class = "rate limit wait would exceed the context deadline"
 )
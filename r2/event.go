@@ -1,6 +1,8 @@
 package r2
 
 import (
+	"bytes"
+	"encoding/json"
 	"fmt"
 	"io"
 	"net/http"
@@ -12,11 +14,18 @@ import (
 	"github.com/blend/go-sdk/webutil"
 )
 
+var (
+	_ logger.Event      = (*Event)(nil)
+	_ logger.Redactable = (*Event)(nil)
+)
+
 const (
 	// Flag is a logger event flag.
 	Flag = "http.client.request"
 	// FlagResponse is a logger event flag.
 	FlagResponse = "http.client.response"
+	// FlagCurl is a logger event flag.
+	FlagCurl = "http.client.curl"
 )
 
 // NewEvent returns a new event.
@@ -41,6 +50,10 @@ type Event struct {
 	Body []byte
 	// Elapsed is the time elapsed.
 	Elapsed time.Duration
+	// Curl is the request rendered as a curl command.
+	Curl string
+	// Err is the error returned by the request, if any.
+	Err error
 }
 
 // GetFlag implements logger.Event.
@@ -53,6 +66,14 @@ func (e Event) WriteText(tf logger.TextFormatter, wr io.Writer) {
 	} else if e.Request != nil {
 		io.WriteString(wr, fmt.Sprintf("%s %s", e.Request.Method, e.Request.URL.String()))
 	}
+	if e.Err != nil {
+		io.WriteString(wr, logger.Space)
+		io.WriteString(wr, e.Err.Error())
+	}
+	if e.Curl != "" {
+		io.WriteString(wr, logger.Newline)
+		io.WriteString(wr, e.Curl)
+	}
 	if e.Body != nil {
 		io.WriteString(wr, logger.Newline)
 		io.WriteString(wr, string(e.Body))
@@ -87,10 +108,48 @@ func (e *Event) Decompose() map[string]interface{} {
 	if e.Body != nil {
 		output["body"] = string(e.Body)
 	}
+	if e.Curl != "" {
+		output["curl"] = e.Curl
+	}
+	if e.Err != nil {
+		output["err"] = e.Err.Error()
+	}
 
 	return output
 }
 
+// Redact implements logger.Redactable.
+//
+// The request and response headers and the request url are redacted, as is the body; json object bodies
+// have redacted keys replaced, and other bodies have the redaction patterns replaced.
+func (e Event) Redact(r *logger.Redactor) logger.Event {
+	e.Request = r.Request(e.Request)
+	if e.Response != nil {
+		response := *e.Response
+		response.Header = r.Header(e.Response.Header)
+		e.Response = &response
+	}
+	if e.Body != nil {
+		e.Body = redactBody(r, e.Body)
+	}
+	e.Curl = r.String(e.Curl)
+	e.Err = r.Error(e.Err)
+	return e
+}
+
+// redactBody returns a redacted copy of a request or response body.
+func redactBody(r *logger.Redactor, body []byte) []byte {
+	decoder := json.NewDecoder(bytes.NewReader(body))
+	decoder.UseNumber()
+	var fields map[string]interface{}
+	if err := decoder.Decode(&fields); err == nil && fields != nil && !decoder.More() {
+		if redacted, err := json.Marshal(r.Fields(fields)); err == nil {
+			return redacted
+		}
+	}
+	return []byte(r.String(string(body)))
+}
+
 // EventJSONSchema is the json schema of the logger event.
 type EventJSONSchema struct {
 	Req struct {
@@ -35,3 +35,17 @@ func OptEventBody(body []byte) EventOption {
 		e.Body = body
 	}
 }
+
+// OptEventCurl sets the curl command.
+func OptEventCurl(curl string) EventOption {
+	return func(e *Event) {
+		e.Curl = curl
+	}
+}
+
+// OptEventErr sets the error.
+func OptEventErr(err error) EventOption {
+	return func(e *Event) {
+		e.Err = err
+	}
+}
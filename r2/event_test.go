@@ -40,6 +40,33 @@ func TestEventWriteString(t *testing.T) {
 	assert.Equal("GET http://localhost/http://test.com 200 (1s)\nfoo", output2.String())
 }
 
+func TestEventRedact(t *testing.T) {
+	assert := assert.New(t)
+
+	redactor, err := logger.NewRedactor(logger.RedactionConfig{Enabled: true, Builtins: []string{logger.RedactBearerToken}})
+	assert.Nil(err)
+
+	req := webutil.NewMockRequest("GET", "/foo")
+	req.Header.Set("Authorization", "Bearer abcd")
+	e := NewEvent(FlagResponse,
+		OptEventRequest(req),
+		OptEventResponse(&http.Response{StatusCode: http.StatusOK, Header: http.Header{"Set-Cookie": {"session=abcd"}, "Content-Type": {"application/json"}}}),
+		OptEventBody([]byte(`{"password":"abcd","name":"foo","count":1}`)),
+	)
+	redacted, ok := e.Redact(redactor).(Event)
+	assert.True(ok)
+	assert.Equal("[REDACTED]", redacted.Request.Header.Get("Authorization"))
+	assert.Equal("[REDACTED]", redacted.Response.Header.Get("Set-Cookie"))
+	assert.Equal("application/json", redacted.Response.Header.Get("Content-Type"))
+	assert.Equal(`{"count":1,"name":"foo","password":"[REDACTED]"}`, string(redacted.Body))
+	assert.Equal("Bearer abcd", e.Request.Header.Get("Authorization"))
+	assert.Equal("session=abcd", e.Response.Header.Get("Set-Cookie"))
+
+	e.Body = []byte("token: Bearer abcd")
+	redacted = e.Redact(redactor).(Event)
+	assert.Equal("token: [REDACTED]", string(redacted.Body))
+}
+
 // eventJSONSchema is the json schema of the logger event.
 type eventJSONSchema struct {
 	Req struct {
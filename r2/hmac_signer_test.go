@@ -0,0 +1,65 @@
+package r2
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"testing"
+	"time"
+
+	"github.com/blend/go-sdk/assert"
+)
+
+func TestHMACSigner(t *testing.T) {
+	assert := assert.New(t)
+
+	signer := &HMACSigner{
+		KeyID:         "my-key",
+		Key:           []byte("my-secret"),
+		SignedHeaders: []string{"Content-Type"},
+		Now:           func() time.Time { return time.Date(2020, 1, 2, 3, 4, 5, 0, time.UTC) },
+	}
+	r := New("https://foo.bar.local/buzz/fuzz bar?b=2&a=1&a=0",
+		OptPost(),
+		OptSigner(signer),
+		OptHeaderValue("X-Not-Signed", "foo"),
+		OptJSONBody(map[string]string{"foo": "bar"}),
+	)
+	assert.Nil(r.Err)
+	sent, err := sentRequest(r)
+	assert.Nil(err)
+	assert.Equal("20200102T030405Z", sent.Header.Get(HeaderHMACDate))
+
+	canonical, err := signer.CanonicalRequest(sent)
+	assert.Nil(err)
+	assert.Equal("POST\n"+
+		"/buzz/fuzz%20bar\n"+
+		"a=0&a=1&b=2\n"+
+		"content-type:application/json; charset=UTF-8\n"+
+		"host:foo.bar.local\n"+
+		"x-date:20200102T030405Z\n"+
+		"\n"+
+		"content-type;host;x-date\n"+
+		"7a38bf81f383f69433ad6e900d35b3e2385593f76a7b7ab5d4355b8ba41ee24b", canonical.String())
+
+	mac := hmac.New(sha256.New, []byte("my-secret"))
+	mac.Write([]byte("HMAC-SHA256\n20200102T030405Z\n" + canonical.Hash()))
+	expected := hex.EncodeToString(mac.Sum(nil))
+	assert.Equal("HMAC-SHA256 KeyId=my-key, SignedHeaders=content-type;host;x-date, Signature="+expected, sent.Header.Get("Authorization"))
+	assert.Equal(`{"foo":"bar"}`, readString(sent.Body))
+}
+
+func TestHMACSignerPostForm(t *testing.T) {
+	assert := assert.New(t)
+
+	signer := &HMACSigner{KeyID: "my-key", Key: []byte("my-secret")}
+	r := New("https://foo.bar.local", OptPost(), OptPostFormValue("foo", "bar"), OptSigner(signer))
+	assert.Nil(r.Err)
+	sent, err := sentRequest(r)
+	assert.Nil(err)
+	assert.NotEmpty(sent.Header.Get("Authorization"))
+
+	canonical, err := signer.CanonicalRequest(sent)
+	assert.Nil(err)
+	assert.Equal(hashHex([]byte("foo=bar")), canonical.PayloadHash)
+}
@@ -0,0 +1,157 @@
+package r2
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+
+	"github.com/blend/go-sdk/ex"
+)
+
+// JSONStream executes the request and returns a stream that decodes the response body
+// one item at a time, without buffering the whole body.
+//
+// The body can either be newline delimited json (NDJSON), or a single json array; the
+// format is detected from the first non-whitespace character of the body.
+//
+// You must close the stream when finished with it:
+//
+//	stream, err := r2.New("https://api.example.com/things.ndjson").JSONStream()
+//	if err != nil {
+//		return err
+//	}
+//	defer stream.Close()
+//	for {
+//		var thing Thing
+//		if err := stream.Decode(&thing); err == io.EOF {
+//			break
+//		} else if err != nil {
+//			return err
+//		}
+//		// ...
+//	}
+func (r Request) JSONStream() (*JSONStream, error) {
+	res, err := r.Do()
+	if err != nil {
+		r.Close()
+		return nil, err
+	}
+	if res.StatusCode == http.StatusNoContent {
+		res.Body.Close()
+		r.Close()
+		return nil, ex.New(ErrNoContentJSON)
+	}
+	stream := NewJSONStream(r.Context(), res.Body)
+	stream.Response = res
+	stream.Closer = r.Closer
+	return stream, nil
+}
+
+// NewJSONStream returns a new json stream for a given body.
+// The context is checked for cancellation before each item is decoded.
+func NewJSONStream(ctx context.Context, body io.ReadCloser) *JSONStream {
+	return &JSONStream{
+		Context: ctx,
+		Body:    body,
+	}
+}
+
+// JSONStream decodes a stream of json items, either as NDJSON or as a json array.
+type JSONStream struct {
+	// Context is checked for cancellation before each item is decoded.
+	Context context.Context
+	// Body is the underlying stream.
+	Body io.ReadCloser
+	// Response is the response metadata, if the stream was created from a request.
+	Response *http.Response
+	// Closer is an optional step to run when the stream is closed.
+	Closer func() error
+
+	decoder *json.Decoder
+	isArray bool
+	done    bool
+}
+
+// Decode decodes the next item into a given object.
+// It returns `io.EOF` once there are no more items.
+func (js *JSONStream) Decode(dst interface{}) error {
+	if js.done {
+		return io.EOF
+	}
+	if js.Context != nil {
+		if err := js.Context.Err(); err != nil {
+			return ex.New(err)
+		}
+	}
+	if js.decoder == nil {
+		if err := js.start(); err != nil {
+			js.done = true
+			return err
+		}
+	}
+	if !js.decoder.More() {
+		js.done = true
+		if js.isArray {
+			if _, err := js.decoder.Token(); err != nil {
+				return ex.New(err)
+			}
+		}
+		return io.EOF
+	}
+	if err := js.decoder.Decode(dst); err != nil {
+		js.done = true
+		if err == io.EOF {
+			return io.EOF
+		}
+		return ex.New(err)
+	}
+	return nil
+}
+
+// Close closes the underlying body.
+func (js *JSONStream) Close() error {
+	var err error
+	if js.Body != nil {
+		err = js.Body.Close()
+	}
+	if js.Closer != nil {
+		if closerErr := js.Closer(); closerErr != nil && err == nil {
+			err = closerErr
+		}
+	}
+	return ex.New(err)
+}
+
+// start detects the stream format and initializes the decoder.
+func (js *JSONStream) start() error {
+	reader := bufio.NewReader(js.Body)
+	for {
+		b, err := reader.Peek(1)
+		if err == io.EOF {
+			js.decoder = json.NewDecoder(reader)
+			return nil
+		}
+		if err != nil {
+			return ex.New(err)
+		}
+		switch b[0] {
+		case ' ', '\t', '\r', '\n':
+			if _, err = reader.Discard(1); err != nil {
+				return ex.New(err)
+			}
+			continue
+		case '[':
+			js.isArray = true
+			js.decoder = json.NewDecoder(reader)
+			if _, err = js.decoder.Token(); err != nil {
+				return ex.New(err)
+			}
+			return nil
+		default:
+			js.decoder = json.NewDecoder(reader)
+			return nil
+		}
+	}
+}
@@ -0,0 +1,106 @@
+package r2
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/blend/go-sdk/assert"
+)
+
+type jsonStreamItem struct {
+	ID int `json:"id"`
+}
+
+func decodeAll(js *JSONStream) ([]int, error) {
+	var ids []int
+	for {
+		var item jsonStreamItem
+		if err := js.Decode(&item); err == io.EOF {
+			return ids, nil
+		} else if err != nil {
+			return ids, err
+		}
+		ids = append(ids, item.ID)
+	}
+}
+
+func TestJSONStreamNDJSON(t *testing.T) {
+	assert := assert.New(t)
+
+	js := NewJSONStream(context.Background(), ioutil.NopCloser(strings.NewReader("{\"id\":1}\n{\"id\":2}\n\n{\"id\":3}\n")))
+	ids, err := decodeAll(js)
+	assert.Nil(err)
+	assert.Equal([]int{1, 2, 3}, ids)
+	assert.Nil(js.Close())
+}
+
+func TestJSONStreamArray(t *testing.T) {
+	assert := assert.New(t)
+
+	js := NewJSONStream(context.Background(), ioutil.NopCloser(strings.NewReader("  \n[{\"id\":1}, {\"id\":2},{\"id\":3}]")))
+	ids, err := decodeAll(js)
+	assert.Nil(err)
+	assert.Equal([]int{1, 2, 3}, ids)
+}
+
+func TestJSONStreamEmpty(t *testing.T) {
+	assert := assert.New(t)
+
+	ids, err := decodeAll(NewJSONStream(context.Background(), ioutil.NopCloser(strings.NewReader(""))))
+	assert.Nil(err)
+	assert.Empty(ids)
+
+	ids, err = decodeAll(NewJSONStream(context.Background(), ioutil.NopCloser(strings.NewReader("[]"))))
+	assert.Nil(err)
+	assert.Empty(ids)
+}
+
+func TestJSONStreamInvalid(t *testing.T) {
+	assert := assert.New(t)
+
+	ids, err := decodeAll(NewJSONStream(context.Background(), ioutil.NopCloser(strings.NewReader(`[{"id":1},{"id":`))))
+	assert.NotNil(err)
+	assert.Equal([]int{1}, ids)
+}
+
+func TestJSONStreamContextCancelled(t *testing.T) {
+	assert := assert.New(t)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	js := NewJSONStream(ctx, ioutil.NopCloser(strings.NewReader("{\"id\":1}\n{\"id\":2}\n")))
+	var item jsonStreamItem
+	assert.Nil(js.Decode(&item))
+	cancel()
+	assert.NotNil(js.Decode(&item))
+}
+
+func TestRequestJSONStream(t *testing.T) {
+	assert := assert.New(t)
+
+	server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		rw.WriteHeader(http.StatusOK)
+		for x := 0; x < 100; x++ {
+			fmt.Fprintf(rw, "{\"id\":%d}\n", x)
+			rw.(http.Flusher).Flush()
+		}
+	}))
+	defer server.Close()
+
+	var closed bool
+	r := New(server.URL)
+	r.Closer = func() error { closed = true; return nil }
+	js, err := r.JSONStream()
+	assert.Nil(err)
+	assert.Equal(http.StatusOK, js.Response.StatusCode)
+	ids, err := decodeAll(js)
+	assert.Nil(err)
+	assert.Len(ids, 100)
+	assert.Nil(js.Close())
+	assert.True(closed)
+}
@@ -0,0 +1,211 @@
+package r2
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/blend/go-sdk/ex"
+	"github.com/blend/go-sdk/stats"
+)
+
+// Limiter metric names and tags.
+const (
+	MetricNameLimiterWait     = "http.client.limiter.wait"
+	MetricNameLimiterRejected = "http.client.limiter.rejected"
+	TagHost                   = "host"
+)
+
+// NewLimiter returns a new limiter with a given set of options.
+// A limiter with no options does not limit requests.
+func NewLimiter(options ...LimiterOption) *Limiter {
+	l := &Limiter{
+		hosts: make(map[string]*hostLimiter),
+	}
+	for _, option := range options {
+		option(l)
+	}
+	return l
+}
+
+// LimiterOption mutates a limiter.
+type LimiterOption func(*Limiter)
+
+// OptLimiterRate sets the sustained request rate per second, and the burst, per host.
+func OptLimiterRate(perSecond float64, burst int) LimiterOption {
+	return func(l *Limiter) {
+		l.Rate = perSecond
+		l.Burst = burst
+	}
+}
+
+// OptLimiterMaxInFlight sets the maximum number of in flight requests per host.
+func OptLimiterMaxInFlight(maxInFlight int) LimiterOption {
+	return func(l *Limiter) {
+		l.MaxInFlight = maxInFlight
+	}
+}
+
+// OptLimiterCollector sets the stats collector used to record wait times and rejections.
+func OptLimiterCollector(collector stats.Collector) LimiterOption {
+	return func(l *Limiter) {
+		l.Collector = collector
+	}
+}
+
+// Limiter limits the rate and concurrency of outgoing requests per destination host.
+//
+// Each host has a token bucket that refills at `Rate` tokens per second up to `Burst` tokens,
+// and a semaphore that allows at most `MaxInFlight` concurrent requests.
+//
+// If acquiring a slot requires waiting, the limiter waits unless the wait would
+// exceed the context deadline, in which case it fails immediately with `ErrLimiterWouldExceedDeadline`.
+// If the context is cancelled while waiting, it returns the context error.
+//
+// A limiter should be created once and shared between requests, e.g. with `OptLimiter` in a `Defaults` set.
+type Limiter struct {
+	// Rate is the sustained number of requests per second per host; zero disables rate limiting.
+	Rate float64
+	// Burst is the number of requests that can be made at once before being limited to `Rate`.
+	// If it is less than one it is treated as one.
+	Burst int
+	// MaxInFlight is the maximum number of concurrent requests per host; zero disables the limit.
+	MaxInFlight int
+	// Collector is an optional stats collector for wait times and rejections.
+	Collector stats.Collector
+
+	mu    sync.Mutex
+	hosts map[string]*hostLimiter
+}
+
+// Acquire waits for a request slot for a given host.
+// The returned release function must be called when the request is complete.
+func (l *Limiter) Acquire(ctx context.Context, host string) (release func(), err error) {
+	started := time.Now()
+	hl := l.host(host)
+
+	defer func() {
+		l.record(host, time.Since(started), err)
+	}()
+
+	if err = hl.waitToken(ctx, l.Rate, l.burst()); err != nil {
+		return nil, err
+	}
+	if hl.sem == nil {
+		return func() {}, nil
+	}
+
+	select {
+	case hl.sem <- struct{}{}:
+	case <-ctx.Done():
+		if l.Rate > 0 {
+			hl.returnToken()
+		}
+		return nil, ex.New(ctx.Err())
+	}
+
+	var once sync.Once
+	return func() {
+		once.Do(func() { <-hl.sem })
+	}, nil
+}
+
+// InFlight returns the number of in flight requests for a host.
+// It always returns zero if `MaxInFlight` is unset.
+func (l *Limiter) InFlight(host string) int {
+	return len(l.host(host).sem)
+}
+
+func (l *Limiter) host(host string) *hostLimiter {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.hosts == nil {
+		l.hosts = make(map[string]*hostLimiter)
+	}
+	hl, ok := l.hosts[host]
+	if !ok {
+		hl = &hostLimiter{
+			tokens: float64(l.burst()),
+			last:   time.Now(),
+		}
+		if l.MaxInFlight > 0 {
+			hl.sem = make(chan struct{}, l.MaxInFlight)
+		}
+		l.hosts[host] = hl
+	}
+	return hl
+}
+
+func (l *Limiter) burst() int {
+	if l.Burst < 1 {
+		return 1
+	}
+	return l.Burst
+}
+
+func (l *Limiter) record(host string, waited time.Duration, err error) {
+	if l.Collector == nil {
+		return
+	}
+	tag := stats.Tag(TagHost, host)
+	if err != nil {
+		_ = l.Collector.Increment(MetricNameLimiterRejected, tag)
+		return
+	}
+	_ = l.Collector.TimeInMilliseconds(MetricNameLimiterWait, waited, tag)
+}
+
+// hostLimiter is the state for a single host.
+type hostLimiter struct {
+	mu     sync.Mutex
+	tokens float64
+	last   time.Time
+	sem    chan struct{}
+}
+
+// waitToken takes a token from the bucket, waiting for one to become available if necessary.
+func (hl *hostLimiter) waitToken(ctx context.Context, rate float64, burst int) error {
+	if rate <= 0 {
+		return nil
+	}
+
+	hl.mu.Lock()
+	now := time.Now()
+	hl.tokens += now.Sub(hl.last).Seconds() * rate
+	if hl.tokens > float64(burst) {
+		hl.tokens = float64(burst)
+	}
+	hl.last = now
+
+	var wait time.Duration
+	if hl.tokens < 1 {
+		wait = time.Duration((1 - hl.tokens) / rate * float64(time.Second))
+	}
+	if deadline, hasDeadline := ctx.Deadline(); hasDeadline && wait > 0 && now.Add(wait).After(deadline) {
+		hl.mu.Unlock()
+		return ex.New(ErrLimiterWouldExceedDeadline, ex.OptMessagef("wait: %v", wait))
+	}
+	// reserve the token; waiting callers queue up behind each other with a negative balance.
+	hl.tokens--
+	hl.mu.Unlock()
+
+	if wait <= 0 {
+		return nil
+	}
+	timer := time.NewTimer(wait)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		hl.returnToken()
+		return ex.New(ctx.Err())
+	}
+}
+
+// returnToken returns a token taken by `waitToken` to the bucket.
+func (hl *hostLimiter) returnToken() {
+	hl.mu.Lock()
+	hl.tokens++
+	hl.mu.Unlock()
+}
@@ -0,0 +1,148 @@
+package r2
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/blend/go-sdk/assert"
+	"github.com/blend/go-sdk/ex"
+	"github.com/blend/go-sdk/stats"
+)
+
+func TestLimiterUnlimited(t *testing.T) {
+	assert := assert.New(t)
+
+	l := NewLimiter()
+	for x := 0; x < 100; x++ {
+		release, err := l.Acquire(context.Background(), "foo.local")
+		assert.Nil(err)
+		release()
+	}
+}
+
+func TestLimiterRate(t *testing.T) {
+	assert := assert.New(t)
+
+	l := NewLimiter(OptLimiterRate(100, 2))
+
+	started := time.Now()
+	for x := 0; x < 4; x++ {
+		release, err := l.Acquire(context.Background(), "foo.local")
+		assert.Nil(err)
+		release()
+	}
+	// the burst of 2 is immediate, the next 2 are spaced 10ms apart.
+	assert.True(time.Since(started) >= 15*time.Millisecond)
+
+	// hosts have independent buckets.
+	started = time.Now()
+	release, err := l.Acquire(context.Background(), "bar.local")
+	assert.Nil(err)
+	release()
+	assert.True(time.Since(started) < 10*time.Millisecond)
+}
+
+func TestLimiterRateDeadline(t *testing.T) {
+	assert := assert.New(t)
+
+	l := NewLimiter(OptLimiterRate(1, 1))
+	release, err := l.Acquire(context.Background(), "foo.local")
+	assert.Nil(err)
+	release()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	started := time.Now()
+	_, err = l.Acquire(ctx, "foo.local")
+	assert.True(ex.Is(err, ErrLimiterWouldExceedDeadline))
+	assert.True(time.Since(started) < 10*time.Millisecond, "should fail without waiting")
+}
+
+func TestLimiterRateCancelled(t *testing.T) {
+	assert := assert.New(t)
+
+	l := NewLimiter(OptLimiterRate(1, 1))
+	release, err := l.Acquire(context.Background(), "foo.local")
+	assert.Nil(err)
+	release()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	go func() {
+		time.Sleep(5 * time.Millisecond)
+		cancel()
+	}()
+	_, err = l.Acquire(ctx, "foo.local")
+	assert.True(ex.Is(err, context.Canceled))
+}
+
+func TestLimiterMaxInFlight(t *testing.T) {
+	assert := assert.New(t)
+
+	l := NewLimiter(OptLimiterMaxInFlight(2))
+	first, err := l.Acquire(context.Background(), "foo.local")
+	assert.Nil(err)
+	second, err := l.Acquire(context.Background(), "foo.local")
+	assert.Nil(err)
+	assert.Equal(2, l.InFlight("foo.local"))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Millisecond)
+	defer cancel()
+	_, err = l.Acquire(ctx, "foo.local")
+	assert.True(ex.Is(err, context.DeadlineExceeded))
+
+	acquired := make(chan struct{})
+	go func() {
+		third, err := l.Acquire(context.Background(), "foo.local")
+		if err == nil {
+			third()
+		}
+		close(acquired)
+	}()
+	first()
+	first()
+	<-acquired
+	second()
+	assert.Zero(l.InFlight("foo.local"))
+}
+
+func TestLimiterMaxInFlightCancelledReturnsToken(t *testing.T) {
+	assert := assert.New(t)
+
+	l := NewLimiter(OptLimiterRate(1, 2), OptLimiterMaxInFlight(1))
+	release, err := l.Acquire(context.Background(), "foo.local")
+	assert.Nil(err)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Millisecond)
+	defer cancel()
+	_, err = l.Acquire(ctx, "foo.local")
+	assert.True(ex.Is(err, context.DeadlineExceeded))
+	release()
+
+	ctx, cancel = context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	release, err = l.Acquire(ctx, "foo.local")
+	assert.Nil(err, "the token taken by the cancelled acquire should have been returned")
+	release()
+}
+
+func TestLimiterCollector(t *testing.T) {
+	assert := assert.New(t)
+
+	collector := stats.NewMockCollector()
+	l := NewLimiter(OptLimiterRate(1, 1), OptLimiterCollector(collector))
+
+	release, err := l.Acquire(context.Background(), "foo.local")
+	assert.Nil(err)
+	release()
+	metric := <-collector.Events
+	assert.Equal(MetricNameLimiterWait, metric.Name)
+	assert.Equal([]string{"host:foo.local"}, metric.Tags)
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Millisecond)
+	defer cancel()
+	_, err = l.Acquire(ctx, "foo.local")
+	assert.NotNil(err)
+	metric = <-collector.Events
+	assert.Equal(MetricNameLimiterRejected, metric.Name)
+}
@@ -38,6 +38,25 @@ func readString(r io.Reader) string {
 	return string(contents)
 }
 
+// sentRequest sends a request with a transport that records it, and returns the request as it was sent.
+func sentRequest(r *Request) (*http.Request, error) {
+	var sent *http.Request
+	r.Client = &http.Client{Transport: roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+		sent = req
+		return &http.Response{StatusCode: http.StatusOK, Body: http.NoBody, Request: req}, nil
+	})}
+	if _, err := r.Discard(); err != nil {
+		return nil, err
+	}
+	return sent, nil
+}
+
+type roundTripperFunc func(*http.Request) (*http.Response, error)
+
+func (rtf roundTripperFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return rtf(req)
+}
+
 func mockServerOK() *httptest.Server {
 	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		w.WriteHeader(http.StatusOK)
@@ -0,0 +1,67 @@
+package r2
+
+import (
+	"io"
+	"net/http"
+	"sync"
+)
+
+var (
+	_ http.RoundTripper = (*LimiterTransport)(nil)
+)
+
+// OptLimiter limits the request with a given shared limiter, keyed by the url host.
+//
+// The request waits for a slot before it is sent, and holds its in flight slot until the response body is closed.
+// The client transport is wrapped when the request is sent, so options that set the transport or tls config
+// can be applied in any order.
+func OptLimiter(limiter *Limiter) Option {
+	return func(r *Request) error {
+		r.TransportWrappers = append(r.TransportWrappers, func(base http.RoundTripper) http.RoundTripper {
+			return &LimiterTransport{Base: base, Limiter: limiter}
+		})
+		return nil
+	}
+}
+
+// LimiterTransport is a round tripper that limits requests with a limiter.
+type LimiterTransport struct {
+	// Base is the underlying round tripper; if unset `http.DefaultTransport` is used.
+	Base http.RoundTripper
+	// Limiter is the limiter.
+	Limiter *Limiter
+}
+
+// RoundTrip implements http.RoundTripper.
+func (lt *LimiterTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	release, err := lt.Limiter.Acquire(req.Context(), req.URL.Host)
+	if err != nil {
+		closeRequestBody(req)
+		return nil, err
+	}
+	base := lt.Base
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	res, err := base.RoundTrip(req)
+	if err != nil || res.Body == nil {
+		release()
+		return res, err
+	}
+	res.Body = &releaseOnClose{ReadCloser: res.Body, release: release}
+	return res, nil
+}
+
+// releaseOnClose calls a release function when the body is closed.
+type releaseOnClose struct {
+	io.ReadCloser
+	once    sync.Once
+	release func()
+}
+
+// Close implements io.Closer.
+func (roc *releaseOnClose) Close() error {
+	err := roc.ReadCloser.Close()
+	roc.once.Do(roc.release)
+	return err
+}
@@ -0,0 +1,65 @@
+package r2
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/blend/go-sdk/assert"
+)
+
+func TestOptLimiter(t *testing.T) {
+	assert := assert.New(t)
+
+	server := mockServerOK()
+	defer server.Close()
+	serverURL, _ := url.Parse(server.URL)
+
+	limiter := NewLimiter(OptLimiterMaxInFlight(1))
+	res, err := New(server.URL, OptLimiter(limiter)).Do()
+	assert.Nil(err)
+	assert.Equal(1, limiter.InFlight(serverURL.Host), "the slot should be held until the body is closed")
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Millisecond)
+	defer cancel()
+	_, err = New(server.URL, OptContext(ctx), OptLimiter(limiter)).Do()
+	assert.NotNil(err)
+
+	assert.Nil(res.Body.Close())
+	assert.Zero(limiter.InFlight(serverURL.Host))
+
+	_, err = New(server.URL, OptLimiter(limiter)).Discard()
+	assert.Nil(err)
+	assert.Zero(limiter.InFlight(serverURL.Host))
+}
+
+func TestOptLimiterTransportOptions(t *testing.T) {
+	assert := assert.New(t)
+
+	server := mockServerOK()
+	defer server.Close()
+	serverURL, _ := url.Parse(server.URL)
+
+	limiter := NewLimiter(OptLimiterMaxInFlight(1))
+	res, err := New(server.URL, OptLimiter(limiter), OptTransport(&http.Transport{})).Do()
+	assert.Nil(err)
+	assert.Equal(1, limiter.InFlight(serverURL.Host))
+	assert.Nil(res.Body.Close())
+	assert.Zero(limiter.InFlight(serverURL.Host))
+}
+
+func TestOptLimiterReleasesOnError(t *testing.T) {
+	assert := assert.New(t)
+
+	server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {}))
+	serverURL, _ := url.Parse(server.URL)
+	server.Close()
+
+	limiter := NewLimiter(OptLimiterMaxInFlight(1))
+	_, err := New(serverURL.String(), OptLimiter(limiter)).Do()
+	assert.NotNil(err)
+	assert.Zero(limiter.InFlight(serverURL.Host))
+}
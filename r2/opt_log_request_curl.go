@@ -0,0 +1,31 @@
+package r2
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/blend/go-sdk/logger"
+)
+
+// OptLogRequestCurl adds an OnResponse listener that logs failing requests as curl commands.
+// A request is considered failing if it returns an error or a response with a status code >= 400.
+// Secret headers are redacted according to the curl options (see `Request.Curl`).
+//
+// The body is only included if it can be re-read after the request was sent, which
+// is the case for bodies set with `OptBodyBytes`, `OptJSONBody`, `OptXMLBody` and `OptPostForm`.
+func OptLogRequestCurl(log logger.Triggerable, options ...CurlOption) Option {
+	return OptOnResponse(func(req *http.Request, res *http.Response, started time.Time, err error) error {
+		if err == nil && res != nil && res.StatusCode < http.StatusBadRequest {
+			return nil
+		}
+		event := NewEvent(FlagCurl,
+			OptEventRequest(req),
+			OptEventResponse(res),
+			OptEventElapsed(time.Now().UTC().Sub(started)),
+			OptEventCurl(curlCommand(req, options...)),
+			OptEventErr(err),
+		)
+		logger.MaybeTrigger(req.Context(), log, event)
+		return err
+	})
+}
@@ -0,0 +1,48 @@
+package r2
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/blend/go-sdk/assert"
+	"github.com/blend/go-sdk/logger"
+)
+
+func TestOptLogRequestCurl(t *testing.T) {
+	assert := assert.New(t)
+
+	buf := new(bytes.Buffer)
+	log, err := logger.New(logger.OptOutput(buf), logger.OptAll())
+	assert.Nil(err)
+
+	server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/fail" {
+			rw.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		rw.WriteHeader(http.StatusOK)
+		fmt.Fprintf(rw, "OK!\n")
+	}))
+	defer server.Close()
+
+	_, err = New(server.URL, OptLogRequestCurl(log)).Discard()
+	assert.Nil(err)
+	assert.Empty(buf.String())
+
+	res, err := New(server.URL+"/fail",
+		OptPost(),
+		OptHeaderValue("Authorization", "Bearer secret"),
+		OptJSONBody(map[string]string{"foo": "bar"}),
+		OptLogRequestCurl(log),
+	).Discard()
+	assert.Nil(err)
+	assert.Equal(http.StatusInternalServerError, res.StatusCode)
+	assert.Contains(buf.String(), FlagCurl)
+	assert.Contains(buf.String(), "curl -X POST")
+	assert.Contains(buf.String(), `--data-raw '{"foo":"bar"}'`)
+	assert.Contains(buf.String(), "Authorization: <redacted>")
+	assert.NotContains(buf.String(), "secret")
+}
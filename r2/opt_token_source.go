@@ -0,0 +1,118 @@
+package r2
+
+import (
+	"io"
+	"io/ioutil"
+	"net/http"
+
+	"github.com/blend/go-sdk/ex"
+)
+
+var (
+	_ http.RoundTripper = (*TokenTransport)(nil)
+)
+
+// OptTokenSource authorizes the request with tokens from a given source.
+//
+// The token is fetched when the request is sent, and set as the `Authorization` header.
+// If the server responds with a 401, the token is invalidated (if the source implements `TokenInvalidator`)
+// and the request is retried once with a new token, provided the body can be re-read.
+// The client transport is wrapped when the request is sent, so options that set the transport or tls config
+// can be applied in any order.
+func OptTokenSource(source TokenSource) Option {
+	return func(r *Request) error {
+		r.TransportWrappers = append(r.TransportWrappers, func(base http.RoundTripper) http.RoundTripper {
+			return &TokenTransport{Base: base, Source: source}
+		})
+		return nil
+	}
+}
+
+// OptClientCredentials authorizes the request with oauth2 client credentials tokens.
+// Tokens are cached and proactively refreshed with a `CachingTokenSource`.
+//
+// The returned option holds the token cache, so it should be created once and shared between requests,
+// for example as part of a `Defaults` set:
+//
+//	defaults := r2.Defaults{
+//		r2.OptClientCredentials(r2.ClientCredentials{
+//			TokenURL:     "https://auth.example.com/oauth/token",
+//			ClientID:     "my-client",
+//			ClientSecretProvider: r2.SecretFromKV(vault, "secret/my-client", "client_secret"),
+//		}),
+//	}
+func OptClientCredentials(cc ClientCredentials, options ...CachingTokenSourceOption) Option {
+	return OptTokenSource(NewCachingTokenSource(cc, options...))
+}
+
+// TokenTransport is a round tripper that authorizes requests with tokens from a token source.
+type TokenTransport struct {
+	// Base is the underlying round tripper; if unset `http.DefaultTransport` is used.
+	Base http.RoundTripper
+	// Source is the token source.
+	Source TokenSource
+}
+
+// RoundTrip implements http.RoundTripper.
+func (tt *TokenTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	token, err := tt.Source.Token(req.Context())
+	if err != nil {
+		closeRequestBody(req)
+		return nil, err
+	}
+	if token == nil {
+		closeRequestBody(req)
+		return nil, ex.New(ErrTokenEmpty)
+	}
+	res, err := tt.base().RoundTrip(tt.authorize(req, token))
+	if err != nil || res.StatusCode != http.StatusUnauthorized {
+		return res, err
+	}
+	if req.Body != nil && req.GetBody == nil {
+		return res, nil
+	}
+
+	invalidator, ok := tt.Source.(TokenInvalidator)
+	if !ok {
+		return res, nil
+	}
+	invalidator.Invalidate(token)
+	retryToken, err := tt.Source.Token(req.Context())
+	if err != nil || retryToken == nil || retryToken.AccessToken == token.AccessToken {
+		return res, nil
+	}
+
+	retry := tt.authorize(req, retryToken)
+	if req.GetBody != nil {
+		if retry.Body, err = req.GetBody(); err != nil {
+			return res, nil
+		}
+	}
+	_, _ = io.Copy(ioutil.Discard, res.Body)
+	_ = res.Body.Close()
+	return tt.base().RoundTrip(retry)
+}
+
+// authorize returns a copy of the request with the authorization header set.
+// Round trippers must not modify the original request.
+func (tt *TokenTransport) authorize(req *http.Request, token *Token) *http.Request {
+	authorized := req.Clone(req.Context())
+	if authorized.Header == nil {
+		authorized.Header = http.Header{}
+	}
+	authorized.Header.Set("Authorization", token.HeaderValue())
+	return authorized
+}
+
+func (tt *TokenTransport) base() http.RoundTripper {
+	if tt.Base != nil {
+		return tt.Base
+	}
+	return http.DefaultTransport
+}
+
+func closeRequestBody(req *http.Request) {
+	if req.Body != nil {
+		_ = req.Body.Close()
+	}
+}
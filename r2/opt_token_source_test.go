@@ -0,0 +1,154 @@
+package r2
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+
+	"github.com/blend/go-sdk/assert"
+	"github.com/blend/go-sdk/ex"
+)
+
+func TestOptTokenSource(t *testing.T) {
+	assert := assert.New(t)
+
+	server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer foo" {
+			rw.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		rw.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	req := New(server.URL, OptTokenSource(StaticTokenSource(Token{AccessToken: "foo"})))
+	res, err := req.Discard()
+	assert.Nil(err)
+	assert.Equal(http.StatusOK, res.StatusCode)
+	assert.Empty(req.Header.Get("Authorization"), "the original request should not be modified")
+}
+
+func TestOptTokenSourceNilToken(t *testing.T) {
+	assert := assert.New(t)
+
+	server := mockServerOK()
+	defer server.Close()
+
+	tt := &TokenTransport{Source: TokenSourceFunc(func(_ context.Context) (*Token, error) {
+		return nil, nil
+	})}
+	req, err := http.NewRequest(http.MethodGet, server.URL, nil)
+	assert.Nil(err)
+	_, err = tt.RoundTrip(req)
+	assert.True(ex.Is(err, ErrTokenEmpty))
+}
+
+func TestOptTokenSourceTransportOptions(t *testing.T) {
+	assert := assert.New(t)
+
+	server := httptest.NewTLSServer(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer foo" {
+			rw.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		rw.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	req := New(server.URL,
+		OptTokenSource(StaticTokenSource(Token{AccessToken: "foo"})),
+		OptTLSSkipVerify(true),
+	)
+	_, isTransport := req.Client.Transport.(*http.Transport)
+	assert.True(isTransport, "the tls options should still set the base transport")
+
+	res, err := req.Discard()
+	assert.Nil(err)
+	assert.Equal(http.StatusOK, res.StatusCode)
+}
+
+func TestOptTokenSourceRetriesUnauthorized(t *testing.T) {
+	assert := assert.New(t)
+
+	var calls int32
+	server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		body, _ := ioutil.ReadAll(r.Body)
+		if r.Header.Get("Authorization") != "Bearer 2" {
+			rw.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		rw.WriteHeader(http.StatusOK)
+		fmt.Fprint(rw, string(body))
+	}))
+	defer server.Close()
+
+	var fetches int32
+	source := NewCachingTokenSource(TokenSourceFunc(func(_ context.Context) (*Token, error) {
+		return &Token{AccessToken: fmt.Sprint(atomic.AddInt32(&fetches, 1))}, nil
+	}))
+
+	contents, res, err := New(server.URL,
+		OptPost(),
+		OptBodyBytes([]byte("this is a test")),
+		OptTokenSource(source),
+	).Bytes()
+	assert.Nil(err)
+	assert.Equal(http.StatusOK, res.StatusCode)
+	assert.Equal("this is a test", string(contents))
+	assert.Equal(2, atomic.LoadInt32(&calls))
+
+	// the refreshed token is cached
+	res, err = New(server.URL, OptTokenSource(source)).Discard()
+	assert.Nil(err)
+	assert.Equal(http.StatusOK, res.StatusCode)
+	assert.Equal(2, atomic.LoadInt32(&fetches))
+}
+
+func TestOptTokenSourceRetriesOnce(t *testing.T) {
+	assert := assert.New(t)
+
+	var calls int32
+	server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		rw.WriteHeader(http.StatusUnauthorized)
+	}))
+	defer server.Close()
+
+	var fetches int32
+	source := NewCachingTokenSource(TokenSourceFunc(func(_ context.Context) (*Token, error) {
+		return &Token{AccessToken: fmt.Sprint(atomic.AddInt32(&fetches, 1))}, nil
+	}))
+	res, err := New(server.URL, OptTokenSource(source)).Discard()
+	assert.Nil(err)
+	assert.Equal(http.StatusUnauthorized, res.StatusCode)
+	assert.Equal(2, atomic.LoadInt32(&calls))
+}
+
+func TestOptClientCredentials(t *testing.T) {
+	assert := assert.New(t)
+
+	tokenServer := mockTokenServer("secret")
+	defer tokenServer.Close()
+
+	server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(rw, r.Header.Get("Authorization"))
+	}))
+	defer server.Close()
+
+	defaults := Defaults{
+		OptClientCredentials(ClientCredentials{
+			TokenURL:     tokenServer.URL,
+			ClientID:     "client",
+			ClientSecret: "secret",
+			Scopes:       []string{"read"},
+		}),
+	}
+	contents, _, err := New(server.URL, defaults...).Bytes()
+	assert.Nil(err)
+	assert.Equal("Bearer token-read", string(contents))
+}
@@ -0,0 +1,236 @@
+package r2
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/blend/go-sdk/ex"
+)
+
+// NextPageProvider returns the options to apply to the previous page's request to fetch the next page.
+// It should return nil options once there are no more pages.
+type NextPageProvider func(req *http.Request, res *http.Response, body []byte) ([]Option, error)
+
+// CursorExtractor returns a cursor from a page response.
+// It should return an empty string once there are no more pages.
+type CursorExtractor func(res *http.Response, body []byte) (string, error)
+
+// NewPager returns a new pager that starts with a given request
+// and follows pages with a given next page provider.
+//
+// Pagers are used in a loop similar to `bufio.Scanner`:
+//
+//	pager := r2.NewPager(r2.New("https://api.example.com/things"), r2.NextPageLink())
+//	for pager.Next() {
+//		var things []Thing
+//		if err := pager.JSON(&things); err != nil {
+//			return err
+//		}
+//		// ...
+//	}
+//	if err := pager.Err(); err != nil {
+//		return err
+//	}
+func NewPager(r *Request, next NextPageProvider) *Pager {
+	return &Pager{
+		request: r,
+		next:    next,
+	}
+}
+
+// Pager iterates over the pages of a paginated api.
+// Each page is read fully before being returned, so a page should be reasonably sized;
+// use `JSONStream` to process a single large response one item at a time.
+type Pager struct {
+	request *Request
+	next    NextPageProvider
+
+	done     bool
+	err      error
+	response *http.Response
+	body     []byte
+}
+
+// Next fetches the next page, returning false if there are no more pages or there was an error.
+// Responses with non-2xx status codes are still returned as pages; check `Response().StatusCode` as needed.
+// It checks the request context for cancellation before each page is fetched.
+func (p *Pager) Next() bool {
+	if p.done || p.err != nil {
+		return false
+	}
+	if p.response != nil {
+		options, err := p.next(&p.request.Request, p.response, p.body)
+		if err != nil {
+			p.err = err
+			return false
+		}
+		if len(options) == 0 {
+			p.done = true
+			return false
+		}
+		p.request = nextPageRequest(p.request, options...)
+	}
+	if p.request.Err != nil {
+		p.err = p.request.Err
+		return false
+	}
+	if err := p.request.Context().Err(); err != nil {
+		p.err = ex.New(err)
+		return false
+	}
+	body, res, err := p.request.Bytes()
+	if err != nil {
+		p.err = err
+		return false
+	}
+	p.response = res
+	p.body = body
+	return true
+}
+
+// Response returns the current page's response metadata.
+// The response body has already been read; use `Body` to get its contents.
+func (p *Pager) Response() *http.Response {
+	return p.response
+}
+
+// Body returns the current page's body.
+func (p *Pager) Body() []byte {
+	return p.body
+}
+
+// JSON decodes the current page's body as json into a given object.
+func (p *Pager) JSON(dst interface{}) error {
+	if p.response != nil && p.response.StatusCode == http.StatusNoContent {
+		return ex.New(ErrNoContentJSON)
+	}
+	return ex.New(json.Unmarshal(p.body, dst))
+}
+
+// Err returns the first error encountered while paging, if any.
+func (p *Pager) Err() error {
+	return p.err
+}
+
+// NextPageLink returns a next page provider that follows `Link` headers with `rel="next"`.
+// Relative links are resolved against the current request url.
+func NextPageLink() NextPageProvider {
+	return func(req *http.Request, res *http.Response, _ []byte) ([]Option, error) {
+		next := ParseLinkHeader(res.Header)[RelNext]
+		if next == "" {
+			return nil, nil
+		}
+		nextURL, err := url.Parse(next)
+		if err != nil {
+			return nil, ex.New(err)
+		}
+		if req.URL != nil {
+			nextURL = req.URL.ResolveReference(nextURL)
+		}
+		if req.URL != nil && nextURL.String() == req.URL.String() {
+			return nil, nil
+		}
+		return []Option{OptURL(nextURL.String())}, nil
+	}
+}
+
+// NextPageCursor returns a next page provider that sets a given query string parameter
+// to the cursor returned by an extractor.
+func NextPageCursor(queryParameter string, extractor CursorExtractor) NextPageProvider {
+	return func(_ *http.Request, res *http.Response, body []byte) ([]Option, error) {
+		cursor, err := extractor(res, body)
+		if err != nil {
+			return nil, err
+		}
+		if cursor == "" {
+			return nil, nil
+		}
+		return []Option{OptQueryValue(queryParameter, cursor)}, nil
+	}
+}
+
+// CursorJSONField returns a cursor extractor that reads a (possibly nested) string field from a json body.
+//
+// As an example, to read the cursor from `{"meta":{"next":"abc"}}`:
+//
+//	r2.CursorJSONField("meta", "next")
+func CursorJSONField(path ...string) CursorExtractor {
+	return func(_ *http.Response, body []byte) (string, error) {
+		var value interface{}
+		if err := json.Unmarshal(body, &value); err != nil {
+			return "", ex.New(err)
+		}
+		for _, field := range path {
+			object, ok := value.(map[string]interface{})
+			if !ok {
+				return "", nil
+			}
+			value = object[field]
+		}
+		cursor, _ := value.(string)
+		return cursor, nil
+	}
+}
+
+// RelNext is the `Link` header relation for the next page.
+const RelNext = "next"
+
+// ParseLinkHeader parses the `Link` headers of a response into a map of relation to url.
+// See RFC 8288 for the format.
+func ParseLinkHeader(header http.Header) map[string]string {
+	output := make(map[string]string)
+	for _, value := range header[http.CanonicalHeaderKey(HeaderLink)] {
+		for _, link := range strings.Split(value, ",") {
+			pieces := strings.Split(link, ";")
+			target := strings.TrimSpace(pieces[0])
+			if !strings.HasPrefix(target, "<") || !strings.HasSuffix(target, ">") {
+				continue
+			}
+			target = strings.TrimSuffix(strings.TrimPrefix(target, "<"), ">")
+			for _, param := range pieces[1:] {
+				keyValue := strings.SplitN(strings.TrimSpace(param), "=", 2)
+				if len(keyValue) != 2 || !strings.EqualFold(strings.TrimSpace(keyValue[0]), "rel") {
+					continue
+				}
+				for _, rel := range strings.Fields(strings.Trim(keyValue[1], `"`)) {
+					output[strings.ToLower(rel)] = target
+				}
+			}
+		}
+	}
+	return output
+}
+
+// nextPageRequest returns a copy of a request with a given set of options applied.
+// The url and headers are copied so the previous request is not modified,
+// and the body is re-read with `GetBody` if it is set.
+func nextPageRequest(r *Request, options ...Option) *Request {
+	next := *r
+	next.Request = *r.Request.WithContext(r.Request.Context())
+	if r.URL != nil {
+		nextURL := *r.URL
+		next.URL = &nextURL
+	}
+	if r.Header != nil {
+		next.Header = r.Header.Clone()
+	}
+	if r.Request.GetBody != nil {
+		body, err := r.Request.GetBody()
+		if err != nil {
+			next.Err = ex.New(err)
+			return &next
+		}
+		next.Body = body
+	} else {
+		next.Body = nil
+	}
+	for _, option := range options {
+		if err := option(&next); err != nil {
+			next.Err = err
+			return &next
+		}
+	}
+	return &next
+}
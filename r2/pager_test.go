@@ -0,0 +1,101 @@
+package r2
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+
+	"github.com/blend/go-sdk/assert"
+)
+
+func TestPagerLink(t *testing.T) {
+	assert := assert.New(t)
+
+	server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		page, _ := strconv.Atoi(r.URL.Query().Get("page"))
+		if page < 2 {
+			rw.Header().Add("Link", fmt.Sprintf(`</things?page=%d>; rel="next", </things?page=2>; rel="last"`, page+1))
+		}
+		rw.WriteHeader(http.StatusOK)
+		fmt.Fprintf(rw, "[%d]", page)
+	}))
+	defer server.Close()
+
+	pager := NewPager(New(server.URL+"/things", OptHeaderValue("X-Foo", "bar")), NextPageLink())
+	var pages []int
+	for pager.Next() {
+		var page []int
+		assert.Nil(pager.JSON(&page))
+		pages = append(pages, page...)
+		assert.Equal(http.StatusOK, pager.Response().StatusCode)
+	}
+	assert.Nil(pager.Err())
+	assert.Equal([]int{0, 1, 2}, pages)
+	assert.False(pager.Next())
+}
+
+func TestPagerCursor(t *testing.T) {
+	assert := assert.New(t)
+
+	var headers []string
+	server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		headers = append(headers, r.Header.Get("X-Foo"))
+		switch r.URL.Query().Get("cursor") {
+		case "":
+			fmt.Fprint(rw, `{"items":["a","b"],"meta":{"next":"c1"}}`)
+		case "c1":
+			fmt.Fprint(rw, `{"items":["c"],"meta":{"next":"c2"}}`)
+		default:
+			fmt.Fprint(rw, `{"items":["d"],"meta":{}}`)
+		}
+	}))
+	defer server.Close()
+
+	pager := NewPager(New(server.URL, OptHeaderValue("X-Foo", "bar")), NextPageCursor("cursor", CursorJSONField("meta", "next")))
+	var items []string
+	for pager.Next() {
+		var page struct {
+			Items []string `json:"items"`
+		}
+		assert.Nil(pager.JSON(&page))
+		items = append(items, page.Items...)
+	}
+	assert.Nil(pager.Err())
+	assert.Equal([]string{"a", "b", "c", "d"}, items)
+	assert.Equal([]string{"bar", "bar", "bar"}, headers)
+}
+
+func TestPagerContextCancelled(t *testing.T) {
+	assert := assert.New(t)
+
+	server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		rw.Header().Add("Link", `</next>; rel="next"`)
+		fmt.Fprint(rw, "[]")
+	}))
+	defer server.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	pager := NewPager(New(server.URL, OptContext(ctx)), NextPageLink())
+	assert.True(pager.Next())
+	cancel()
+	assert.False(pager.Next())
+	assert.NotNil(pager.Err())
+}
+
+func TestParseLinkHeader(t *testing.T) {
+	assert := assert.New(t)
+
+	header := http.Header{}
+	header.Add("Link", `<https://api.example.com/things?page=2>; rel="next", <https://api.example.com/things?page=5>; rel="last"`)
+	header.Add("Link", `<https://api.example.com/things?page=1>; rel="first prev"`)
+
+	links := ParseLinkHeader(header)
+	assert.Equal("https://api.example.com/things?page=2", links[RelNext])
+	assert.Equal("https://api.example.com/things?page=5", links["last"])
+	assert.Equal("https://api.example.com/things?page=1", links["first"])
+	assert.Equal("https://api.example.com/things?page=1", links["prev"])
+	assert.Empty(ParseLinkHeader(http.Header{}))
+}
@@ -0,0 +1,381 @@
+package r2
+
+import (
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/blend/go-sdk/ex"
+)
+
+// ParseCurl parses a curl command line into a set of options.
+//
+// It understands the common flags produced by browsers' "copy as curl" and by `Request.Curl()`,
+// specifically the method, url, headers, data, user, user agent, cookie, insecure and max time flags.
+// Purely cosmetic flags (silent, verbose, compressed etc.) are ignored.
+// Any other flag is rejected with `ErrCurlUnsupportedFlag`.
+//
+// The url is returned as an `OptURL`, so the options can be passed to `New` with an empty url:
+//
+//	options, err := r2.ParseCurl(`curl -X POST 'https://example.com' -d '{"foo":"bar"}'`)
+//	if err != nil {
+//		return err
+//	}
+//	res, err := r2.New("", options...).Do()
+func ParseCurl(command string) ([]Option, error) {
+	args, err := splitCurlArgs(command)
+	if err != nil {
+		return nil, err
+	}
+	if len(args) == 0 || args[0] != "curl" {
+		return nil, ex.New(ErrCurlInvalid, ex.OptMessage("command must start with curl"))
+	}
+	args = args[1:]
+
+	var method, rawURL string
+	var getQuery, head bool
+	var data []string
+	var options []Option
+
+	for index := 0; index < len(args); index++ {
+		arg := args[index]
+		if !strings.HasPrefix(arg, "-") || arg == "-" {
+			if rawURL != "" {
+				return nil, ex.New(ErrCurlInvalid, ex.OptMessagef("multiple urls: %s", arg))
+			}
+			rawURL = arg
+			continue
+		}
+
+		for _, name := range expandCurlSwitches(arg) {
+			if curlIgnoredFlags[name] {
+				continue
+			}
+			switch curlSwitchFlags[name] {
+			case "get":
+				getQuery = true
+				continue
+			case "head":
+				head = true
+				continue
+			case "insecure":
+				options = append(options, OptTLSSkipVerify(true))
+				continue
+			}
+
+			var value string
+			var hasValue bool
+			if strings.HasPrefix(name, "--") {
+				if equals := strings.Index(name, "="); equals > 0 {
+					name, value, hasValue = name[:equals], name[equals+1:], true
+				}
+			} else if len(name) > 2 {
+				name, value, hasValue = name[:2], name[2:], true
+			}
+
+			flag, ok := curlValueFlags[name]
+			if !ok {
+				return nil, ex.New(ErrCurlUnsupportedFlag, ex.OptMessagef("flag: %s", name))
+			}
+			if !hasValue {
+				if index+1 >= len(args) {
+					return nil, ex.New(ErrCurlInvalid, ex.OptMessagef("flag requires a value: %s", name))
+				}
+				index++
+				value = args[index]
+			}
+
+			switch flag {
+			case "request":
+				method = strings.ToUpper(value)
+			case "url":
+				rawURL = value
+			case "header":
+				key, headerValue, err := parseCurlHeader(value)
+				if err != nil {
+					return nil, err
+				}
+				options = append(options, optAddHeaderValue(key, headerValue))
+			case "data":
+				data = append(data, value)
+			case "data-urlencode":
+				data = append(data, curlURLEncode(value))
+			case "user":
+				pieces := strings.SplitN(value, ":", 2)
+				if len(pieces) == 2 {
+					options = append(options, OptBasicAuth(pieces[0], pieces[1]))
+				} else {
+					options = append(options, OptBasicAuth(pieces[0], ""))
+				}
+			case "user-agent":
+				options = append(options, OptUserAgent(value))
+			case "cookie":
+				options = append(options, optAddHeaderValue("Cookie", value))
+			case "max-time":
+				seconds, err := strconv.ParseFloat(value, 64)
+				if err != nil {
+					return nil, ex.New(ErrCurlInvalid, ex.OptMessagef("invalid max time: %s", value), ex.OptInner(err))
+				}
+				options = append(options, OptTimeout(time.Duration(seconds*float64(time.Second))))
+			}
+		}
+	}
+
+	if rawURL == "" {
+		return nil, ex.New(ErrCurlMissingURL)
+	}
+
+	output := []Option{OptURL(rawURL)}
+	switch {
+	case method != "":
+		output = append(output, OptMethod(method))
+	case head:
+		output = append(output, OptMethod(http.MethodHead))
+	case len(data) > 0 && !getQuery:
+		output = append(output, OptPost())
+	}
+	output = append(output, options...)
+
+	if len(data) > 0 {
+		body := strings.Join(data, "&")
+		if getQuery {
+			output = append(output, optAppendRawQuery(body))
+		} else {
+			output = append(output, OptBodyBytes([]byte(body)), optDefaultHeaderValue(HeaderContentType, ContentTypeApplicationFormEncoded))
+		}
+	}
+	return output, nil
+}
+
+// curlValueFlags maps curl flags that take a value to their canonical names.
+var curlValueFlags = map[string]string{
+	"-X":               "request",
+	"--request":        "request",
+	"--url":            "url",
+	"-H":               "header",
+	"--header":         "header",
+	"-d":               "data",
+	"--data":           "data",
+	"--data-raw":       "data",
+	"--data-binary":    "data",
+	"--data-ascii":     "data",
+	"--data-urlencode": "data-urlencode",
+	"-u":               "user",
+	"--user":           "user",
+	"-A":               "user-agent",
+	"--user-agent":     "user-agent",
+	"-b":               "cookie",
+	"--cookie":         "cookie",
+	"-m":               "max-time",
+	"--max-time":       "max-time",
+}
+
+// curlSwitchFlags maps curl flags that do not take a value to their canonical names.
+var curlSwitchFlags = map[string]string{
+	"-G":         "get",
+	"--get":      "get",
+	"-I":         "head",
+	"--head":     "head",
+	"-k":         "insecure",
+	"--insecure": "insecure",
+}
+
+// curlIgnoredFlags are flags that do not affect the request itself.
+var curlIgnoredFlags = map[string]bool{
+	"-s":           true,
+	"--silent":     true,
+	"-S":           true,
+	"--show-error": true,
+	"-v":           true,
+	"--verbose":    true,
+	"-i":           true,
+	"--include":    true,
+	"-L":           true,
+	"--location":   true,
+	"--compressed": true,
+	"-f":           true,
+	"--fail":       true,
+}
+
+// expandCurlSwitches splits combined short switches, e.g. `-sSL`, into individual flags.
+// Any other argument is returned as is.
+func expandCurlSwitches(arg string) []string {
+	if strings.HasPrefix(arg, "--") || len(arg) <= 2 {
+		return []string{arg}
+	}
+	var output []string
+	for _, c := range arg[1:] {
+		flag := "-" + string(c)
+		if _, isSwitch := curlSwitchFlags[flag]; !isSwitch && !curlIgnoredFlags[flag] {
+			return []string{arg}
+		}
+		output = append(output, flag)
+	}
+	return output
+}
+
+func parseCurlHeader(value string) (key, headerValue string, err error) {
+	pieces := strings.SplitN(value, ":", 2)
+	if len(pieces) != 2 || strings.TrimSpace(pieces[0]) == "" {
+		err = ex.New(ErrCurlInvalid, ex.OptMessagef("invalid header: %s", value))
+		return
+	}
+	key = strings.TrimSpace(pieces[0])
+	headerValue = strings.TrimSpace(pieces[1])
+	return
+}
+
+// curlURLEncode encodes a `--data-urlencode` value, i.e. `name=content` or `content`.
+func curlURLEncode(value string) string {
+	if equals := strings.Index(value, "="); equals >= 0 {
+		if equals == 0 {
+			return url.QueryEscape(value[1:])
+		}
+		return value[:equals] + "=" + url.QueryEscape(value[equals+1:])
+	}
+	return url.QueryEscape(value)
+}
+
+func optAddHeaderValue(key, value string) Option {
+	return func(r *Request) error {
+		if r.Header == nil {
+			r.Header = http.Header{}
+		}
+		r.Header.Add(key, value)
+		return nil
+	}
+}
+
+func optDefaultHeaderValue(key, value string) Option {
+	return func(r *Request) error {
+		if r.Header == nil {
+			r.Header = http.Header{}
+		}
+		if r.Header.Get(key) == "" {
+			r.Header.Set(key, value)
+		}
+		return nil
+	}
+}
+
+func optAppendRawQuery(query string) Option {
+	return func(r *Request) error {
+		if r.URL == nil {
+			r.URL = &url.URL{}
+		}
+		if r.URL.RawQuery != "" {
+			r.URL.RawQuery = r.URL.RawQuery + "&" + query
+		} else {
+			r.URL.RawQuery = query
+		}
+		return nil
+	}
+}
+
+// splitCurlArgs splits a command line into arguments following posix shell quoting rules.
+// It supports single quotes, double quotes, ansi-c quotes (`$'...'`), backslash escapes and line continuations.
+func splitCurlArgs(command string) ([]string, error) {
+	var args []string
+	var current strings.Builder
+	var inArg bool
+
+	runes := []rune(command)
+	for index := 0; index < len(runes); index++ {
+		c := runes[index]
+		switch {
+		case c == '\\':
+			if index+1 >= len(runes) {
+				return nil, ex.New(ErrCurlInvalid, ex.OptMessage("trailing backslash"))
+			}
+			index++
+			if runes[index] == '\n' {
+				continue
+			}
+			current.WriteRune(runes[index])
+			inArg = true
+		case c == '\'':
+			end := indexRune(runes, index+1, '\'')
+			if end < 0 {
+				return nil, ex.New(ErrCurlInvalid, ex.OptMessage("unterminated single quote"))
+			}
+			current.WriteString(string(runes[index+1 : end]))
+			index = end
+			inArg = true
+		case c == '$' && index+1 < len(runes) && runes[index+1] == '\'':
+			value, end, err := readANSICQuoted(runes, index+2)
+			if err != nil {
+				return nil, err
+			}
+			current.WriteString(value)
+			index = end
+			inArg = true
+		case c == '"':
+			index++
+			for ; index < len(runes) && runes[index] != '"'; index++ {
+				if runes[index] == '\\' && index+1 < len(runes) && strings.ContainsRune("\"\\$`\n", runes[index+1]) {
+					index++
+					if runes[index] == '\n' {
+						continue
+					}
+				}
+				current.WriteRune(runes[index])
+			}
+			if index >= len(runes) {
+				return nil, ex.New(ErrCurlInvalid, ex.OptMessage("unterminated double quote"))
+			}
+			inArg = true
+		case c == ' ' || c == '\t' || c == '\n' || c == '\r':
+			if inArg {
+				args = append(args, current.String())
+				current.Reset()
+				inArg = false
+			}
+		default:
+			current.WriteRune(c)
+			inArg = true
+		}
+	}
+	if inArg {
+		args = append(args, current.String())
+	}
+	return args, nil
+}
+
+func indexRune(runes []rune, start int, r rune) int {
+	for index := start; index < len(runes); index++ {
+		if runes[index] == r {
+			return index
+		}
+	}
+	return -1
+}
+
+// readANSICQuoted reads a `$'...'` string starting after the opening quote.
+// It returns the decoded value and the index of the closing quote.
+func readANSICQuoted(runes []rune, start int) (string, int, error) {
+	var output strings.Builder
+	for index := start; index < len(runes); index++ {
+		c := runes[index]
+		if c == '\'' {
+			return output.String(), index, nil
+		}
+		if c != '\\' || index+1 >= len(runes) {
+			output.WriteRune(c)
+			continue
+		}
+		index++
+		switch runes[index] {
+		case 'n':
+			output.WriteRune('\n')
+		case 't':
+			output.WriteRune('\t')
+		case 'r':
+			output.WriteRune('\r')
+		default:
+			output.WriteRune(runes[index])
+		}
+	}
+	return "", 0, ex.New(ErrCurlInvalid, ex.OptMessage("unterminated ansi-c quote"))
+}
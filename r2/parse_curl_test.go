@@ -0,0 +1,112 @@
+package r2
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/blend/go-sdk/assert"
+	"github.com/blend/go-sdk/ex"
+)
+
+func TestParseCurl(t *testing.T) {
+	assert := assert.New(t)
+
+	options, err := ParseCurl(`curl -X PUT 'https://foo.bar.local/buzz?a=b' \
+		-H 'X-Foo: it'\''s bar' \
+		-H "X-Bar: \"quoted\"" \
+		--data-raw '{"foo":"bar"}' \
+		-u user:pass -m 1.5 -sSL --compressed`)
+	assert.Nil(err)
+
+	r := New("", options...)
+	assert.Nil(r.Err)
+	assert.Equal("PUT", r.Method)
+	assert.Equal("https://foo.bar.local/buzz?a=b", r.URL.String())
+	assert.Equal("it's bar", r.Header.Get("X-Foo"))
+	assert.Equal(`"quoted"`, r.Header.Get("X-Bar"))
+	assert.Equal(ContentTypeApplicationFormEncoded, r.Header.Get(HeaderContentType))
+	assert.Equal(`{"foo":"bar"}`, readString(r.Body))
+	username, password, ok := r.BasicAuth()
+	assert.True(ok)
+	assert.Equal("user", username)
+	assert.Equal("pass", password)
+	assert.Equal(1500*time.Millisecond, r.Client.Timeout)
+}
+
+func TestParseCurlDataImpliesPost(t *testing.T) {
+	assert := assert.New(t)
+
+	options, err := ParseCurl(`curl https://foo.bar.local -d foo=bar --data-urlencode 'q=a b' -H 'Content-Type: text/plain'`)
+	assert.Nil(err)
+	r := New("", options...)
+	assert.Nil(r.Err)
+	assert.Equal(MethodPost, r.Method)
+	assert.Equal("text/plain", r.Header.Get(HeaderContentType))
+	assert.Equal("foo=bar&q=a+b", readString(r.Body))
+}
+
+func TestParseCurlGet(t *testing.T) {
+	assert := assert.New(t)
+
+	options, err := ParseCurl(`curl -G 'https://foo.bar.local?a=b' -d c=d`)
+	assert.Nil(err)
+	r := New("", options...)
+	assert.Nil(r.Err)
+	assert.Equal(MethodGet, r.Method)
+	assert.Equal("a=b&c=d", r.URL.RawQuery)
+	assert.Nil(r.Body)
+
+	options, err = ParseCurl(`curl -I --url=https://foo.bar.local`)
+	assert.Nil(err)
+	r = New("", options...)
+	assert.Equal(http.MethodHead, r.Method)
+	assert.Equal("https://foo.bar.local", r.URL.String())
+}
+
+func TestParseCurlANSICQuoting(t *testing.T) {
+	assert := assert.New(t)
+
+	options, err := ParseCurl(`curl 'https://foo.bar.local' --data-binary $'line one\nline \'two\''`)
+	assert.Nil(err)
+	r := New("", options...)
+	assert.Equal("line one\nline 'two'", readString(r.Body))
+}
+
+func TestParseCurlRoundTrip(t *testing.T) {
+	assert := assert.New(t)
+
+	original := New("https://foo.bar.local/buzz",
+		OptPatch(),
+		OptHeaderValue("X-Foo", "bar"),
+		OptJSONBody(map[string]string{"foo": "it's bar"}),
+	)
+	options, err := ParseCurl(original.Curl())
+	assert.Nil(err)
+
+	parsed := New("", options...)
+	assert.Nil(parsed.Err)
+	assert.Equal(original.Curl(), parsed.Curl())
+}
+
+func TestParseCurlErrors(t *testing.T) {
+	assert := assert.New(t)
+
+	_, err := ParseCurl(`wget https://foo.bar.local`)
+	assert.True(ex.Is(err, ErrCurlInvalid))
+
+	_, err = ParseCurl(`curl https://foo.bar.local -X`)
+	assert.True(ex.Is(err, ErrCurlInvalid))
+
+	_, err = ParseCurl(`curl -H 'X-Foo: bar'`)
+	assert.True(ex.Is(err, ErrCurlMissingURL))
+
+	_, err = ParseCurl(`curl --proxy http://proxy.local https://foo.bar.local`)
+	assert.True(ex.Is(err, ErrCurlUnsupportedFlag))
+
+	_, err = ParseCurl(`curl 'https://foo.bar.local`)
+	assert.True(ex.Is(err, ErrCurlInvalid))
+
+	_, err = ParseCurl(`curl -H 'no colon' https://foo.bar.local`)
+	assert.True(ex.Is(err, ErrCurlInvalid))
+}
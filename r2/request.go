@@ -54,6 +54,12 @@ type Request struct {
 	OnRequest []OnRequestListener
 	// OnResponse is an array of response lifecycle hooks used for logging.
 	OnResponse []OnResponseListener
+	// TransportWrappers wrap the client transport when the request is sent, with later wrappers outermost.
+	// They are applied when the request is sent so that they wrap the transport set by any other options.
+	TransportWrappers []TransportWrapper
+	// Signer is an optional request signer.
+	// Requests are signed when they are sent, after any transport wrappers.
+	Signer Signer
 }
 
 // Do executes the request.
@@ -62,12 +68,7 @@ func (r Request) Do() (*http.Response, error) {
 		return nil, r.Err
 	}
 
-	// reconcile post form values
-	if r.Request.PostForm != nil && len(r.Request.PostForm) > 0 && r.Request.Body == nil {
-		body := r.Request.PostForm.Encode()
-		r.Request.Body = ioutil.NopCloser(strings.NewReader(body))
-		r.Request.ContentLength = int64(len(body))
-	}
+	reconcilePostForm(&r.Request)
 
 	var err error
 	started := time.Now().UTC()
@@ -83,12 +84,7 @@ func (r Request) Do() (*http.Response, error) {
 		}
 	}
 
-	var res *http.Response
-	if r.Client != nil {
-		res, err = r.Client.Do(&r.Request)
-	} else {
-		res, err = http.DefaultClient.Do(&r.Request)
-	}
+	res, err := r.client().Do(&r.Request)
 	if finisher != nil {
 		finisher.Finish(&r.Request, res, started, err)
 	}
@@ -103,6 +99,29 @@ func (r Request) Do() (*http.Response, error) {
 	return res, nil
 }
 
+// client returns the client to send the request with, with its transport wrapped by the signer
+// and any transport wrappers.
+func (r Request) client() *http.Client {
+	client := r.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+	if len(r.TransportWrappers) == 0 && r.Signer == nil {
+		return client
+	}
+	wrapped := *client
+	if wrapped.Transport == nil {
+		wrapped.Transport = http.DefaultTransport
+	}
+	if r.Signer != nil {
+		wrapped.Transport = &SignerTransport{Base: wrapped.Transport, Signer: r.Signer}
+	}
+	for _, wrapper := range r.TransportWrappers {
+		wrapped.Transport = wrapper(wrapped.Transport)
+	}
+	return &wrapped
+}
+
 // Close closes the request if there is a closer specified.
 func (r *Request) Close() error {
 	if r.Closer != nil {
@@ -185,3 +204,15 @@ func (r Request) XML(dst interface{}) (*http.Response, error) {
 	}
 	return res, ex.New(xml.NewDecoder(res.Body).Decode(dst))
 }
+
+// reconcilePostForm sets the request body from the post form values if the body is unset.
+func reconcilePostForm(req *http.Request) {
+	if req.PostForm != nil && len(req.PostForm) > 0 && req.Body == nil {
+		body := req.PostForm.Encode()
+		req.Body = ioutil.NopCloser(strings.NewReader(body))
+		req.GetBody = func() (io.ReadCloser, error) {
+			return ioutil.NopCloser(strings.NewReader(body)), nil
+		}
+		req.ContentLength = int64(len(body))
+	}
+}
@@ -0,0 +1,54 @@
+package r2
+
+import "net/http"
+
+var (
+	_ http.RoundTripper = (*SignerTransport)(nil)
+)
+
+// Signer is a type that signs requests.
+type Signer interface {
+	Sign(*http.Request) error
+}
+
+// SignerFunc is a function that implements Signer.
+type SignerFunc func(*http.Request) error
+
+// Sign implements Signer.
+func (sf SignerFunc) Sign(req *http.Request) error {
+	return sf(req)
+}
+
+// OptSigner sets the request signer.
+// The request is signed when it is sent, after all other options and transport wrappers regardless of the
+// order options are passed in, so that it signs the final method, url, headers and body of each request sent,
+// including retries and requests for further pages.
+func OptSigner(signer Signer) Option {
+	return func(r *Request) error {
+		r.Signer = signer
+		return nil
+	}
+}
+
+// SignerTransport is a round tripper that signs requests with a signer before sending them.
+type SignerTransport struct {
+	// Base is the underlying round tripper; if unset `http.DefaultTransport` is used.
+	Base http.RoundTripper
+	// Signer is the signer.
+	Signer Signer
+}
+
+// RoundTrip implements http.RoundTripper.
+// The original request is not modified; a copy of it is signed and sent.
+func (st *SignerTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	signed := req.Clone(req.Context())
+	if err := st.Signer.Sign(signed); err != nil {
+		closeRequestBody(req)
+		return nil, err
+	}
+	base := st.Base
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	return base.RoundTrip(signed)
+}
@@ -0,0 +1,103 @@
+package r2
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync/atomic"
+	"testing"
+
+	"github.com/blend/go-sdk/assert"
+)
+
+func TestOptSignerAppliedLast(t *testing.T) {
+	assert := assert.New(t)
+
+	var signedHeader string
+	r := New("https://foo.bar.local",
+		OptSigner(SignerFunc(func(req *http.Request) error {
+			signedHeader = req.Header.Get("X-Foo")
+			req.Header.Set("X-Signature", "signed")
+			return nil
+		})),
+		OptHeaderValue("X-Foo", "bar"),
+	)
+	assert.Nil(r.Err)
+	sent, err := sentRequest(r)
+	assert.Nil(err)
+	assert.Equal("bar", signedHeader)
+	assert.Equal("signed", sent.Header.Get("X-Signature"))
+	assert.Empty(r.Header.Get("X-Signature"), "the original request should not be modified")
+}
+
+func TestOptSignerError(t *testing.T) {
+	assert := assert.New(t)
+
+	r := New("https://foo.bar.local", OptSigner(SignerFunc(func(_ *http.Request) error {
+		return fmt.Errorf("this is only a test")
+	})))
+	assert.Nil(r.Err)
+	_, err := sentRequest(r)
+	assert.NotNil(err)
+}
+
+func TestOptSignerPager(t *testing.T) {
+	assert := assert.New(t)
+
+	server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("X-Signature") != r.URL.RequestURI() {
+			rw.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		page, _ := strconv.Atoi(r.URL.Query().Get("page"))
+		if page < 2 {
+			rw.Header().Add("Link", fmt.Sprintf(`</things?page=%d>; rel="next"`, page+1))
+		}
+		rw.WriteHeader(http.StatusOK)
+		fmt.Fprintf(rw, "[%d]", page)
+	}))
+	defer server.Close()
+
+	signer := SignerFunc(func(req *http.Request) error {
+		req.Header.Set("X-Signature", req.URL.RequestURI())
+		return nil
+	})
+	pager := NewPager(New(server.URL+"/things", OptSigner(signer)), NextPageLink())
+	var pages []int
+	for pager.Next() {
+		assert.Equal(http.StatusOK, pager.Response().StatusCode)
+		var page []int
+		assert.Nil(pager.JSON(&page))
+		pages = append(pages, page...)
+	}
+	assert.Nil(pager.Err())
+	assert.Equal([]int{0, 1, 2}, pages)
+}
+
+func TestOptSignerTokenSourceRetry(t *testing.T) {
+	assert := assert.New(t)
+
+	server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("X-Signature") != r.Header.Get("Authorization") || r.Header.Get("Authorization") != "Bearer 2" {
+			rw.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		rw.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	var fetches int32
+	source := NewCachingTokenSource(TokenSourceFunc(func(_ context.Context) (*Token, error) {
+		return &Token{AccessToken: fmt.Sprint(atomic.AddInt32(&fetches, 1))}, nil
+	}))
+	signer := SignerFunc(func(req *http.Request) error {
+		req.Header.Set("X-Signature", req.Header.Get("Authorization"))
+		return nil
+	})
+	res, err := New(server.URL, OptSigner(signer), OptTokenSource(source)).Discard()
+	assert.Nil(err)
+	assert.Equal(http.StatusOK, res.StatusCode)
+	assert.Equal(2, atomic.LoadInt32(&fetches))
+}
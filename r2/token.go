@@ -0,0 +1,73 @@
+package r2
+
+import (
+	"context"
+	"strings"
+	"time"
+)
+
+// TokenTypeBearer is the default token type.
+const TokenTypeBearer = "Bearer"
+
+// Token is an access token used to authorize requests.
+type Token struct {
+	// AccessToken is the token value.
+	AccessToken string `json:"access_token"`
+	// TokenType is the token type, e.g. `Bearer`.
+	TokenType string `json:"token_type,omitempty"`
+	// Expiry is when the token expires.
+	// A zero value means the token does not expire.
+	Expiry time.Time `json:"expiry,omitempty"`
+}
+
+// TypeOrDefault returns the token type or a default.
+// Bearer token types are normalized to `Bearer` as some servers reject other casings.
+func (t Token) TypeOrDefault() string {
+	if t.TokenType == "" || strings.EqualFold(t.TokenType, TokenTypeBearer) {
+		return TokenTypeBearer
+	}
+	return t.TokenType
+}
+
+// HeaderValue returns the authorization header value for the token.
+func (t Token) HeaderValue() string {
+	return t.TypeOrDefault() + " " + t.AccessToken
+}
+
+// IsExpired returns if the token is expired, or will expire within a given window, as of a given time.
+func (t Token) IsExpired(asOf time.Time, window time.Duration) bool {
+	if t.Expiry.IsZero() {
+		return false
+	}
+	return !asOf.Add(window).Before(t.Expiry)
+}
+
+// IsValid returns if the token is set and not expired as of a given time.
+func (t *Token) IsValid(asOf time.Time) bool {
+	return t != nil && t.AccessToken != "" && !t.IsExpired(asOf, 0)
+}
+
+// TokenSource is a type that returns tokens.
+type TokenSource interface {
+	Token(context.Context) (*Token, error)
+}
+
+// TokenInvalidator is a token source that can discard a token that was rejected by a server.
+type TokenInvalidator interface {
+	Invalidate(*Token)
+}
+
+// TokenSourceFunc is a function that implements TokenSource.
+type TokenSourceFunc func(context.Context) (*Token, error)
+
+// Token implements TokenSource.
+func (tsf TokenSourceFunc) Token(ctx context.Context) (*Token, error) {
+	return tsf(ctx)
+}
+
+// StaticTokenSource returns a token source that always returns the same token.
+func StaticTokenSource(token Token) TokenSource {
+	return TokenSourceFunc(func(_ context.Context) (*Token, error) {
+		return &token, nil
+	})
+}
@@ -0,0 +1,41 @@
+package r2
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/blend/go-sdk/assert"
+)
+
+func TestTokenHeaderValue(t *testing.T) {
+	assert := assert.New(t)
+
+	assert.Equal("Bearer foo", Token{AccessToken: "foo"}.HeaderValue())
+	assert.Equal("Bearer foo", Token{AccessToken: "foo", TokenType: "bearer"}.HeaderValue())
+	assert.Equal("MAC foo", Token{AccessToken: "foo", TokenType: "MAC"}.HeaderValue())
+}
+
+func TestTokenIsValid(t *testing.T) {
+	assert := assert.New(t)
+
+	now := time.Now().UTC()
+	var unset *Token
+	assert.False(unset.IsValid(now))
+	assert.False((&Token{}).IsValid(now))
+	assert.True((&Token{AccessToken: "foo"}).IsValid(now))
+	assert.True((&Token{AccessToken: "foo", Expiry: now.Add(time.Hour)}).IsValid(now))
+	assert.False((&Token{AccessToken: "foo", Expiry: now.Add(-time.Second)}).IsValid(now))
+
+	token := Token{AccessToken: "foo", Expiry: now.Add(30 * time.Second)}
+	assert.False(token.IsExpired(now, 0))
+	assert.True(token.IsExpired(now, time.Minute))
+}
+
+func TestStaticTokenSource(t *testing.T) {
+	assert := assert.New(t)
+
+	token, err := StaticTokenSource(Token{AccessToken: "foo"}).Token(context.Background())
+	assert.Nil(err)
+	assert.Equal("foo", token.AccessToken)
+}
@@ -0,0 +1,6 @@
+package r2
+
+import "net/http"
+
+// TransportWrapper wraps the round tripper a request is sent with.
+type TransportWrapper func(http.RoundTripper) http.RoundTripper
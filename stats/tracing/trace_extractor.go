@@ -0,0 +1,71 @@
+package tracing
+
+import (
+	"context"
+	"strconv"
+
+	opentracing "github.com/opentracing/opentracing-go"
+
+	"github.com/blend/go-sdk/logger"
+)
+
+// SpanContextExtractor returns the trace and span id for a span context,
+// and if it supports the span context's tracer implementation.
+type SpanContextExtractor func(opentracing.SpanContext) (traceID, spanID string, ok bool)
+
+// DefaultSpanContextExtractors are the span context extractors tried after any given extractors.
+var DefaultSpanContextExtractors = []SpanContextExtractor{
+	Uint64SpanContextExtractor,
+	StringSpanContextExtractor,
+}
+
+// TraceExtractor returns a logger trace extractor that reads the trace and span id from the span in the context.
+// Each span context extractor is tried in order, followed by the `DefaultSpanContextExtractors`, and the first
+// that supports the span context is used.
+//
+// To add trace and span ids to log events, set it on a logger with:
+//
+//	log := logger.MustNew(logger.OptTraceExtractor(tracing.TraceExtractor()))
+func TraceExtractor(extractors ...SpanContextExtractor) logger.TraceExtractor {
+	extractors = append(append([]SpanContextExtractor(nil), extractors...), DefaultSpanContextExtractors...)
+	return func(ctx context.Context) (traceID, spanID string) {
+		span := opentracing.SpanFromContext(ctx)
+		if span == nil {
+			return "", ""
+		}
+		spanContext := span.Context()
+		if spanContext == nil {
+			return "", ""
+		}
+		for _, extractor := range extractors {
+			if traceID, spanID, ok := extractor(spanContext); ok {
+				return traceID, spanID
+			}
+		}
+		return "", ""
+	}
+}
+
+// Uint64SpanContextExtractor extracts ids from span contexts with `uint64` ids, e.g. datadog span contexts.
+func Uint64SpanContextExtractor(spanContext opentracing.SpanContext) (traceID, spanID string, ok bool) {
+	typed, ok := spanContext.(interface {
+		TraceID() uint64
+		SpanID() uint64
+	})
+	if !ok {
+		return "", "", false
+	}
+	return strconv.FormatUint(typed.TraceID(), 10), strconv.FormatUint(typed.SpanID(), 10), true
+}
+
+// StringSpanContextExtractor extracts ids from span contexts with `string` ids.
+func StringSpanContextExtractor(spanContext opentracing.SpanContext) (traceID, spanID string, ok bool) {
+	typed, ok := spanContext.(interface {
+		TraceID() string
+		SpanID() string
+	})
+	if !ok {
+		return "", "", false
+	}
+	return typed.TraceID(), typed.SpanID(), true
+}
@@ -0,0 +1,105 @@
+package tracing
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"strconv"
+	"testing"
+
+	opentracing "github.com/opentracing/opentracing-go"
+	"github.com/opentracing/opentracing-go/mocktracer"
+
+	"github.com/blend/go-sdk/assert"
+	"github.com/blend/go-sdk/logger"
+)
+
+type uint64SpanContext struct {
+	mocktracer.MockSpanContext
+}
+
+func (sc uint64SpanContext) TraceID() uint64 { return 1234 }
+func (sc uint64SpanContext) SpanID() uint64  { return 5678 }
+
+type stringSpanContext struct {
+	mocktracer.MockSpanContext
+}
+
+func (sc stringSpanContext) TraceID() string { return "abcd" }
+func (sc stringSpanContext) SpanID() string  { return "ef01" }
+
+func mockSpanContextExtractor(spanContext opentracing.SpanContext) (traceID, spanID string, ok bool) {
+	typed, ok := spanContext.(mocktracer.MockSpanContext)
+	if !ok {
+		return "", "", false
+	}
+	return strconv.Itoa(typed.TraceID), strconv.Itoa(typed.SpanID), true
+}
+
+func TestSpanContextExtractors(t *testing.T) {
+	assert := assert.New(t)
+
+	traceID, spanID, ok := Uint64SpanContextExtractor(uint64SpanContext{})
+	assert.True(ok)
+	assert.Equal("1234", traceID)
+	assert.Equal("5678", spanID)
+	_, _, ok = Uint64SpanContextExtractor(stringSpanContext{})
+	assert.False(ok)
+
+	traceID, spanID, ok = StringSpanContextExtractor(stringSpanContext{})
+	assert.True(ok)
+	assert.Equal("abcd", traceID)
+	assert.Equal("ef01", spanID)
+	_, _, ok = StringSpanContextExtractor(mocktracer.MockSpanContext{})
+	assert.False(ok)
+}
+
+func TestTraceExtractor(t *testing.T) {
+	assert := assert.New(t)
+
+	extractor := TraceExtractor(mockSpanContextExtractor)
+	traceID, spanID := extractor(context.Background())
+	assert.Empty(traceID)
+	assert.Empty(spanID)
+
+	span, ctx := StartSpanFromContext(context.Background(), mocktracer.New(), "test.operation")
+	defer span.Finish()
+	mockContext := span.Context().(mocktracer.MockSpanContext)
+
+	traceID, spanID = extractor(ctx)
+	assert.Equal(strconv.Itoa(mockContext.TraceID), traceID)
+	assert.Equal(strconv.Itoa(mockContext.SpanID), spanID)
+
+	// the mock span context isn't supported by the defaults.
+	traceID, _ = TraceExtractor()(ctx)
+	assert.Empty(traceID)
+
+	// the given extractors are not appended to.
+	extractors := make([]SpanContextExtractor, 1, 4)
+	extractors[0] = mockSpanContextExtractor
+	TraceExtractor(extractors...)
+	assert.Nil(extractors[:2][1])
+}
+
+func TestTraceExtractorLogger(t *testing.T) {
+	assert := assert.New(t)
+
+	output := new(bytes.Buffer)
+	log := logger.MustNew(
+		logger.OptOutput(output),
+		logger.OptJSON(),
+		logger.OptTraceExtractor(TraceExtractor(mockSpanContextExtractor)),
+	)
+	defer log.Close()
+
+	span, ctx := StartSpanFromContext(context.Background(), mocktracer.New(), "test.operation")
+	defer span.Finish()
+	mockContext := span.Context().(mocktracer.MockSpanContext)
+
+	log.WithContext(ctx).Infof("traced")
+
+	var fields map[string]interface{}
+	assert.Nil(json.Unmarshal(output.Bytes(), &fields))
+	assert.Equal(strconv.Itoa(mockContext.TraceID), fields[logger.FieldTraceID])
+	assert.Equal(strconv.Itoa(mockContext.SpanID), fields[logger.FieldSpanID])
+}
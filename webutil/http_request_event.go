@@ -14,6 +14,7 @@ var (
 	_ logger.Event        = (*HTTPRequestEvent)(nil)
 	_ logger.TextWritable = (*HTTPRequestEvent)(nil)
 	_ logger.JSONWritable = (*HTTPRequestEvent)(nil)
+	_ logger.Redactable   = (*HTTPRequestEvent)(nil)
 )
 
 // NewHTTPRequestEvent creates a new web request event.
@@ -87,3 +88,9 @@ func (e HTTPRequestEvent) Decompose() map[string]interface{} {
 		"userAgent": GetUserAgent(e.Request),
 	}
 }
+
+// Redact implements logger.Redactable.
+func (e HTTPRequestEvent) Redact(r *logger.Redactor) logger.Event {
+	e.Request = r.Request(e.Request)
+	return e
+}
@@ -57,3 +57,22 @@ func TestHTTPRequestEventListener(t *testing.T) {
 	listener(context.Background(), NewHTTPRequestEvent(nil))
 	assert.True(didCall)
 }
+
+func TestHTTPRequestEventRedact(t *testing.T) {
+	assert := assert.New(t)
+
+	redactor, err := logger.NewRedactor(logger.RedactionConfig{Enabled: true})
+	assert.Nil(err)
+
+	hre := NewHTTPRequestEvent(&http.Request{
+		Method: "GET",
+		URL:    &url.URL{Scheme: "https", Host: "localhost", Path: "/foo", RawQuery: "api_key=abcd"},
+		Header: http.Header{"Authorization": {"Bearer abcd"}},
+	})
+
+	redacted, ok := hre.Redact(redactor).(HTTPRequestEvent)
+	assert.True(ok)
+	assert.Equal("[REDACTED]", redacted.Request.Header.Get("Authorization"))
+	assert.Equal("api_key=%5BREDACTED%5D", redacted.Decompose()["query"])
+	assert.Equal("Bearer abcd", hre.Request.Header.Get("Authorization"))
+}
@@ -16,6 +16,7 @@ var (
 	_ logger.Event        = (*HTTPResponseEvent)(nil)
 	_ logger.TextWritable = (*HTTPResponseEvent)(nil)
 	_ logger.JSONWritable = (*HTTPResponseEvent)(nil)
+	_ logger.Redactable   = (*HTTPResponseEvent)(nil)
 )
 
 // NewHTTPResponseEvent is an event representing a response to an http request.
@@ -134,3 +135,10 @@ func (e HTTPResponseEvent) Decompose() map[string]interface{} {
 		"elapsed":         timeutil.Milliseconds(e.Elapsed),
 	}
 }
+
+// Redact implements logger.Redactable.
+func (e HTTPResponseEvent) Redact(r *logger.Redactor) logger.Event {
+	e.Request = r.Request(e.Request)
+	e.Header = r.Header(e.Header)
+	return e
+}
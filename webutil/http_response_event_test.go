@@ -64,3 +64,20 @@ func TestHTTPResponseEventListener(t *testing.T) {
 	listener(context.Background(), NewHTTPResponseEvent(nil))
 	assert.True(didCall)
 }
+
+func TestHTTPResponseEventRedact(t *testing.T) {
+	assert := assert.New(t)
+
+	redactor, err := logger.NewRedactor(logger.RedactionConfig{Enabled: true})
+	assert.Nil(err)
+
+	hre := NewHTTPResponseEvent(&http.Request{Method: "GET", URL: &url.URL{Path: "/foo"}, Header: http.Header{"Cookie": {"session=abcd"}}},
+		OptHTTPResponseHeader(http.Header{"Set-Cookie": {"session=abcd"}, "Content-Type": {"text/html"}}),
+	)
+
+	redacted, ok := hre.Redact(redactor).(HTTPResponseEvent)
+	assert.True(ok)
+	assert.Equal("[REDACTED]", redacted.Request.Header.Get("Cookie"))
+	assert.Equal("[REDACTED]", redacted.Header.Get("Set-Cookie"))
+	assert.Equal("text/html", redacted.Header.Get("Content-Type"))
+}